@@ -0,0 +1,185 @@
+package addresses
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"pandora-pay/config/config_coins"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const PAYMENT_REQUEST_URI_SCHEME = "pandora"
+
+type PaymentRequest struct {
+	Address   *Address `json:"address" msgpack:"address"`
+	Amount    uint64   `json:"amount,omitempty" msgpack:"amount,omitempty"`
+	Asset     []byte   `json:"asset,omitempty" msgpack:"asset,omitempty"`
+	PaymentID []byte   `json:"paymentID,omitempty" msgpack:"paymentID,omitempty"`
+	Memo      string   `json:"memo,omitempty" msgpack:"memo,omitempty"`
+	Expiry    uint64   `json:"expiry,omitempty" msgpack:"expiry,omitempty"` //unix timestamp in seconds
+}
+
+func IsPaymentRequestURI(input string) bool {
+	return len(input) > len(PAYMENT_REQUEST_URI_SCHEME) && strings.EqualFold(input[:len(PAYMENT_REQUEST_URI_SCHEME)+1], PAYMENT_REQUEST_URI_SCHEME+":")
+}
+
+func NewPaymentRequest(addressEncoded string, amount uint64, asset, paymentID []byte, memo string, expiry uint64) (*PaymentRequest, error) {
+
+	address, err := DecodeAddr(addressEncoded)
+	if err != nil {
+		return nil, err
+	}
+
+	request := &PaymentRequest{
+		Address: address,
+		Memo:    memo,
+		Expiry:  expiry,
+	}
+
+	if err = request.merge(amount, asset, paymentID); err != nil {
+		return nil, err
+	}
+
+	return request, request.Validate()
+}
+
+//merge moves the payment details integrated in the address into the request
+func (request *PaymentRequest) merge(amount uint64, asset, paymentID []byte) error {
+
+	address := *request.Address
+	request.Address = &address
+
+	if address.PaymentAmount > 0 && amount > 0 && address.PaymentAmount != amount {
+		return errors.New("Amount is not matching the integrated address")
+	}
+	if len(address.PaymentAsset) > 0 && len(asset) > 0 && !bytes.Equal(address.PaymentAsset, asset) {
+		return errors.New("Asset is not matching the integrated address")
+	}
+	if len(address.PaymentID) > 0 && len(paymentID) > 0 && !bytes.Equal(address.PaymentID, paymentID) {
+		return errors.New("PaymentID is not matching the integrated address")
+	}
+
+	request.Amount = amount
+	if address.PaymentAmount > 0 {
+		request.Amount = address.PaymentAmount
+	}
+	request.Asset = asset
+	if len(address.PaymentAsset) > 0 {
+		request.Asset = address.PaymentAsset
+	}
+	request.PaymentID = paymentID
+	if len(address.PaymentID) > 0 {
+		request.PaymentID = address.PaymentID
+	}
+
+	address.PaymentAmount = 0
+	address.PaymentAsset = nil
+	address.PaymentID = nil
+
+	return nil
+}
+
+func (request *PaymentRequest) Validate() error {
+	if request.Address == nil {
+		return errors.New("Address is missing")
+	}
+	if len(request.PaymentID) != 8 && len(request.PaymentID) != 0 {
+		return errors.New("Invalid PaymentID. It must be an 8 byte")
+	}
+	if len(request.Asset) != 0 && len(request.Asset) != config_coins.ASSET_LENGTH {
+		return errors.New("Invalid Asset size")
+	}
+	return nil
+}
+
+func (request *PaymentRequest) IsExpired() bool {
+	return request.Expiry > 0 && uint64(time.Now().Unix()) > request.Expiry
+}
+
+//GetIntegratedAddress returns the address having the amount, asset and paymentID integrated
+func (request *PaymentRequest) GetIntegratedAddress() *Address {
+	address := *request.Address
+	address.PaymentAmount = request.Amount
+	address.PaymentAsset = request.Asset
+	address.PaymentID = request.PaymentID
+	return &address
+}
+
+func (request *PaymentRequest) encode(address *Address, values url.Values) string {
+	if request.Memo != "" {
+		values.Set("memo", request.Memo)
+	}
+	if request.Expiry > 0 {
+		values.Set("expiry", strconv.FormatUint(request.Expiry, 10))
+	}
+
+	uri := PAYMENT_REQUEST_URI_SCHEME + ":" + address.EncodeAddr()
+	if len(values) > 0 {
+		uri += "?" + values.Encode()
+	}
+	return uri
+}
+
+func (request *PaymentRequest) EncodeURI() string {
+
+	values := url.Values{}
+	if request.Amount > 0 {
+		values.Set("amount", strconv.FormatUint(request.Amount, 10))
+	}
+	if len(request.Asset) > 0 {
+		values.Set("asset", hex.EncodeToString(request.Asset))
+	}
+	if len(request.PaymentID) > 0 {
+		values.Set("paymentId", hex.EncodeToString(request.PaymentID))
+	}
+
+	return request.encode(request.Address, values)
+}
+
+//EncodeQR returns a shorter URI in which the amount, asset and paymentID are integrated in the address
+func (request *PaymentRequest) EncodeQR() string {
+	return request.encode(request.GetIntegratedAddress(), url.Values{})
+}
+
+func DecodePaymentRequestURI(input string) (*PaymentRequest, error) {
+
+	if !IsPaymentRequestURI(input) {
+		return nil, errors.New("Invalid Payment Request URI scheme")
+	}
+
+	addressEncoded, query, _ := strings.Cut(input[len(PAYMENT_REQUEST_URI_SCHEME)+1:], "?")
+
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	var amount, expiry uint64
+	var asset, paymentID []byte
+
+	if str := values.Get("amount"); str != "" {
+		if amount, err = strconv.ParseUint(str, 10, 64); err != nil {
+			return nil, errors.New("Invalid amount")
+		}
+	}
+	if str := values.Get("asset"); str != "" {
+		if asset, err = hex.DecodeString(str); err != nil {
+			return nil, errors.New("Invalid asset")
+		}
+	}
+	if str := values.Get("paymentId"); str != "" {
+		if paymentID, err = hex.DecodeString(str); err != nil {
+			return nil, errors.New("Invalid paymentId")
+		}
+	}
+	if str := values.Get("expiry"); str != "" {
+		if expiry, err = strconv.ParseUint(str, 10, 64); err != nil {
+			return nil, errors.New("Invalid expiry")
+		}
+	}
+
+	return NewPaymentRequest(addressEncoded, amount, asset, paymentID, values.Get("memo"), expiry)
+}
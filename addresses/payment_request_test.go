@@ -0,0 +1,60 @@
+package addresses
+
+import (
+	"github.com/stretchr/testify/assert"
+	"pandora-pay/config/config_coins"
+	"pandora-pay/helpers"
+	"testing"
+)
+
+func TestPaymentRequest_EncodeURI(t *testing.T) {
+
+	privateKey := GenerateNewPrivateKey()
+	address, err := privateKey.GenerateAddress(false, nil, false, nil, 0, nil)
+	assert.NoError(t, err)
+
+	paymentID := helpers.RandomBytes(8)
+
+	request, err := NewPaymentRequest(address.EncodeAddr(), 1523, config_coins.NATIVE_ASSET_FULL, paymentID, "order #12 & more", 1700000000)
+	assert.NoError(t, err)
+
+	for _, uri := range []string{request.EncodeURI(), request.EncodeQR()} {
+
+		assert.True(t, IsPaymentRequestURI(uri))
+
+		decoded, err := DecodePaymentRequestURI(uri)
+		assert.NoError(t, err)
+		assert.Equal(t, decoded.Address.PublicKey, address.PublicKey)
+		assert.Equal(t, decoded.Address.PaymentAmount, uint64(0))
+		assert.Equal(t, decoded.Amount, uint64(1523))
+		assert.Equal(t, decoded.Asset, config_coins.NATIVE_ASSET_FULL)
+		assert.Equal(t, decoded.PaymentID, paymentID)
+		assert.Equal(t, decoded.Memo, "order #12 & more")
+		assert.Equal(t, decoded.Expiry, uint64(1700000000))
+		assert.True(t, decoded.IsExpired())
+	}
+
+	assert.Less(t, len(request.EncodeQR()), len(request.EncodeURI()))
+}
+
+func TestPaymentRequest_Decode(t *testing.T) {
+
+	privateKey := GenerateNewPrivateKey()
+	address, err := privateKey.GenerateAddress(false, nil, false, helpers.RandomBytes(8), 20, nil)
+	assert.NoError(t, err)
+
+	request, err := DecodePaymentRequestURI("PANDORA:" + address.EncodeAddr())
+	assert.NoError(t, err)
+	assert.Equal(t, request.Amount, uint64(20))
+	assert.Equal(t, request.PaymentID, address.PaymentID)
+	assert.False(t, request.IsExpired())
+
+	_, err = DecodePaymentRequestURI("pandora:" + address.EncodeAddr() + "?amount=21")
+	assert.Error(t, err, "amount conflicting with the integrated address should fail")
+
+	_, err = DecodePaymentRequestURI("pandora:" + address.EncodeAddr() + "?paymentId=0102")
+	assert.Error(t, err)
+
+	_, err = DecodePaymentRequestURI(address.EncodeAddr())
+	assert.Error(t, err)
+}
@@ -4,6 +4,7 @@ import (
 	"pandora-pay/address_balance_decryptor"
 	"pandora-pay/blockchain"
 	"pandora-pay/blockchain/forging"
+	"pandora-pay/blockchain/forging/forging_signer"
 	"pandora-pay/gui"
 	"pandora-pay/mempool"
 	"pandora-pay/network"
@@ -26,6 +27,7 @@ var (
 	Network                 *network.Network
 	TxsBuilder              *txs_builder.TxsBuilder
 	Testnet                 *testnet.Testnet
+	ForgingSignerServer     *forging_signer.SignerServer
 )
 
 func Close() {
@@ -34,4 +36,7 @@ func Close() {
 	Forging.Close()
 	Chain.Close()
 	Wallet.Close()
+	if ForgingSignerServer != nil {
+		ForgingSignerServer.Close()
+	}
 }
@@ -8,6 +8,7 @@ import (
 	"math/big"
 	"pandora-pay/blockchain/blockchain_sync"
 	"pandora-pay/blockchain/blockchain_types"
+	"pandora-pay/blockchain/blocks/block"
 	"pandora-pay/blockchain/blocks/block/difficulty"
 	"pandora-pay/blockchain/blocks/block_complete"
 	"pandora-pay/blockchain/data_storage"
@@ -19,6 +20,7 @@ import (
 	"pandora-pay/blockchain/transactions/transaction/transaction_zether/transaction_zether_payload/transaction_zether_payload_extra"
 	"pandora-pay/blockchain/transactions/transaction/transaction_zether/transaction_zether_payload/transaction_zether_payload_script"
 	"pandora-pay/config"
+	"pandora-pay/config/config_checkpoints"
 	"pandora-pay/config/config_coins"
 	"pandora-pay/config/config_stake"
 	"pandora-pay/gui"
@@ -51,6 +53,7 @@ type Blockchain struct {
 	UpdateSocketsSubscriptionsTransactions  *multicast.MulticastChannel[[]*blockchain_types.BlockchainTransactionUpdate]
 	UpdateSocketsSubscriptionsNotifications *multicast.MulticastChannel[*data_storage.DataStorage]
 	NextBlockCreatedCn                      chan *forging_block_work.ForgingWork
+	StateRoot                               *generics.Value[*data_storage.StateRoot] //state root committed by the last block, nil before the stateRoot upgrade
 }
 
 func (chain *Blockchain) validateBlocks(blocksComplete []*block_complete.BlockComplete) (err error) {
@@ -124,7 +127,11 @@ func (chain *Blockchain) AddBlocks(blocksComplete []*block_complete.BlockComplet
 	removedBlocksHeights := []uint64{}
 	removedBlocksTransactionsCount := uint64(0)
 
+	var removedBlocksHashes [][]byte //ordered by height
+	var orphanedTxsHashes [][]byte   //ordered list
+
 	var dataStorage *data_storage.DataStorage
+	var insertedStateRoot *data_storage.StateRoot
 
 	err = func() (err error) {
 
@@ -167,6 +174,10 @@ func (chain *Blockchain) AddBlocks(blocksComplete []*block_complete.BlockComplet
 			firstBlockComplete := blocksComplete[0]
 			if firstBlockComplete.Block.Height < newChainData.Height {
 
+				if err = config_checkpoints.ValidateReorg(newChainData.Height, firstBlockComplete.Block.Height); err != nil {
+					return
+				}
+
 				index := newChainData.Height - 1
 				for {
 
@@ -174,6 +185,12 @@ func (chain *Blockchain) AddBlocks(blocksComplete []*block_complete.BlockComplet
 					copy(removedBlocksHeights[1:], removedBlocksHeights)
 					removedBlocksHeights[0] = index
 
+					var removedBlockHash []byte
+					if removedBlockHash, err = chain.LoadBlockHash(writer, index); err != nil {
+						return
+					}
+					removedBlocksHashes = append([][]byte{helpers.CloneBytes(removedBlockHash)}, removedBlocksHashes...)
+
 					if allTransactionsChanges, err = chain.removeBlockComplete(writer, index, removedTxHashes, allTransactionsChanges, dataStorage); err != nil {
 						return
 					}
@@ -217,13 +234,17 @@ func (chain *Blockchain) AddBlocks(blocksComplete []*block_complete.BlockComplet
 
 			err = func() (err error) {
 
-				for _, blkComplete := range blocksComplete {
+				for i, blkComplete := range blocksComplete {
 
 					//check block height
 					if blkComplete.Block.Height != newChainData.Height {
 						return errors.New("Block Height is not right!")
 					}
 
+					if err = config_checkpoints.VerifyBlockHash(blkComplete.Block.Height, blkComplete.Bloom.Hash); err != nil {
+						return
+					}
+
 					//check existance of a tx with payloads
 					var foundStakingRewardTx *transaction.Transaction
 					for index, tx := range blkComplete.Txs {
@@ -277,6 +298,26 @@ func (chain *Blockchain) AddBlocks(blocksComplete []*block_complete.BlockComplet
 						return fmt.Errorf("Payload Reward %d is bigger than it should be %d", foundStakingRewardTxBase.Payloads[1].Extra.(*transaction_zether_payload_extra.TransactionZetherPayloadExtraStakingReward).Reward, finalForgerReward)
 					}
 
+					//the state root is computed before the block changes the committed state
+					var stateRoot *data_storage.StateRoot
+					if blkComplete.Block.Version >= block.BLOCK_VERSION_STATE_ROOT {
+
+						//only the accounts of the last block are kept to serve proofs
+						var keepAssets map[string]bool
+						if config.SEED_WALLET_NODES_INFO && i == len(blocksComplete)-1 {
+							keepAssets = config.STATE_ROOT_ASSETS
+						}
+
+						if stateRoot, err = dataStorage.ComputeStateRoot(keepAssets); err != nil {
+							return
+						}
+						if !bytes.Equal(stateRoot.Root, blkComplete.Block.StateRoot) {
+							return errors.New("Block State Root is not matching")
+						}
+						stateRoot.ChainHeight = blkComplete.Block.Height
+						stateRoot.ChainHash = blkComplete.Block.Bloom.Hash
+					}
+
 					//increase supply
 					var ast *asset.Asset
 					if ast, err = dataStorage.Asts.Get(string(config_coins.NATIVE_ASSET_FULL)); err != nil {
@@ -320,6 +361,10 @@ func (chain *Blockchain) AddBlocks(blocksComplete []*block_complete.BlockComplet
 						return errors.New("Error Processing Pending Stakes: " + err.Error())
 					}
 
+					if err = dataStorage.ProcessPendingUnstakes(blkComplete.Height); err != nil {
+						return errors.New("Error Processing Pending Unstakes: " + err.Error())
+					}
+
 					if err = dataStorage.ProcessConditionalPayments(blkComplete.Height); err != nil {
 						return errors.New("Error Processing Pending Future: " + err.Error())
 					}
@@ -359,6 +404,7 @@ func (chain *Blockchain) AddBlocks(blocksComplete []*block_complete.BlockComplet
 						return
 					}
 
+					insertedStateRoot = stateRoot
 					savedBlock = true
 				}
 
@@ -425,6 +471,7 @@ func (chain *Blockchain) AddBlocks(blocksComplete []*block_complete.BlockComplet
 				for _, change := range allTransactionsChanges {
 					if !change.Inserted && removedTxHashes[change.TxHashStr] != nil && insertedTxs[change.TxHashStr] == nil {
 						removedTxsList[removedCount] = writer.Get("tx:" + change.TxHashStr) //required because the garbage collector sometimes it deletes the underlying buffers
+						orphanedTxsHashes = append(orphanedTxsHashes, helpers.CloneBytes(change.TxHash))
 						writer.Delete("tx:" + change.TxHashStr)
 						writer.Delete("txHash:" + change.TxHashStr)
 						writer.Delete("txBlock:" + change.TxHashStr)
@@ -467,6 +514,7 @@ func (chain *Blockchain) AddBlocks(blocksComplete []*block_complete.BlockComplet
 
 	if err == nil {
 		kernelHash = newChainData.KernelHash
+		chain.StateRoot.Store(insertedStateRoot)
 		chain.ChainData.Store(newChainData)
 		chain.mempool.ContinueProcessingCn <- mempool.CONTINUE_PROCESSING_NO_ERROR
 	} else {
@@ -488,6 +536,18 @@ func (chain *Blockchain) AddBlocks(blocksComplete []*block_complete.BlockComplet
 		update.insertedTxsList = insertedTxsList
 		update.insertedBlocks = insertedBlocks
 		update.allTransactionsChanges = allTransactionsChanges
+
+		if len(removedBlocksHashes) > 0 {
+			update.reorg = &blockchain_types.BlockchainReorg{
+				CommonAncestorHeight: insertedBlocks[0].Block.Height - 1,
+				RemovedBlocks:        removedBlocksHashes,
+				AddedBlocks:          make([][]byte, len(insertedBlocks)),
+				OrphanedTxs:          orphanedTxsHashes,
+			}
+			for i, blkComplete := range insertedBlocks {
+				update.reorg.AddedBlocks[i] = blkComplete.Block.Bloom.Hash
+			}
+		}
 	}
 
 	chain.updatesQueue.updatesCn <- update
@@ -514,6 +574,7 @@ func CreateBlockchain(mempool *mempool.Mempool, txsValidator *txs_validator.TxsV
 		multicast.NewMulticastChannel[[]*blockchain_types.BlockchainTransactionUpdate](),
 		multicast.NewMulticastChannel[*data_storage.DataStorage](),
 		make(chan *forging_block_work.ForgingWork),
+		&generics.Value[*data_storage.StateRoot]{},
 	}
 
 	chain.updatesQueue.chain = chain
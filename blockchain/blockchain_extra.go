@@ -1,6 +1,7 @@
 package blockchain
 
 import (
+	"bytes"
 	"errors"
 	"math/big"
 	"pandora-pay/addresses"
@@ -18,6 +19,7 @@ import (
 	"pandora-pay/config"
 	"pandora-pay/config/config_coins"
 	"pandora-pay/config/config_forging"
+	"pandora-pay/config/config_forks"
 	"pandora-pay/config/config_stake"
 	"pandora-pay/cryptography"
 	"pandora-pay/gui"
@@ -59,13 +61,17 @@ func (chain *Blockchain) initializeNewChain(chainData *BlockchainData, dataStora
 
 	gui.GUI.Info("Initializing New Chain")
 
-	supply := uint64(0)
+	supplies := make(map[string]uint64)
 
 	for _, airdrop := range genesis.GenesisData.AirDrops {
 
+		assetId := airdrop.GetAsset()
+
+		supply := supplies[string(assetId)]
 		if err = helpers.SafeUint64Add(&supply, airdrop.Amount); err != nil {
 			return
 		}
+		supplies[string(assetId)] = supply
 
 		var addr *addresses.Address
 		addr, err = addresses.DecodeAddr(airdrop.Address)
@@ -80,14 +86,21 @@ func (chain *Blockchain) initializeNewChain(chainData *BlockchainData, dataStora
 			return errors.New("Registration verification is false")
 		}
 
-		if _, err = dataStorage.CreateRegistration(addr.PublicKey, addr.Staked, addr.SpendPublicKey); err != nil {
+		//the same address can receive multiple assets
+		var isReg bool
+		if isReg, err = dataStorage.Regs.Exists(string(addr.PublicKey)); err != nil {
 			return
 		}
+		if !isReg {
+			if _, err = dataStorage.CreateRegistration(addr.PublicKey, addr.Staked, addr.SpendPublicKey); err != nil {
+				return
+			}
+		}
 
 		var accs *accounts.Accounts
 		var acc *account.Account
 
-		if accs, acc, err = dataStorage.CreateAccount(config_coins.NATIVE_ASSET_FULL, addr.PublicKey, false); err != nil {
+		if accs, acc, err = dataStorage.CreateAccount(assetId, addr.PublicKey, false); err != nil {
 			return
 		}
 		acc.Balance.AddBalanceUint(airdrop.Amount)
@@ -111,7 +124,7 @@ func (chain *Blockchain) initializeNewChain(chainData *BlockchainData, dataStora
 		false,
 		byte(config_coins.DECIMAL_SEPARATOR),
 		config_coins.MAX_SUPPLY_COINS_UNITS,
-		supply,
+		supplies[string(config_coins.NATIVE_ASSET_FULL)],
 		config_coins.BURN_PUBLIC_KEY,
 		config_coins.BURN_PUBLIC_KEY,
 		config_coins.NATIVE_ASSET_NAME,
@@ -125,6 +138,19 @@ func (chain *Blockchain) initializeNewChain(chainData *BlockchainData, dataStora
 		return
 	}
 
+	for _, genesisAsset := range genesis.GenesisData.Assets {
+
+		newAst := *genesisAsset.Asset
+		newAst.Supply = supplies[string(genesisAsset.AssetId)]
+		if newAst.Supply > newAst.MaxSupply {
+			return errors.New("Genesis asset supply exceeded max supply")
+		}
+
+		if err = dataStorage.Asts.CreateAsset(genesisAsset.AssetId, &newAst); err != nil {
+			return
+		}
+	}
+
 	if err = dataStorage.CommitChanges(); err != nil {
 		return
 	}
@@ -165,6 +191,24 @@ func (chain *Blockchain) init() (*BlockchainData, error) {
 	return chainData, nil
 }
 
+//computeStateRoot returns the state root of the committed chain, which must still be chainHash
+func (chain *Blockchain) computeStateRoot(chainHash []byte) (root []byte, err error) {
+	err = store.StoreBlockchain.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+
+		if !bytes.Equal(reader.Get("chainHash"), chainHash) {
+			return errors.New("Chain changed meanwhile")
+		}
+
+		var stateRoot *data_storage.StateRoot
+		if stateRoot, err = data_storage.NewDataStorage(reader).ComputeStateRoot(nil); err != nil {
+			return
+		}
+		root = stateRoot.Root
+		return
+	})
+	return
+}
+
 func (chain *Blockchain) createNextBlockForForging(chainData *BlockchainData, newWork bool) {
 
 	if config.CONSENSUS != config.CONSENSUS_TYPE_FULL {
@@ -199,7 +243,7 @@ func (chain *Blockchain) createNextBlockForForging(chainData *BlockchainData, ne
 		} else {
 			blk = &block.Block{
 				BlockHeader: &block.BlockHeader{
-					Version: 0,
+					Version: config_forks.GetRules(chainData.Height).BlockVersion,
 					Height:  chainData.Height,
 				},
 				MerkleHash:     cryptography.SHA3([]byte{}),
@@ -207,6 +251,12 @@ func (chain *Blockchain) createNextBlockForForging(chainData *BlockchainData, ne
 				PrevKernelHash: chainData.KernelHash,
 				Timestamp:      chainData.Timestamp,
 			}
+			if blk.Version >= block.BLOCK_VERSION_STATE_ROOT {
+				if blk.StateRoot, err = chain.computeStateRoot(chainData.Hash); err != nil {
+					gui.GUI.Error("Error computing the state root", err)
+					return
+				}
+			}
 		}
 
 		blk.StakingNonce = make([]byte, 32)
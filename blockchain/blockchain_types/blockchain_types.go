@@ -41,6 +41,15 @@ type BlockchainUpdates struct {
 	Registrations  *registrations.Registrations
 	BlockHeight    uint64
 	BlockHash      []byte
+	Reorg          *BlockchainReorg //nil when the update only extended the chain
+}
+
+//BlockchainReorg describes a fork switch. Blocks are ordered by height and OrphanedTxs are the txs of the removed blocks that were not included again
+type BlockchainReorg struct {
+	CommonAncestorHeight uint64   `json:"commonAncestorHeight" msgpack:"commonAncestorHeight"`
+	RemovedBlocks        [][]byte `json:"removedBlocks" msgpack:"removedBlocks"`
+	AddedBlocks          [][]byte `json:"addedBlocks" msgpack:"addedBlocks"`
+	OrphanedTxs          [][]byte `json:"orphanedTxs" msgpack:"orphanedTxs"`
 }
 
 type BlockchainSolutionAnswer struct {
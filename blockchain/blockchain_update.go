@@ -30,6 +30,7 @@ type BlockchainUpdate struct {
 	insertedTxs            map[string]*transaction.Transaction
 	insertedTxsList        []*transaction.Transaction
 	insertedBlocks         []*block_complete.BlockComplete
+	reorg                  *blockchain_types.BlockchainReorg
 	calledByForging        bool
 	exceptSocketUUID       advanced_connection_types.UUID
 }
@@ -86,6 +87,7 @@ func (queue *BlockchainUpdatesQueue) executeUpdate(update *BlockchainUpdate) (er
 		update.dataStorage.Regs,
 		update.newChainData.Height,
 		update.newChainData.Hash,
+		update.reorg,
 	})
 
 	chainSyncData := queue.chain.Sync.AddBlocksChanged(uint32(len(update.insertedBlocks)), true)
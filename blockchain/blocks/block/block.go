@@ -1,11 +1,15 @@
 package block
 
 import (
+	"errors"
 	"pandora-pay/cryptography"
 	"pandora-pay/helpers"
 	"pandora-pay/helpers/advanced_buffers"
 )
 
+//BLOCK_VERSION_STATE_ROOT blocks commit the accounts state root
+const BLOCK_VERSION_STATE_ROOT = 1
+
 type Block struct {
 	*BlockHeader
 	MerkleHash     []byte      `json:"merkleHash" msgpack:"merkleHash"`          //32 byte
@@ -13,7 +17,8 @@ type Block struct {
 	PrevKernelHash []byte      `json:"prevKernelHash"  msgpack:"prevKernelHash"` //32 byte
 	Timestamp      uint64      `json:"timestamp" msgpack:"timestamp"`
 	StakingAmount  uint64      `json:"stakingAmount" msgpack:"stakingAmount"`
-	StakingNonce   []byte      `json:"stakingNonce" msgpack:"stakingNonce"` // 33 byte public key can also be found into the accounts tree
+	StakingNonce   []byte      `json:"stakingNonce" msgpack:"stakingNonce"`               // 33 byte public key can also be found into the accounts tree
+	StateRoot      []byte      `json:"stateRoot,omitempty" msgpack:"stateRoot,omitempty"` //32 byte, the accounts state root of the chain extended by the block
 	Bloom          *BlockBloom `json:"bloom" msgpack:"bloom"`
 }
 
@@ -28,6 +33,14 @@ func (blk *Block) Validate() error {
 		return err
 	}
 
+	if blk.Version >= BLOCK_VERSION_STATE_ROOT {
+		if len(blk.StateRoot) != cryptography.HashSize {
+			return errors.New("Block State Root is invalid")
+		}
+	} else if len(blk.StateRoot) != 0 {
+		return errors.New("Block can not have a State Root")
+	}
+
 	return nil
 }
 
@@ -70,6 +83,10 @@ func (blk *Block) AdvancedSerialization(w *advanced_buffers.BufferWriter, kernel
 
 	w.Write(blk.StakingNonce)
 
+	if !kernelHash && blk.Version >= BLOCK_VERSION_STATE_ROOT {
+		w.Write(blk.StateRoot)
+	}
+
 }
 
 func (blk *Block) SerializeForForging(w *advanced_buffers.BufferWriter) {
@@ -111,6 +128,11 @@ func (blk *Block) Deserialize(r *advanced_buffers.BufferReader) (err error) {
 	if blk.StakingNonce, err = r.ReadBytes(32); err != nil {
 		return
 	}
+	if blk.Version >= BLOCK_VERSION_STATE_ROOT {
+		if blk.StateRoot, err = r.ReadHash(); err != nil {
+			return
+		}
+	}
 
 	serialized := r.Buf[first:r.Position]
 	blk.BloomSerializedNow(serialized)
@@ -2,6 +2,7 @@ package block
 
 import (
 	"errors"
+	"pandora-pay/config/config_forks"
 	"pandora-pay/helpers/advanced_buffers"
 )
 
@@ -11,8 +12,8 @@ type BlockHeader struct {
 }
 
 func (blockHeader *BlockHeader) Validate() error {
-	if blockHeader.Version != 0 {
-		return errors.New("Invalid Block")
+	if blockHeader.Version != config_forks.GetRules(blockHeader.Height).BlockVersion {
+		return errors.New("Invalid Block Version")
 	}
 	return nil
 }
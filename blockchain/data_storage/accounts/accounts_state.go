@@ -0,0 +1,234 @@
+package accounts
+
+import (
+	"bytes"
+	"errors"
+	"pandora-pay/cryptography"
+	"pandora-pay/cryptography/merkle_tree"
+	"pandora-pay/helpers"
+	"pandora-pay/helpers/advanced_buffers"
+	"pandora-pay/helpers/generics"
+	"pandora-pay/store/hash_map"
+	"sort"
+)
+
+//AccountsState is a snapshot of all the accounts of an asset (or of all the registrations) sorted by public key. The root commits to every element and to their number
+type AccountsState struct {
+	Keys       [][]byte
+	Serialized [][]byte
+	Leaves     [][]byte
+	MerkleRoot []byte
+	Root       []byte
+}
+
+type AccountStateLeaf struct {
+	PublicKey  []byte   `json:"publicKey" msgpack:"publicKey"`
+	Serialized []byte   `json:"serialized" msgpack:"serialized"`
+	Index      uint64   `json:"index" msgpack:"index"`
+	Proof      [][]byte `json:"proof" msgpack:"proof"`
+}
+
+//AccountStateProof proves an account against the state root. A missing account is proven by its two neighbours
+type AccountStateProof struct {
+	Count      uint64            `json:"count" msgpack:"count"`
+	MerkleRoot []byte            `json:"merkleRoot" msgpack:"merkleRoot"`
+	Leaf       *AccountStateLeaf `json:"leaf,omitempty" msgpack:"leaf,omitempty"`
+	Left       *AccountStateLeaf `json:"left,omitempty" msgpack:"left,omitempty"`
+	Right      *AccountStateLeaf `json:"right,omitempty" msgpack:"right,omitempty"`
+}
+
+//AssetsStateProof proves the accounts state root of an asset and the registrations state root against the state root committed by a block
+type AssetsStateProof struct {
+	RegistrationsRoot []byte   `json:"registrationsRoot" msgpack:"registrationsRoot"`
+	Count             uint64   `json:"count" msgpack:"count"`
+	MerkleRoot        []byte   `json:"merkleRoot" msgpack:"merkleRoot"`
+	Index             uint64   `json:"index" msgpack:"index"`
+	Proof             [][]byte `json:"proof" msgpack:"proof"`
+}
+
+func ComputeAccountStateLeaf(publicKey, serialized []byte) []byte {
+	return cryptography.SHA3(append(append([]byte{}, publicKey...), serialized...))
+}
+
+func ComputeAccountsStateRoot(count uint64, merkleRoot []byte) []byte {
+	w := advanced_buffers.NewBufferWriter()
+	w.WriteUvarint(count)
+	w.Write(merkleRoot)
+	return cryptography.SHA3(w.Bytes())
+}
+
+func ComputeAssetStateLeaf(asset, accountsRoot []byte) []byte {
+	return cryptography.SHA3(append(append([]byte{}, asset...), accountsRoot...))
+}
+
+//ComputeStateRoot returns the root committed by the blocks. It commits to the registrations and to the accounts of every asset
+func ComputeStateRoot(registrationsRoot []byte, assetsCount uint64, assetsMerkleRoot []byte) []byte {
+	w := advanced_buffers.NewBufferWriter()
+	w.Write(registrationsRoot)
+	w.WriteUvarint(assetsCount)
+	w.Write(assetsMerkleRoot)
+	return cryptography.SHA3(w.Bytes())
+}
+
+//ComputeState should be called only on committed data
+func (accounts *Accounts) ComputeState() (*AccountsState, error) {
+	return ComputeHashMapState(accounts.HashMap)
+}
+
+//ComputeHashMapState commits to all the elements of an indexable hash map, like the accounts of an asset or the registrations. It should be called only on committed data
+func ComputeHashMapState[T hash_map.HashMapElementSerializableInterface](hashMap *hash_map.HashMap[T]) (*AccountsState, error) {
+
+	state := &AccountsState{
+		Keys: make([][]byte, hashMap.Count),
+	}
+
+	for i := uint64(0); i < hashMap.Count; i++ {
+		key, err := hashMap.GetKeyByIndex(i)
+		if err != nil {
+			return nil, err
+		}
+		state.Keys[i] = append([]byte{}, key...)
+	}
+
+	sort.Slice(state.Keys, func(i, j int) bool {
+		return bytes.Compare(state.Keys[i], state.Keys[j]) < 0
+	})
+
+	state.Serialized = make([][]byte, len(state.Keys))
+	state.Leaves = make([][]byte, len(state.Keys))
+
+	for i, key := range state.Keys {
+		element, err := hashMap.Get(string(key))
+		if err != nil {
+			return nil, err
+		}
+		if generics.IsZero(element) {
+			return nil, errors.New("Element from the list was not found")
+		}
+		state.Serialized[i] = helpers.SerializeToBytes(element)
+		state.Leaves[i] = ComputeAccountStateLeaf(key, state.Serialized[i])
+	}
+
+	if len(state.Leaves) > 0 {
+		state.MerkleRoot = merkle_tree.MerkleRoot(state.Leaves)
+	}
+	state.Root = ComputeAccountsStateRoot(uint64(len(state.Leaves)), state.MerkleRoot)
+
+	return state, nil
+}
+
+func (state *AccountsState) getLeaf(index int) (*AccountStateLeaf, error) {
+	proof, err := merkle_tree.MerkleProof(state.Leaves, index)
+	if err != nil {
+		return nil, err
+	}
+	return &AccountStateLeaf{state.Keys[index], state.Serialized[index], uint64(index), proof}, nil
+}
+
+//GetProof returns the serialized account (nil if it doesn't exist) and its proof
+func (state *AccountsState) GetProof(publicKey []byte) (serialized []byte, proof *AccountStateProof, err error) {
+
+	proof = &AccountStateProof{
+		Count:      uint64(len(state.Keys)),
+		MerkleRoot: state.MerkleRoot,
+	}
+
+	index := sort.Search(len(state.Keys), func(i int) bool {
+		return bytes.Compare(state.Keys[i], publicKey) >= 0
+	})
+
+	if index < len(state.Keys) && bytes.Equal(state.Keys[index], publicKey) {
+		if proof.Leaf, err = state.getLeaf(index); err != nil {
+			return
+		}
+		return state.Serialized[index], proof, nil
+	}
+
+	if index > 0 {
+		if proof.Left, err = state.getLeaf(index - 1); err != nil {
+			return
+		}
+	}
+	if index < len(state.Keys) {
+		if proof.Right, err = state.getLeaf(index); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+func (proof *AccountStateProof) verifyLeaf(leaf *AccountStateLeaf) bool {
+	if proof.Count == 0 || leaf.Index >= proof.Count {
+		return false
+	}
+	return merkle_tree.VerifyMerkleProof(proof.MerkleRoot, ComputeAccountStateLeaf(leaf.PublicKey, leaf.Serialized), int(leaf.Index), int(proof.Count), leaf.Proof)
+}
+
+//Verify checks that the serialized account (nil when missing) is the one committed in the state root
+func (proof *AccountStateProof) Verify(root, publicKey, serialized []byte) error {
+
+	if !bytes.Equal(ComputeAccountsStateRoot(proof.Count, proof.MerkleRoot), root) {
+		return errors.New("Proof doesn't match the state root")
+	}
+
+	if serialized != nil {
+		if proof.Leaf == nil || !bytes.Equal(proof.Leaf.PublicKey, publicKey) || !bytes.Equal(proof.Leaf.Serialized, serialized) {
+			return errors.New("Proof doesn't match the account")
+		}
+		if !proof.verifyLeaf(proof.Leaf) {
+			return errors.New("Account proof is invalid")
+		}
+		return nil
+	}
+
+	if proof.Count == 0 {
+		return nil
+	}
+
+	if proof.Left == nil && proof.Right == nil {
+		return errors.New("Missing account proof requires its neighbours")
+	}
+
+	if proof.Left != nil {
+		if !proof.verifyLeaf(proof.Left) || bytes.Compare(proof.Left.PublicKey, publicKey) >= 0 {
+			return errors.New("Left neighbour proof is invalid")
+		}
+		if proof.Right == nil && proof.Left.Index != proof.Count-1 {
+			return errors.New("Left neighbour is not the last account")
+		}
+	}
+
+	if proof.Right != nil {
+		if !proof.verifyLeaf(proof.Right) || bytes.Compare(proof.Right.PublicKey, publicKey) <= 0 {
+			return errors.New("Right neighbour proof is invalid")
+		}
+		if proof.Left == nil && proof.Right.Index != 0 {
+			return errors.New("Right neighbour is not the first account")
+		}
+	}
+
+	if proof.Left != nil && proof.Right != nil && proof.Right.Index != proof.Left.Index+1 {
+		return errors.New("Neighbours are not adjacent")
+	}
+
+	return nil
+}
+
+//Verify checks that the accounts state root of the asset and the registrations state root are the ones committed in the state root of a block
+func (proof *AssetsStateProof) Verify(stateRoot, asset, accountsRoot, registrationsRoot []byte) error {
+
+	if !bytes.Equal(proof.RegistrationsRoot, registrationsRoot) {
+		return errors.New("Proof doesn't match the registrations state root")
+	}
+
+	if !bytes.Equal(ComputeStateRoot(proof.RegistrationsRoot, proof.Count, proof.MerkleRoot), stateRoot) {
+		return errors.New("Proof doesn't match the block state root")
+	}
+
+	if proof.Index >= proof.Count || !merkle_tree.VerifyMerkleProof(proof.MerkleRoot, ComputeAssetStateLeaf(asset, accountsRoot), int(proof.Index), int(proof.Count), proof.Proof) {
+		return errors.New("Asset proof is invalid")
+	}
+
+	return nil
+}
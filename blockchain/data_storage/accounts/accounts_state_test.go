@@ -0,0 +1,108 @@
+package accounts
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"pandora-pay/blockchain/data_storage/registrations"
+	"pandora-pay/cryptography"
+	"pandora-pay/cryptography/merkle_tree"
+	"pandora-pay/helpers"
+	"pandora-pay/store/store_db/store_db_interface"
+	"pandora-pay/store/store_db/store_db_memory"
+	"sort"
+	"testing"
+)
+
+func createTestAccountsState(count int) *AccountsState {
+
+	state := &AccountsState{}
+	for i := 0; i < count; i++ {
+		state.Keys = append(state.Keys, helpers.RandomBytes(cryptography.PublicKeySize))
+	}
+	sort.Slice(state.Keys, func(i, j int) bool {
+		return bytes.Compare(state.Keys[i], state.Keys[j]) < 0
+	})
+
+	for _, key := range state.Keys {
+		serialized := helpers.RandomBytes(64)
+		state.Serialized = append(state.Serialized, serialized)
+		state.Leaves = append(state.Leaves, ComputeAccountStateLeaf(key, serialized))
+	}
+	if count > 0 {
+		state.MerkleRoot = merkle_tree.MerkleRoot(state.Leaves)
+	}
+	state.Root = ComputeAccountsStateRoot(uint64(count), state.MerkleRoot)
+
+	return state
+}
+
+func TestAccountStateProof(t *testing.T) {
+
+	for count := 0; count < 10; count++ {
+
+		state := createTestAccountsState(count)
+
+		for i, key := range state.Keys {
+			serialized, proof, err := state.GetProof(key)
+			assert.NoError(t, err)
+			assert.Equal(t, state.Serialized[i], serialized)
+			assert.NoError(t, proof.Verify(state.Root, key, serialized))
+
+			assert.Error(t, proof.Verify(state.Root, key, helpers.RandomBytes(64)), "a different account must be rejected")
+			assert.Error(t, proof.Verify(state.Root, key, nil), "an existing account can't be proven missing")
+		}
+
+		missing := helpers.RandomBytes(cryptography.PublicKeySize)
+		serialized, proof, err := state.GetProof(missing)
+		assert.NoError(t, err)
+		assert.Nil(t, serialized)
+		assert.NoError(t, proof.Verify(state.Root, missing, nil))
+
+		if count > 1 && proof.Left != nil && proof.Right != nil {
+			proof.Right = nil
+			assert.Error(t, proof.Verify(state.Root, missing, nil), "a single neighbour must be the first or the last account")
+		}
+
+		assert.Error(t, proof.Verify(cryptography.RandomHash(), missing, nil))
+	}
+
+}
+
+func TestRegistrationsState(t *testing.T) {
+
+	db, err := store_db_memory.CreateStoreDBMemory("registrations")
+	assert.NoError(t, err)
+
+	keys := [][]byte{}
+	assert.NoError(t, db.Update(func(writer store_db_interface.StoreDBTransactionInterface) (err error) {
+		regs := registrations.NewRegistrations(writer)
+		for i := 0; i < 5; i++ {
+			key := helpers.RandomBytes(cryptography.PublicKeySize)
+			if _, err = regs.CreateNewRegistration(key, i%2 == 0, nil); err != nil {
+				return
+			}
+			keys = append(keys, key)
+		}
+		return regs.CommitChanges()
+	}))
+
+	assert.NoError(t, db.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+
+		regs := registrations.NewRegistrations(reader)
+		state, err := ComputeHashMapState(regs.HashMap)
+		assert.NoError(t, err)
+		assert.Len(t, state.Keys, len(keys))
+
+		for _, key := range keys {
+			reg, err := regs.Get(string(key))
+			assert.NoError(t, err)
+
+			serialized, proof, err := state.GetProof(key)
+			assert.NoError(t, err)
+			assert.Equal(t, helpers.SerializeToBytes(reg), serialized)
+			assert.NoError(t, proof.Verify(state.Root, key, serialized))
+		}
+
+		return
+	}))
+}
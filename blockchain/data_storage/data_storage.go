@@ -28,6 +28,7 @@ type DataStorage struct {
 	PlainAccs                     *plain_accounts.PlainAccounts
 	AccsCollection                *accounts.AccountsCollection
 	PendingStakes                 *pending_stakes_list.PendingStakesList
+	PendingUnstakes               *pending_stakes_list.PendingStakesList
 	ConditionalPaymentsCollection *conditional_payments_list.ConditionalPaymentsCollection
 	Asts                          *assets.Assets
 	AstsFeeLiquidityCollection    *assets.AssetsFeeLiquidityCollection
@@ -154,13 +155,45 @@ func (dataStorage *DataStorage) AddPendingStake(publicKey []byte, amount *crypto
 		return errors.New("reg.Staked is false")
 	}
 
-	pendingStakes, err := dataStorage.PendingStakes.GetPendingStakes(blockHeight)
+	return addPending(dataStorage.PendingStakes, publicKey, amount, blockHeight)
+}
+
+func (dataStorage *DataStorage) ProcessPendingStakes(blockHeight uint64) error {
+	return dataStorage.processPending(dataStorage.PendingStakes, blockHeight)
+}
+
+//AddPendingUnstake releases the amount moved out of a staked account to the unstaked recipient at blockHeight
+func (dataStorage *DataStorage) AddPendingUnstake(publicKey []byte, amount *crypto.ElGamal, blockHeight uint64) error {
+
+	reg, err := dataStorage.Regs.Get(string(publicKey))
+	if err != nil {
+		return err
+	}
+
+	if reg == nil {
+		return errors.New("Account was not registered")
+	}
+
+	if reg.Staked {
+		return errors.New("reg.Staked should be false")
+	}
+
+	return addPending(dataStorage.PendingUnstakes, publicKey, amount, blockHeight)
+}
+
+func (dataStorage *DataStorage) ProcessPendingUnstakes(blockHeight uint64) error {
+	return dataStorage.processPending(dataStorage.PendingUnstakes, blockHeight)
+}
+
+func addPending(list *pending_stakes_list.PendingStakesList, publicKey []byte, amount *crypto.ElGamal, blockHeight uint64) error {
+
+	pendingStakes, err := list.GetPendingStakes(blockHeight)
 	if err != nil {
 		return err
 	}
 
 	if pendingStakes == nil {
-		if pendingStakes, err = dataStorage.PendingStakes.CreateNewPendingStakes(blockHeight); err != nil {
+		if pendingStakes, err = list.CreateNewPendingStakes(blockHeight); err != nil {
 			return err
 		}
 	}
@@ -170,17 +203,17 @@ func (dataStorage *DataStorage) AddPendingStake(publicKey []byte, amount *crypto
 		amount.Serialize(),
 	})
 
-	return dataStorage.PendingStakes.Update(strconv.FormatUint(blockHeight, 10), pendingStakes)
+	return list.Update(strconv.FormatUint(blockHeight, 10), pendingStakes)
 }
 
-func (dataStorage *DataStorage) ProcessPendingStakes(blockHeight uint64) error {
+func (dataStorage *DataStorage) processPending(list *pending_stakes_list.PendingStakesList, blockHeight uint64) error {
 
 	accs, err := dataStorage.AccsCollection.GetMap(config_coins.NATIVE_ASSET_FULL)
 	if err != nil {
 		return err
 	}
 
-	pendingStakes, err := dataStorage.PendingStakes.GetPendingStakes(blockHeight)
+	pendingStakes, err := list.GetPendingStakes(blockHeight)
 	if err != nil {
 		return err
 	}
@@ -211,7 +244,7 @@ func (dataStorage *DataStorage) ProcessPendingStakes(blockHeight uint64) error {
 		}
 	}
 
-	dataStorage.PendingStakes.Delete(strconv.FormatUint(blockHeight, 10))
+	list.Delete(strconv.FormatUint(blockHeight, 10))
 	return nil
 }
 
@@ -404,6 +437,7 @@ func NewDataStorage(dbTx store_db_interface.StoreDBTransactionInterface) (out *D
 		plain_accounts.NewPlainAccounts(dbTx),
 		accounts.NewAccountsCollection(dbTx),
 		pending_stakes_list.NewPendingStakesList(dbTx),
+		pending_stakes_list.NewPendingUnstakesList(dbTx),
 		conditional_payments_list.NewConditionalPaymentsCollection(dbTx),
 		assets.NewAssets(dbTx),
 		assets.NewAssetsFeeLiquidityCollection(dbTx),
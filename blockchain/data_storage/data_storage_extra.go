@@ -18,6 +18,7 @@ func (dataStorage *DataStorage) GetListWithoutCollections() (list []hash_map.Has
 		dataStorage.Regs.HashMap,
 		dataStorage.PlainAccs.HashMap,
 		dataStorage.PendingStakes.HashMap,
+		dataStorage.PendingUnstakes.HashMap,
 		dataStorage.Asts.HashMap,
 	}
 }
@@ -28,6 +29,7 @@ func (dataStorage *DataStorage) GetList(computeChangesSize bool) (list []hash_ma
 		dataStorage.Regs.HashMap,
 		dataStorage.PlainAccs.HashMap,
 		dataStorage.PendingStakes.HashMap,
+		dataStorage.PendingUnstakes.HashMap,
 		dataStorage.Asts.HashMap,
 	}
 
@@ -0,0 +1,96 @@
+package data_storage
+
+import (
+	"bytes"
+	"errors"
+	"pandora-pay/blockchain/data_storage/accounts"
+	"pandora-pay/cryptography/merkle_tree"
+	"sort"
+)
+
+//StateRoot is the committed state of the registrations and of the accounts of every asset. A block commits the state root of the chain it extends
+type StateRoot struct {
+	Root             []byte
+	Registrations    *accounts.AccountsState
+	Assets           [][]byte //sorted
+	AssetsLeaves     [][]byte
+	AssetsMerkleRoot []byte
+	Accounts         map[string]*accounts.AccountsState //only the accounts states of the kept assets
+	ChainHeight      uint64                             //height of the block committing the root
+	ChainHash        []byte                             //hash of the block committing the root
+}
+
+//ComputeStateRoot should be called only on committed data. The accounts states of keepAssets are kept to serve proofs
+func (dataStorage *DataStorage) ComputeStateRoot(keepAssets map[string]bool) (*StateRoot, error) {
+
+	state := &StateRoot{
+		Assets:   make([][]byte, dataStorage.Asts.Count),
+		Accounts: make(map[string]*accounts.AccountsState),
+	}
+
+	var err error
+	if state.Registrations, err = accounts.ComputeHashMapState(dataStorage.Regs.HashMap); err != nil {
+		return nil, err
+	}
+
+	for i := uint64(0); i < dataStorage.Asts.Count; i++ {
+		var key []byte
+		if key, err = dataStorage.Asts.GetKeyByIndex(i); err != nil {
+			return nil, err
+		}
+		state.Assets[i] = append([]byte{}, key...)
+	}
+
+	sort.Slice(state.Assets, func(i, j int) bool {
+		return bytes.Compare(state.Assets[i], state.Assets[j]) < 0
+	})
+
+	state.AssetsLeaves = make([][]byte, len(state.Assets))
+	for i, asset := range state.Assets {
+
+		var accs *accounts.Accounts
+		if accs, err = dataStorage.AccsCollection.GetMap(asset); err != nil {
+			return nil, err
+		}
+
+		var accsState *accounts.AccountsState
+		if accsState, err = accs.ComputeState(); err != nil {
+			return nil, err
+		}
+
+		state.AssetsLeaves[i] = accounts.ComputeAssetStateLeaf(asset, accsState.Root)
+		if keepAssets[string(asset)] {
+			state.Accounts[string(asset)] = accsState
+		}
+	}
+
+	if len(state.AssetsLeaves) > 0 {
+		state.AssetsMerkleRoot = merkle_tree.MerkleRoot(state.AssetsLeaves)
+	}
+	state.Root = accounts.ComputeStateRoot(state.Registrations.Root, uint64(len(state.AssetsLeaves)), state.AssetsMerkleRoot)
+
+	return state, nil
+}
+
+//GetAssetProof returns the kept accounts state of an asset and its proof against the state root
+func (state *StateRoot) GetAssetProof(asset []byte) (*accounts.AccountsState, *accounts.AssetsStateProof, error) {
+
+	accsState := state.Accounts[string(asset)]
+	if accsState == nil {
+		return nil, nil, errors.New("Node is not serving state proofs for the asset")
+	}
+
+	index := sort.Search(len(state.Assets), func(i int) bool {
+		return bytes.Compare(state.Assets[i], asset) >= 0
+	})
+	if index == len(state.Assets) || !bytes.Equal(state.Assets[index], asset) {
+		return nil, nil, errors.New("Asset was not found")
+	}
+
+	proof, err := merkle_tree.MerkleProof(state.AssetsLeaves, index)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return accsState, &accounts.AssetsStateProof{state.Registrations.Root, uint64(len(state.Assets)), state.AssetsMerkleRoot, uint64(index), proof}, nil
+}
@@ -0,0 +1,126 @@
+package data_storage
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"pandora-pay/addresses"
+	"pandora-pay/blockchain/data_storage/accounts/account"
+	"pandora-pay/blockchain/data_storage/assets/asset"
+	"pandora-pay/config/config_coins"
+	"pandora-pay/cryptography/crypto"
+	"pandora-pay/store/store_db/store_db_interface"
+	"pandora-pay/store/store_db/store_db_memory"
+	"testing"
+)
+
+func TestPendingUnstakes(t *testing.T) {
+
+	db, err := store_db_memory.CreateStoreDBMemory("data_storage")
+	assert.Nil(t, err)
+
+	assert.Nil(t, db.Update(func(writer store_db_interface.StoreDBTransactionInterface) (err error) {
+
+		dataStorage := NewDataStorage(writer)
+
+		privateKey := addresses.GenerateNewPrivateKey()
+		publicKey := privateKey.GeneratePublicKey()
+		stakedPublicKey := addresses.GenerateNewPrivateKey().GeneratePublicKey()
+
+		_, err = dataStorage.CreateRegistration(publicKey, false, nil)
+		assert.Nil(t, err)
+		_, err = dataStorage.CreateRegistration(stakedPublicKey, true, nil)
+		assert.Nil(t, err)
+
+		var acc *account.Account
+		_, acc, err = dataStorage.CreateAccount(config_coins.NATIVE_ASSET_FULL, publicKey, true)
+		assert.Nil(t, err)
+		initialBalance := acc.GetBalance()
+		initial := initialBalance.Serialize()
+
+		amount := crypto.CommitElGamal(privateKey.GeneratePublicKeyPoint(), big.NewInt(100))
+		assert.Nil(t, dataStorage.AddPendingUnstake(publicKey, amount, 10))
+		assert.NotNil(t, dataStorage.AddPendingUnstake(stakedPublicKey, amount, 10), "staked recipients receive pending stakes instead")
+
+		balance := func() []byte {
+			accs, err := dataStorage.AccsCollection.GetMap(config_coins.NATIVE_ASSET_FULL)
+			assert.Nil(t, err)
+			acc, err := accs.Get(string(publicKey))
+			assert.Nil(t, err)
+			return acc.GetBalance().Serialize()
+		}
+
+		assert.Nil(t, dataStorage.ProcessPendingUnstakes(9))
+		assert.Equal(t, initial, balance(), "the funds are locked during the cooldown")
+
+		assert.Nil(t, dataStorage.ProcessPendingStakes(10))
+		assert.Equal(t, initial, balance(), "the pending unstakes are not pending stakes")
+
+		assert.Nil(t, dataStorage.ProcessPendingUnstakes(10))
+		assert.Equal(t, initialBalance.Add(amount).Serialize(), balance(), "the funds are released at the end of the cooldown")
+
+		pending, err := dataStorage.PendingUnstakes.GetPendingStakes(10)
+		assert.Nil(t, err)
+		assert.Nil(t, pending)
+
+		return
+	}))
+
+}
+
+func TestStateRoot(t *testing.T) {
+
+	db, err := store_db_memory.CreateStoreDBMemory("data_storage")
+	assert.Nil(t, err)
+
+	assert.Nil(t, db.Update(func(writer store_db_interface.StoreDBTransactionInterface) (err error) {
+
+		dataStorage := NewDataStorage(writer)
+
+		ast := &asset.Asset{Name: config_coins.NATIVE_ASSET_NAME, Ticker: config_coins.NATIVE_ASSET_TICKER, Identification: config_coins.NATIVE_ASSET_IDENTIFICATION, Description: config_coins.NATIVE_ASSET_DESCRIPTION}
+		assert.Nil(t, dataStorage.Asts.CreateAsset(config_coins.NATIVE_ASSET_FULL, ast))
+
+		publicKeys := [][]byte{}
+		for i := 0; i < 3; i++ {
+			publicKey := addresses.GenerateNewPrivateKey().GeneratePublicKey()
+			_, err = dataStorage.CreateRegistration(publicKey, false, nil)
+			assert.Nil(t, err)
+			_, _, err = dataStorage.CreateAccount(config_coins.NATIVE_ASSET_FULL, publicKey, true)
+			assert.Nil(t, err)
+			publicKeys = append(publicKeys, publicKey)
+		}
+		assert.Nil(t, dataStorage.CommitChanges())
+
+		stateRoot, err := dataStorage.ComputeStateRoot(map[string]bool{config_coins.NATIVE_ASSET_FULL_STRING: true})
+		assert.Nil(t, err)
+
+		state, assetProof, err := stateRoot.GetAssetProof(config_coins.NATIVE_ASSET_FULL)
+		assert.Nil(t, err)
+		assert.Nil(t, assetProof.Verify(stateRoot.Root, config_coins.NATIVE_ASSET_FULL, state.Root, stateRoot.Registrations.Root))
+		assert.NotNil(t, assetProof.Verify(stateRoot.Root, config_coins.NATIVE_ASSET_FULL, stateRoot.Registrations.Root, stateRoot.Registrations.Root), "the registrations root is not the accounts root")
+
+		for _, publicKey := range publicKeys {
+			serialized, proof, err := state.GetProof(publicKey)
+			assert.Nil(t, err)
+			assert.NotNil(t, serialized)
+			assert.Nil(t, proof.Verify(state.Root, publicKey, serialized))
+		}
+
+		stateRoot2, err := dataStorage.ComputeStateRoot(nil)
+		assert.Nil(t, err)
+		assert.Equal(t, stateRoot.Root, stateRoot2.Root)
+		_, _, err = stateRoot2.GetAssetProof(config_coins.NATIVE_ASSET_FULL)
+		assert.NotNil(t, err, "the accounts of the assets that are not kept are not served")
+
+		publicKey := addresses.GenerateNewPrivateKey().GeneratePublicKey()
+		_, err = dataStorage.CreateRegistration(publicKey, false, nil)
+		assert.Nil(t, err)
+		assert.Nil(t, dataStorage.CommitChanges())
+
+		stateRoot3, err := dataStorage.ComputeStateRoot(nil)
+		assert.Nil(t, err)
+		assert.NotEqual(t, stateRoot.Root, stateRoot3.Root, "the state root commits to the registrations")
+
+		return
+	}))
+
+}
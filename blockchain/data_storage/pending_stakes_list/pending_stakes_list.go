@@ -27,10 +27,10 @@ func (this *PendingStakesList) GetPendingStakes(blockHeight uint64) (*pending_st
 	return this.Get(strconv.FormatUint(blockHeight, 10))
 }
 
-func NewPendingStakesList(tx store_db_interface.StoreDBTransactionInterface) (this *PendingStakesList) {
+func newPendingStakesList(tx store_db_interface.StoreDBTransactionInterface, name string) (this *PendingStakesList) {
 
 	this = &PendingStakesList{
-		hash_map.CreateNewHashMap[*pending_stakes.PendingStakes](tx, "pendingStakes", 0, false),
+		hash_map.CreateNewHashMap[*pending_stakes.PendingStakes](tx, name, 0, false),
 	}
 
 	this.HashMap.CreateObject = func(key []byte, index uint64) (*pending_stakes.PendingStakes, error) {
@@ -39,3 +39,12 @@ func NewPendingStakesList(tx store_db_interface.StoreDBTransactionInterface) (th
 
 	return
 }
+
+func NewPendingStakesList(tx store_db_interface.StoreDBTransactionInterface) *PendingStakesList {
+	return newPendingStakesList(tx, "pendingStakes")
+}
+
+//NewPendingUnstakesList stores the funds moved out of staked accounts until the unstake cooldown ends
+func NewPendingUnstakesList(tx store_db_interface.StoreDBTransactionInterface) *PendingStakesList {
+	return newPendingStakesList(tx, "pendingUnstakes")
+}
@@ -2,12 +2,16 @@ package forging
 
 import (
 	"github.com/tevino/abool"
+	"math"
+	"math/big"
 	"pandora-pay/address_balance_decryptor"
 	"pandora-pay/blockchain/blockchain_types"
 	"pandora-pay/blockchain/blocks/block_complete"
 	"pandora-pay/blockchain/forging/forging_block_work"
+	"pandora-pay/blockchain/forging/forging_signer"
 	"pandora-pay/blockchain/transactions/transaction"
 	"pandora-pay/config"
+	"pandora-pay/config/config_forging"
 	"pandora-pay/gui"
 	"pandora-pay/helpers/generics"
 	"pandora-pay/helpers/multicast"
@@ -15,6 +19,39 @@ import (
 	"pandora-pay/recovery"
 )
 
+//ForgingBlockForged is broadcasted every time a block forged by one of the wallet addresses was accepted
+type ForgingBlockForged struct {
+	PublicKey     []byte
+	BlockHeight   uint64
+	BlockHash     []byte
+	StakingAmount uint64
+	Reward        uint64
+}
+
+//ForgingBlockOpportunity is an address that was forging with an active stake a new block
+type ForgingBlockOpportunity struct {
+	PublicKey     []byte
+	StakingAmount uint64
+}
+
+//ForgingBlockOpportunities is broadcasted by every worker when it starts forging a new work. The same height can be broadcasted multiple times
+type ForgingBlockOpportunities struct {
+	BlockHeight uint64
+	Target      *big.Int
+	Addresses   []*ForgingBlockOpportunity
+}
+
+//GetExpectedBlocks is the probability of forging the block using the stakingAmount for BLOCK_TIME timestamps
+func (opportunities *ForgingBlockOpportunities) GetExpectedBlocks(stakingAmount uint64) float64 {
+
+	probability := new(big.Float).SetInt(new(big.Int).Mul(opportunities.Target, new(big.Int).SetUint64(stakingAmount)))
+	probability.Quo(probability, new(big.Float).SetInt(config.BIG_INT_MAX_256))
+	probability.Mul(probability, new(big.Float).SetUint64(config.BLOCK_TIME))
+
+	expected, _ := probability.Float64()
+	return math.Min(expected, 1)
+}
+
 type Forging struct {
 	mempool                 *mempool.Mempool
 	addressBalanceDecryptor *address_balance_decryptor.AddressBalanceDecryptor
@@ -23,6 +60,9 @@ type Forging struct {
 	forgingThread           *ForgingThread
 	nextBlockCreatedCn      <-chan *forging_block_work.ForgingWork
 	forgingSolutionCn       chan<- *blockchain_types.BlockchainSolution
+	BlockForged             *multicast.MulticastChannel[*ForgingBlockForged]
+	BlockOpportunities      *multicast.MulticastChannel[*ForgingBlockOpportunities]
+	signer                  forging_signer.ForgingSignerInterface
 }
 
 func CreateForging(mempool *mempool.Mempool, addressBalanceDecryptor *address_balance_decryptor.AddressBalanceDecryptor) (*Forging, error) {
@@ -45,9 +85,19 @@ func CreateForging(mempool *mempool.Mempool, addressBalanceDecryptor *address_ba
 		},
 		abool.New(),
 		nil, nil, nil,
+		multicast.NewMulticastChannel[*ForgingBlockForged](),
+		multicast.NewMulticastChannel[*ForgingBlockOpportunities](),
+		nil,
 	}
 	forging.Wallet.forging = forging
 
+	if config_forging.FORGING_REMOTE_SIGNER != "" {
+		var err error
+		if forging.signer, err = forging_signer.NewRemoteSigner(config_forging.FORGING_REMOTE_SIGNER, config_forging.FORGING_SIGNER_SECRET); err != nil {
+			return nil, err
+		}
+	}
+
 	return forging, nil
 }
 
@@ -57,7 +107,7 @@ func (forging *Forging) InitializeForging(createForgingTransactions func(*block_
 	forging.Wallet.updateNewChainUpdate = updateNewChainUpdate
 	forging.forgingSolutionCn = forgingSolutionCn
 
-	forging.forgingThread = createForgingThread(config.CPU_THREADS, createForgingTransactions, forging.mempool, forging.addressBalanceDecryptor, forging.forgingSolutionCn, forging.nextBlockCreatedCn)
+	forging.forgingThread = createForgingThread(config.CPU_THREADS, createForgingTransactions, forging.mempool, forging.addressBalanceDecryptor, forging.forgingSolutionCn, forging.nextBlockCreatedCn, forging.BlockForged, forging.BlockOpportunities, forging.signer)
 	forging.Wallet.workersCreatedCn = forging.forgingThread.workersCreatedCn
 	forging.Wallet.workersDestroyedCn = forging.forgingThread.workersDestroyedCn
 
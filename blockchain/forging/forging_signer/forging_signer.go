@@ -0,0 +1,78 @@
+package forging_signer
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"math/big"
+	"pandora-pay/config/config_coins"
+	"pandora-pay/cryptography"
+	"pandora-pay/cryptography/bn256"
+	"pandora-pay/cryptography/crypto"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//ForgingSignerInterface computes the secrets of the forging keys without exposing them to the forging threads
+type ForgingSignerInterface interface {
+	ComputeStakingNonce(publicKey, prevKernelHash []byte) ([]byte, error)
+	SignBlock(publicKey []byte, blockHeight uint64, message []byte) ([]byte, error)
+}
+
+type signerRequestType byte
+
+const (
+	SIGNER_REQUEST_STAKING_NONCE signerRequestType = iota
+	SIGNER_REQUEST_SIGN_BLOCK
+)
+
+const (
+	signerNonceSize = 32
+	signerTimeout   = 10 * time.Second
+)
+
+type signerHello struct {
+	Nonce []byte `msgpack:"nonce"`
+	Proof []byte `msgpack:"proof"`
+}
+
+type signerRequest struct {
+	Type           signerRequestType `msgpack:"type"`
+	PublicKey      []byte            `msgpack:"publicKey"`
+	PrevKernelHash []byte            `msgpack:"prevKernelHash"`
+	BlockHeight    uint64            `msgpack:"blockHeight"`
+	Message        []byte            `msgpack:"message"`
+}
+
+type signerResponse struct {
+	Result []byte `msgpack:"result"`
+	Error  string `msgpack:"error"`
+}
+
+//ComputeStakingNonce is the deterministic staking nonce of the private key for the next block
+func ComputeStakingNonce(privateKeyPoint *big.Int, prevKernelHash []byte) []byte {
+	uinput := append([]byte(crypto.PROTOCOL_CRYPTOPGRAPHY_CONSTANT), prevKernelHash[:]...)
+	uinput = append(uinput, config_coins.NATIVE_ASSET_FULL...)
+	uinput = append(uinput, strconv.Itoa(0)...)
+	u := new(bn256.G1).ScalarMult(crypto.HashToPoint(crypto.HashtoNumber(uinput)), privateKeyPoint)
+	return cryptography.SHA3(u.EncodeCompressed())
+}
+
+//parseSignerAddress splits an address like unix:/path or tcp:host:port
+func parseSignerAddress(address string) (string, string, error) {
+	network, addr, found := strings.Cut(address, ":")
+	if !found || addr == "" || (network != "unix" && network != "tcp") {
+		return "", "", errors.New("Invalid signer address. It must be unix:/path or tcp:host:port")
+	}
+	return network, addr, nil
+}
+
+//authProof proves the knowledge of the shared secret for both nonces. The role avoids reflecting the proof of the other side
+func authProof(secret, role string, clientNonce, serverNonce []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(role))
+	mac.Write(clientNonce)
+	mac.Write(serverNonce)
+	return mac.Sum(nil)
+}
@@ -0,0 +1,119 @@
+package forging_signer
+
+import (
+	"crypto/hmac"
+	"errors"
+	"github.com/vmihailenco/msgpack/v5"
+	"net"
+	"pandora-pay/helpers"
+	"sync"
+	"time"
+)
+
+//RemoteSigner is the client used by the forging threads to request the staking nonces and the block signatures
+type RemoteSigner struct {
+	network string
+	address string
+	secret  string
+	conn    net.Conn
+	encoder *msgpack.Encoder
+	decoder *msgpack.Decoder
+	lock    sync.Mutex
+}
+
+//must be locked before
+func (signer *RemoteSigner) connect() (err error) {
+
+	conn, err := net.DialTimeout(signer.network, signer.address, signerTimeout)
+	if err != nil {
+		return
+	}
+
+	defer func() {
+		if err != nil {
+			conn.Close()
+		}
+	}()
+
+	if err = conn.SetDeadline(time.Now().Add(signerTimeout)); err != nil {
+		return
+	}
+
+	encoder, decoder := msgpack.NewEncoder(conn), msgpack.NewDecoder(conn)
+
+	clientNonce := helpers.RandomBytes(signerNonceSize)
+	if err = encoder.Encode(&signerHello{Nonce: clientNonce}); err != nil {
+		return
+	}
+
+	server := &signerHello{}
+	if err = decoder.Decode(server); err != nil {
+		return
+	}
+	if len(server.Nonce) != signerNonceSize || !hmac.Equal(server.Proof, authProof(signer.secret, "server", clientNonce, server.Nonce)) {
+		return errors.New("Remote signer failed to authenticate")
+	}
+
+	if err = encoder.Encode(&signerHello{Proof: authProof(signer.secret, "client", clientNonce, server.Nonce)}); err != nil {
+		return
+	}
+
+	signer.conn, signer.encoder, signer.decoder = conn, encoder, decoder
+	return
+}
+
+func (signer *RemoteSigner) request(request *signerRequest) ([]byte, error) {
+
+	signer.lock.Lock()
+	defer signer.lock.Unlock()
+
+	if signer.conn == nil {
+		if err := signer.connect(); err != nil {
+			return nil, err
+		}
+	}
+
+	response := &signerResponse{}
+
+	err := signer.conn.SetDeadline(time.Now().Add(signerTimeout))
+	if err == nil {
+		err = signer.encoder.Encode(request)
+	}
+	if err == nil {
+		err = signer.decoder.Decode(response)
+	}
+
+	//the connection is established again on the next request
+	if err != nil {
+		signer.conn.Close()
+		signer.conn = nil
+		return nil, err
+	}
+
+	if response.Error != "" {
+		return nil, errors.New(response.Error)
+	}
+	return response.Result, nil
+}
+
+func (signer *RemoteSigner) ComputeStakingNonce(publicKey, prevKernelHash []byte) ([]byte, error) {
+	return signer.request(&signerRequest{Type: SIGNER_REQUEST_STAKING_NONCE, PublicKey: publicKey, PrevKernelHash: prevKernelHash})
+}
+
+func (signer *RemoteSigner) SignBlock(publicKey []byte, blockHeight uint64, message []byte) ([]byte, error) {
+	return signer.request(&signerRequest{Type: SIGNER_REQUEST_SIGN_BLOCK, PublicKey: publicKey, BlockHeight: blockHeight, Message: message})
+}
+
+func NewRemoteSigner(address, secret string) (*RemoteSigner, error) {
+
+	network, addr, err := parseSignerAddress(address)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RemoteSigner{
+		network: network,
+		address: addr,
+		secret:  secret,
+	}, nil
+}
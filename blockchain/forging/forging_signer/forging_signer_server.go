@@ -0,0 +1,210 @@
+package forging_signer
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/vmihailenco/msgpack/v5"
+	"net"
+	"os"
+	"pandora-pay/addresses"
+	"pandora-pay/cryptography"
+	"pandora-pay/cryptography/crypto"
+	"pandora-pay/gui"
+	"pandora-pay/helpers"
+	"pandora-pay/recovery"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_interface"
+	"sync"
+	"time"
+)
+
+//SignedBlock is the last block signed by a forging key. It is stored to refuse signing two different blocks at the same height
+type SignedBlock struct {
+	BlockHeight uint64 `msgpack:"blockHeight"`
+	Message     []byte `msgpack:"message"`
+}
+
+//SignerServer keeps the forging keys in a separate process and answers only to the nodes knowing the shared secret
+type SignerServer struct {
+	listener      net.Listener
+	secret        string
+	getPrivateKey func(publicKey []byte) *addresses.PrivateKey
+	lock          sync.Mutex
+}
+
+func getSignedBlockKey(publicKey []byte) string {
+	return "forgingSignerSignedBlock:" + hex.EncodeToString(publicKey)
+}
+
+//checkDoubleSign stores the block before signing it. The same message can be signed again
+func (server *SignerServer) checkDoubleSign(publicKey []byte, blockHeight uint64, message []byte) error {
+
+	server.lock.Lock()
+	defer server.lock.Unlock()
+
+	return store.StoreSettings.DB.Update(func(writer store_db_interface.StoreDBTransactionInterface) (err error) {
+
+		key := getSignedBlockKey(publicKey)
+
+		if data := writer.Get(key); data != nil {
+			last := &SignedBlock{}
+			if err = msgpack.Unmarshal(data, last); err != nil {
+				return
+			}
+			if blockHeight < last.BlockHeight {
+				return fmt.Errorf("Double sign protection. Block %d is lower than the last signed block %d", blockHeight, last.BlockHeight)
+			}
+			if blockHeight == last.BlockHeight && !bytes.Equal(message, last.Message) {
+				return fmt.Errorf("Double sign protection. A different block %d was already signed", blockHeight)
+			}
+		}
+
+		data, err := msgpack.Marshal(&SignedBlock{blockHeight, message})
+		if err != nil {
+			return
+		}
+
+		writer.Put(key, data)
+		return
+	})
+}
+
+func (server *SignerServer) processRequest(request *signerRequest) ([]byte, error) {
+
+	privateKey := server.getPrivateKey(request.PublicKey)
+	if privateKey == nil {
+		return nil, errors.New("Forging key was not found")
+	}
+
+	switch request.Type {
+	case SIGNER_REQUEST_STAKING_NONCE:
+		if len(request.PrevKernelHash) != cryptography.HashSize {
+			return nil, errors.New("Invalid PrevKernelHash")
+		}
+		return ComputeStakingNonce(new(crypto.BNRed).SetBytes(privateKey.Key).BigInt(), request.PrevKernelHash), nil
+	case SIGNER_REQUEST_SIGN_BLOCK:
+		if len(request.Message) != cryptography.HashSize {
+			return nil, errors.New("Invalid Message")
+		}
+		if err := server.checkDoubleSign(request.PublicKey, request.BlockHeight, request.Message); err != nil {
+			return nil, err
+		}
+		return privateKey.Sign(request.Message)
+	default:
+		return nil, errors.New("Invalid request")
+	}
+}
+
+func (server *SignerServer) authenticate(conn net.Conn, encoder *msgpack.Encoder, decoder *msgpack.Decoder) (err error) {
+
+	if err = conn.SetDeadline(time.Now().Add(signerTimeout)); err != nil {
+		return
+	}
+
+	client := &signerHello{}
+	if err = decoder.Decode(client); err != nil {
+		return
+	}
+	if len(client.Nonce) != signerNonceSize {
+		return errors.New("Invalid nonce")
+	}
+
+	serverNonce := helpers.RandomBytes(signerNonceSize)
+	if err = encoder.Encode(&signerHello{serverNonce, authProof(server.secret, "server", client.Nonce, serverNonce)}); err != nil {
+		return
+	}
+
+	proof := &signerHello{}
+	if err = decoder.Decode(proof); err != nil {
+		return
+	}
+	if !hmac.Equal(proof.Proof, authProof(server.secret, "client", client.Nonce, serverNonce)) {
+		return errors.New("Node failed to authenticate")
+	}
+
+	return conn.SetDeadline(time.Time{})
+}
+
+func (server *SignerServer) handleConnection(conn net.Conn) {
+
+	defer conn.Close()
+
+	encoder, decoder := msgpack.NewEncoder(conn), msgpack.NewDecoder(conn)
+
+	if err := server.authenticate(conn, encoder, decoder); err != nil {
+		gui.GUI.Error("Remote signer rejected connection", conn.RemoteAddr(), err)
+		return
+	}
+
+	for {
+		request := &signerRequest{}
+		if err := decoder.Decode(request); err != nil {
+			return
+		}
+
+		response := &signerResponse{}
+
+		var err error
+		if response.Result, err = server.processRequest(request); err != nil {
+			response.Error = err.Error()
+		}
+
+		if err = encoder.Encode(response); err != nil {
+			return
+		}
+	}
+}
+
+func (server *SignerServer) Close() error {
+	return server.listener.Close()
+}
+
+func NewSignerServer(address, secret string, getPrivateKey func(publicKey []byte) *addresses.PrivateKey) (*SignerServer, error) {
+
+	network, addr, err := parseSignerAddress(address)
+	if err != nil {
+		return nil, err
+	}
+
+	if network == "unix" {
+		if err = os.Remove(addr); err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+
+	listener, err := net.Listen(network, addr)
+	if err != nil {
+		return nil, err
+	}
+
+	//only the owner of the signer process can connect to the unix socket
+	if network == "unix" {
+		if err = os.Chmod(addr, 0600); err != nil {
+			listener.Close()
+			return nil, err
+		}
+	}
+
+	server := &SignerServer{
+		listener:      listener,
+		secret:        secret,
+		getPrivateKey: getPrivateKey,
+	}
+
+	recovery.SafeGo(func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			recovery.SafeGo(func() {
+				server.handleConnection(conn)
+			})
+		}
+	})
+
+	return server, nil
+}
@@ -0,0 +1,82 @@
+package forging_signer
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"pandora-pay/addresses"
+	"pandora-pay/cryptography"
+	"pandora-pay/cryptography/crypto"
+	"pandora-pay/gui"
+	"pandora-pay/gui/gui_non_interactive"
+	"pandora-pay/helpers"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_memory"
+	"path/filepath"
+	"testing"
+)
+
+func TestRemoteSigner(t *testing.T) {
+
+	var err error
+	gui.GUI, err = gui_non_interactive.CreateGUINonInteractive()
+	assert.Nil(t, err)
+
+	db, err := store_db_memory.CreateStoreDBMemory("settings")
+	assert.Nil(t, err)
+	store.StoreSettings = &store.Store{Name: "settings", Opened: true, DB: db}
+
+	privateKey := addresses.GenerateNewPrivateKey()
+	publicKey := privateKey.GeneratePublicKey()
+	getPrivateKey := func(key []byte) *addresses.PrivateKey {
+		if bytes.Equal(key, publicKey) {
+			return privateKey
+		}
+		return nil
+	}
+
+	address := "unix:" + filepath.Join(t.TempDir(), "signer.sock")
+	secret := "0123456789abcdef"
+
+	server, err := NewSignerServer(address, secret, getPrivateKey)
+	assert.Nil(t, err)
+
+	//both sides must know the secret
+	wrong, err := NewRemoteSigner(address, secret+"0")
+	assert.Nil(t, err)
+	_, err = wrong.ComputeStakingNonce(publicKey, helpers.RandomBytes(cryptography.HashSize))
+	assert.NotNil(t, err)
+
+	signer, err := NewRemoteSigner(address, secret)
+	assert.Nil(t, err)
+
+	prevKernelHash := helpers.RandomBytes(cryptography.HashSize)
+	stakingNonce, err := signer.ComputeStakingNonce(publicKey, prevKernelHash)
+	assert.Nil(t, err)
+	assert.Equal(t, ComputeStakingNonce(new(crypto.BNRed).SetBytes(privateKey.Key).BigInt(), prevKernelHash), stakingNonce, "the remote nonce is the same as the local one")
+
+	_, err = signer.ComputeStakingNonce(helpers.RandomBytes(cryptography.PublicKeySize), prevKernelHash)
+	assert.NotNil(t, err)
+
+	message := helpers.RandomBytes(cryptography.HashSize)
+	signature, err := signer.SignBlock(publicKey, 10, message)
+	assert.Nil(t, err)
+	assert.True(t, crypto.VerifySignature(message, signature, publicKey))
+
+	_, err = signer.SignBlock(publicKey, 10, message)
+	assert.Nil(t, err, "the same block can be signed again")
+	_, err = signer.SignBlock(publicKey, 10, helpers.RandomBytes(cryptography.HashSize))
+	assert.NotNil(t, err, "a different block at the same height is refused")
+	_, err = signer.SignBlock(publicKey, 9, helpers.RandomBytes(cryptography.HashSize))
+	assert.NotNil(t, err, "a lower height is refused")
+
+	//the signed heights are kept after a restart of the signer
+	assert.Nil(t, server.Close())
+	server, err = NewSignerServer(address, secret, getPrivateKey)
+	assert.Nil(t, err)
+	defer server.Close()
+
+	_, err = signer.SignBlock(publicKey, 10, helpers.RandomBytes(cryptography.HashSize))
+	assert.NotNil(t, err)
+	_, err = signer.SignBlock(publicKey, 11, helpers.RandomBytes(cryptography.HashSize))
+	assert.Nil(t, err)
+}
@@ -7,10 +7,14 @@ import (
 	"pandora-pay/blockchain/blockchain_types"
 	"pandora-pay/blockchain/blocks/block_complete"
 	"pandora-pay/blockchain/forging/forging_block_work"
+	"pandora-pay/blockchain/forging/forging_signer"
 	"pandora-pay/blockchain/transactions/transaction"
+	"pandora-pay/blockchain/transactions/transaction/transaction_zether"
+	"pandora-pay/blockchain/transactions/transaction/transaction_zether/transaction_zether_payload/transaction_zether_payload_extra"
 	"pandora-pay/gui"
 	"pandora-pay/helpers/advanced_buffers"
 	"pandora-pay/helpers/generics"
+	"pandora-pay/helpers/multicast"
 	"pandora-pay/mempool"
 	"pandora-pay/recovery"
 	"strconv"
@@ -29,6 +33,9 @@ type ForgingThread struct {
 	workersDestroyedCn        chan struct{}
 	lastPrevKernelHash        *generics.Value[[]byte]
 	createForgingTransactions func(*block_complete.BlockComplete, []byte, uint64, []*transaction.Transaction) (*transaction.Transaction, error)
+	blockForged               *multicast.MulticastChannel[*ForgingBlockForged]
+	blockOpportunities        *multicast.MulticastChannel[*ForgingBlockOpportunities]
+	signer                    forging_signer.ForgingSignerInterface
 }
 
 func (thread *ForgingThread) stopForging() {
@@ -44,7 +51,7 @@ func (thread *ForgingThread) startForging() {
 
 	forgingWorkerSolutionCn := make(chan *ForgingSolution)
 	for i := 0; i < len(thread.workers); i++ {
-		thread.workers[i] = createForgingWorkerThread(i, forgingWorkerSolutionCn, thread.addressBalanceDecryptor)
+		thread.workers[i] = createForgingWorkerThread(i, forgingWorkerSolutionCn, thread.addressBalanceDecryptor, thread.blockOpportunities, thread.signer)
 		recovery.SafeGo(thread.workers[i].forge)
 	}
 	thread.workersCreatedCn <- thread.workers
@@ -129,6 +136,13 @@ func (thread *ForgingThread) publishSolution(solution *ForgingSolution) ([]byte,
 
 	newBlk.Block.MerkleHash = newBlk.MerkleHash()
 
+	//the remote signer refuses to sign a different block at the same height
+	if thread.signer != nil {
+		if _, err = thread.signer.SignBlock(solution.publicKey, newBlk.Height, newBlk.Block.SerializeForSigning()); err != nil {
+			return nil, err
+		}
+	}
+
 	newBlk.Bloom = nil
 	if err = newBlk.BloomAll(); err != nil {
 		return nil, err
@@ -142,10 +156,20 @@ func (thread *ForgingThread) publishSolution(solution *ForgingSolution) ([]byte,
 	}
 
 	res := <-result
+	if res.Err == nil {
+		thread.blockForged.Broadcast(&ForgingBlockForged{
+			solution.publicKey,
+			newBlk.Block.Height,
+			newBlk.Bloom.Hash,
+			solution.stakingAmount,
+			txStakingReward.TransactionBaseInterface.(*transaction_zether.TransactionZether).Payloads[1].Extra.(*transaction_zether_payload_extra.TransactionZetherPayloadExtraStakingReward).Reward,
+		})
+	}
+
 	return res.ChainKernelHash, res.Err
 }
 
-func createForgingThread(threads int, createForgingTransactions func(*block_complete.BlockComplete, []byte, uint64, []*transaction.Transaction) (*transaction.Transaction, error), mempool *mempool.Mempool, addressBalanceDecryptor *address_balance_decryptor.AddressBalanceDecryptor, solutionCn chan<- *blockchain_types.BlockchainSolution, nextBlockCreatedCn <-chan *forging_block_work.ForgingWork) *ForgingThread {
+func createForgingThread(threads int, createForgingTransactions func(*block_complete.BlockComplete, []byte, uint64, []*transaction.Transaction) (*transaction.Transaction, error), mempool *mempool.Mempool, addressBalanceDecryptor *address_balance_decryptor.AddressBalanceDecryptor, solutionCn chan<- *blockchain_types.BlockchainSolution, nextBlockCreatedCn <-chan *forging_block_work.ForgingWork, blockForged *multicast.MulticastChannel[*ForgingBlockForged], blockOpportunities *multicast.MulticastChannel[*ForgingBlockOpportunities], signer forging_signer.ForgingSignerInterface) *ForgingThread {
 	return &ForgingThread{
 		mempool,
 		addressBalanceDecryptor,
@@ -157,5 +181,8 @@ func createForgingThread(threads int, createForgingTransactions func(*block_comp
 		make(chan struct{}),
 		&generics.Value[[]byte]{},
 		createForgingTransactions,
+		blockForged,
+		blockOpportunities,
+		signer,
 	}
 }
@@ -7,15 +7,13 @@ import (
 	"pandora-pay/address_balance_decryptor"
 	"pandora-pay/blockchain/blocks/block_complete"
 	"pandora-pay/blockchain/forging/forging_block_work"
+	"pandora-pay/blockchain/forging/forging_signer"
 	"pandora-pay/config"
-	"pandora-pay/config/config_coins"
 	"pandora-pay/cryptography"
-	"pandora-pay/cryptography/bn256"
-	"pandora-pay/cryptography/crypto"
 	"pandora-pay/gui"
 	"pandora-pay/helpers"
 	"pandora-pay/helpers/generics"
-	"strconv"
+	"pandora-pay/helpers/multicast"
 	"sync/atomic"
 	"time"
 )
@@ -37,6 +35,8 @@ type ForgingWorkerThread struct {
 	workerSolutionCn        chan *ForgingSolution
 	addWalletAddressCn      chan *ForgingWalletAddress
 	removeWalletAddressCn   chan string //publicKey
+	blockOpportunities      *multicast.MulticastChannel[*ForgingBlockOpportunities]
+	signer                  forging_signer.ForgingSignerInterface //nil when the keys are used locally
 }
 
 type ForgingWorkerThreadAddress struct {
@@ -53,11 +53,17 @@ func (worker *ForgingWorkerThread) computeStakingAmount(threadAddr *ForgingWorke
 		if threadAddr.walletAdr.decryptedStakingBalance >= work.MinimumStake {
 
 			if !bytes.Equal(threadAddr.stakingNoncePrevChainKernelHash, work.BlkComplete.PrevKernelHash) {
-				uinput := append([]byte(crypto.PROTOCOL_CRYPTOPGRAPHY_CONSTANT), work.BlkComplete.PrevKernelHash[:]...)
-				uinput = append(uinput, config_coins.NATIVE_ASSET_FULL...)
-				uinput = append(uinput, strconv.Itoa(0)...)
-				u := new(bn256.G1).ScalarMult(crypto.HashToPoint(crypto.HashtoNumber(uinput)), threadAddr.walletAdr.privateKeyPoint)
-				threadAddr.stakingNonce = cryptography.SHA3(u.EncodeCompressed())
+				if worker.signer != nil {
+					stakingNonce, err := worker.signer.ComputeStakingNonce(threadAddr.walletAdr.publicKey, work.BlkComplete.PrevKernelHash)
+					if err != nil {
+						gui.GUI.Error("Remote signer failed computing the staking nonce", err)
+						threadAddr.stakingAmount = 0
+						return false
+					}
+					threadAddr.stakingNonce = stakingNonce
+				} else {
+					threadAddr.stakingNonce = forging_signer.ComputeStakingNonce(threadAddr.walletAdr.privateKeyPoint, work.BlkComplete.PrevKernelHash)
+				}
 				threadAddr.stakingNoncePrevChainKernelHash = work.BlkComplete.PrevKernelHash
 			}
 
@@ -129,6 +135,14 @@ func (worker *ForgingWorkerThread) forge() {
 			}
 		}
 
+		if len(walletsStaked) > 0 {
+			opportunities := &ForgingBlockOpportunities{work.BlkHeight, work.Target, make([]*ForgingBlockOpportunity, 0, len(walletsStaked))}
+			for _, walletAddr := range walletsStaked {
+				opportunities.Addresses = append(opportunities.Addresses, &ForgingBlockOpportunity{walletAddr.walletAdr.publicKey, walletAddr.stakingAmount})
+			}
+			worker.blockOpportunities.Broadcast(opportunities)
+		}
+
 		validateWork()
 	}
 
@@ -295,7 +309,7 @@ func (worker *ForgingWorkerThread) forge() {
 
 }
 
-func createForgingWorkerThread(index int, workerSolutionCn chan *ForgingSolution, addressBalanceDecryptor *address_balance_decryptor.AddressBalanceDecryptor) *ForgingWorkerThread {
+func createForgingWorkerThread(index int, workerSolutionCn chan *ForgingSolution, addressBalanceDecryptor *address_balance_decryptor.AddressBalanceDecryptor, blockOpportunities *multicast.MulticastChannel[*ForgingBlockOpportunities], signer forging_signer.ForgingSignerInterface) *ForgingWorkerThread {
 	return &ForgingWorkerThread{
 		addressBalanceDecryptor: addressBalanceDecryptor,
 		index:                   index,
@@ -303,5 +317,7 @@ func createForgingWorkerThread(index int, workerSolutionCn chan *ForgingSolution
 		workerSolutionCn:        workerSolutionCn,
 		addWalletAddressCn:      make(chan *ForgingWalletAddress),
 		removeWalletAddressCn:   make(chan string),
+		blockOpportunities:      blockOpportunities,
+		signer:                  signer,
 	}
 }
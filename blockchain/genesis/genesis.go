@@ -1,6 +1,7 @@
 package genesis
 
 import (
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"github.com/vmihailenco/msgpack/v5"
@@ -8,7 +9,10 @@ import (
 	"os"
 	"pandora-pay/addresses"
 	"pandora-pay/blockchain/blocks/block"
+	"pandora-pay/blockchain/data_storage/assets/asset"
 	"pandora-pay/config"
+	"pandora-pay/config/config_checkpoints"
+	"pandora-pay/config/config_forks"
 	"pandora-pay/config/config_stake"
 	"pandora-pay/config/globals"
 	"pandora-pay/cryptography"
@@ -22,14 +26,23 @@ import (
 type GenesisDataAirDropType struct {
 	Address string `json:"address" msgpack:"address"`
 	Amount  uint64 `json:"amount" msgpack:"amount"`
+	Asset   []byte `json:"asset,omitempty" msgpack:"asset,omitempty"` //empty for the native asset
+}
+
+type GenesisDataAssetType struct {
+	AssetId []byte       `json:"assetId" msgpack:"assetId"`
+	Asset   *asset.Asset `json:"asset" msgpack:"asset"`
 }
 
 type GenesisDataType struct {
-	Hash       []byte                    `json:"hash" msgpack:"hash"`             //32 byte
-	KernelHash []byte                    `json:"kernelHash" msgpack:"kernelHash"` //32 byte
-	Timestamp  uint64                    `json:"timestamp" msgpack:"timestamp"`
-	Target     []byte                    `json:"target" msgpack:"target"` //32 byte
-	AirDrops   []*GenesisDataAirDropType `json:"airDrops" msgpack:"airDrops"`
+	Hash            []byte                    `json:"hash" msgpack:"hash"`             //32 byte
+	KernelHash      []byte                    `json:"kernelHash" msgpack:"kernelHash"` //32 byte
+	Timestamp       uint64                    `json:"timestamp" msgpack:"timestamp"`
+	Target          []byte                    `json:"target" msgpack:"target"` //32 byte
+	AirDrops        []*GenesisDataAirDropType `json:"airDrops" msgpack:"airDrops"`
+	Assets          []*GenesisDataAssetType   `json:"assets,omitempty" msgpack:"assets,omitempty"`
+	SignerPublicKey []byte                    `json:"signerPublicKey,omitempty" msgpack:"signerPublicKey,omitempty"`
+	Signature       []byte                    `json:"signature,omitempty" msgpack:"signature,omitempty"`
 }
 
 var genesisMainet = GenesisDataType{
@@ -73,11 +86,16 @@ func getGenesis() (*GenesisDataType, error) {
 	}
 }
 
+//GetDefaultGenesis is the hardcoded genesis of the selected network
+func GetDefaultGenesis() (*GenesisDataType, error) {
+	return getGenesis()
+}
+
 func CreateNewGenesisBlock() (*block.Block, error) {
 
 	var blk = block.Block{
 		BlockHeader: &block.BlockHeader{
-			Version: 0,
+			Version: config_forks.GetRules(0).BlockVersion,
 			Height:  0,
 		},
 		MerkleHash:     cryptography.SHA3([]byte{}),
@@ -118,6 +136,7 @@ func createNewGenesis(v []string) (err error) {
 		GenesisData.AirDrops = append(GenesisData.AirDrops, &GenesisDataAirDropType{
 			sharedStakedAddress.Address, //registered address
 			amount,
+			nil,
 		})
 
 	}
@@ -134,6 +153,7 @@ func createNewGenesis(v []string) (err error) {
 		GenesisData.AirDrops = append(GenesisData.AirDrops, &GenesisDataAirDropType{
 			addr.EncodeAddr(),
 			0,
+			nil,
 		})
 	}
 
@@ -147,6 +167,7 @@ func createNewGenesis(v []string) (err error) {
 		GenesisData.AirDrops = append(GenesisData.AirDrops, &GenesisDataAirDropType{
 			addr.EncodeAddr(),
 			0,
+			nil,
 		})
 	}
 
@@ -160,6 +181,7 @@ func createNewGenesis(v []string) (err error) {
 		GenesisData.AirDrops = append(GenesisData.AirDrops, &GenesisDataAirDropType{
 			addr.EncodeAddr(),
 			0,
+			nil,
 		})
 	}
 
@@ -174,7 +196,7 @@ func createNewGenesis(v []string) (err error) {
 	return
 }
 
-func createSimpleGenesis(walletGetFirstAddressForDevnetGenesisAirdrop func() (string, *shared_staked.WalletAddressSharedStakedAddressExported, error)) (err error) {
+func createSimpleGenesis(filename string, walletGetFirstAddressForDevnetGenesisAirdrop func() (string, *shared_staked.WalletAddressSharedStakedAddressExported, error)) (err error) {
 
 	var file *os.File
 
@@ -196,7 +218,7 @@ func createSimpleGenesis(walletGetFirstAddressForDevnetGenesisAirdrop func() (st
 		Amount:  amount,
 	})
 
-	if file, err = os.OpenFile("./genesis.data", os.O_RDWR|os.O_CREATE|os.O_APPEND, 0666); err != nil {
+	if file, err = os.OpenFile(filename, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0666); err != nil {
 		return
 	}
 	defer file.Close()
@@ -229,26 +251,43 @@ func GenesisInit(walletGetFirstAddressForDevnetGenesisAirdrop func() (string, *s
 
 		data := []byte(dataArgument.(string))
 
-		if string(data) == "file" && runtime.GOARCH != "wasm" {
+		//"file" reads ./genesis.data and "file:path" reads the genesis from path
+		if (string(data) == "file" || strings.HasPrefix(string(data), "file:")) && runtime.GOARCH != "wasm" {
+
+			filename := "./genesis.data"
+			if string(data) != "file" {
+				filename = strings.TrimPrefix(string(data), "file:")
+			}
 
-			if _, err = os.Stat("./genesis.data"); os.IsNotExist(err) {
-				if err = createSimpleGenesis(walletGetFirstAddressForDevnetGenesisAirdrop); err != nil {
+			if _, err = os.Stat(filename); os.IsNotExist(err) {
+				if err = createSimpleGenesis(filename, walletGetFirstAddressForDevnetGenesisAirdrop); err != nil {
 					return
 				}
 			}
 
-			if data, err = ioutil.ReadFile("./genesis.data"); err != nil {
+			if data, err = ioutil.ReadFile(filename); err != nil {
 				return
 			}
 
 		}
 
-		if err = msgpack.Unmarshal(data, &GenesisData); err != nil {
+		var expectedHash []byte
+		if hash := globals.Arguments["--genesis-hash"]; hash != nil {
+			if expectedHash, err = hex.DecodeString(hash.(string)); err != nil || len(expectedHash) != cryptography.HashSize {
+				return errors.New("--genesis-hash must be a hex encoded hash")
+			}
+		}
+
+		if GenesisData, err = ReadGenesisData(data, GenesisData, expectedHash); err != nil {
 			return
 		}
 
 	}
 
+	if err = config_checkpoints.VerifyGenesisHash(GenesisData.Hash); err != nil {
+		return
+	}
+
 	if Genesis, err = CreateNewGenesisBlock(); err != nil {
 		return
 	}
@@ -0,0 +1,301 @@
+package genesis
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/vmihailenco/msgpack/v5"
+	"gopkg.in/yaml.v3"
+	"io/ioutil"
+	"pandora-pay/addresses"
+	"pandora-pay/blockchain/data_storage/assets/asset"
+	"pandora-pay/blockchain/data_storage/registrations"
+	"pandora-pay/config/config_coins"
+	"pandora-pay/cryptography"
+	"pandora-pay/cryptography/crypto"
+	"pandora-pay/helpers"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+type GenesisSpecAirDrop struct {
+	Address string `json:"address" yaml:"address"`
+	Amount  uint64 `json:"amount" yaml:"amount"`
+	Asset   string `json:"asset,omitempty" yaml:"asset,omitempty"` //ticker of a genesis asset. Empty for the native asset
+}
+
+type GenesisSpecAsset struct {
+	Name             string `json:"name" yaml:"name"`
+	Ticker           string `json:"ticker" yaml:"ticker"`
+	Description      string `json:"description" yaml:"description"`
+	DecimalSeparator byte   `json:"decimalSeparator" yaml:"decimalSeparator"`
+	MaxSupply        uint64 `json:"maxSupply" yaml:"maxSupply"`
+	CanMint          bool   `json:"canMint,omitempty" yaml:"canMint,omitempty"`
+	CanBurn          bool   `json:"canBurn,omitempty" yaml:"canBurn,omitempty"`
+	UpdatePublicKey  string `json:"updatePublicKey,omitempty" yaml:"updatePublicKey,omitempty"` //hex. Empty will burn the key
+	SupplyPublicKey  string `json:"supplyPublicKey,omitempty" yaml:"supplyPublicKey,omitempty"` //hex. Empty will burn the key
+}
+
+//GenesisSpec is the input of the genesis builder. Empty values are replaced by the current time, the network target and a random hash
+type GenesisSpec struct {
+	Hash      string                `json:"hash,omitempty" yaml:"hash,omitempty"`
+	Timestamp uint64                `json:"timestamp,omitempty" yaml:"timestamp,omitempty"`
+	Target    string                `json:"target,omitempty" yaml:"target,omitempty"`
+	AirDrops  []*GenesisSpecAirDrop `json:"airDrops" yaml:"airDrops"`
+	Assets    []*GenesisSpecAsset   `json:"assets,omitempty" yaml:"assets,omitempty"`
+}
+
+func ReadGenesisSpec(filename string) (*GenesisSpec, error) {
+
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	spec := &GenesisSpec{}
+
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, spec)
+	default:
+		err = json.Unmarshal(data, spec)
+	}
+
+	return spec, err
+}
+
+func decodeSpecHex(value string, size int, name string) ([]byte, error) {
+	out, err := hex.DecodeString(value)
+	if err != nil || len(out) != size {
+		return nil, fmt.Errorf("Invalid %s", name)
+	}
+	return out, nil
+}
+
+func getGenesisAssetId(genesisHash []byte, index int) []byte {
+	return cryptography.RIPEMD(cryptography.SHA3(append([]byte{byte(index)}, genesisHash...)))
+}
+
+//ValidateGenesisData verifies the airdrops, the assets and the signature of a genesis
+func ValidateGenesisData(data *GenesisDataType) error {
+
+	if len(data.Hash) != cryptography.HashSize || len(data.KernelHash) != cryptography.HashSize || len(data.Target) != cryptography.HashSize {
+		return errors.New("Invalid genesis hash, kernel hash or target")
+	}
+
+	supplies := make(map[string]uint64)
+	maxSupplies := map[string]uint64{string(config_coins.NATIVE_ASSET_FULL): config_coins.MAX_SUPPLY_COINS_UNITS}
+
+	for i, genesisAsset := range data.Assets {
+		genesisAsset.Asset.SetKey(genesisAsset.AssetId)
+		if err := genesisAsset.Asset.Validate(); err != nil {
+			return fmt.Errorf("Asset %d is invalid: %s", i, err)
+		}
+		if _, exists := maxSupplies[string(genesisAsset.AssetId)]; exists {
+			return fmt.Errorf("Asset %d is duplicated", i)
+		}
+		maxSupplies[string(genesisAsset.AssetId)] = genesisAsset.Asset.MaxSupply
+	}
+
+	visited := make(map[string]bool)
+	for i, airdrop := range data.AirDrops {
+
+		addr, err := addresses.DecodeAddr(airdrop.Address)
+		if err != nil {
+			return fmt.Errorf("AirDrop %d address is invalid: %s", i, err)
+		}
+		if addr.IsIntegratedAmount() || addr.IsIntegratedPaymentID() || addr.IsIntegratedPaymentAsset() {
+			return fmt.Errorf("AirDrop %d: Amount, PaymentID or IntegratedPaymentAsset are not allowed in the airdrop address", i)
+		}
+		if !registrations.VerifyRegistration(addr.PublicKey, addr.Staked, addr.SpendPublicKey, addr.Registration) {
+			return fmt.Errorf("AirDrop %d registration verification is false", i)
+		}
+
+		assetId := airdrop.GetAsset()
+		if _, exists := maxSupplies[string(assetId)]; !exists {
+			return fmt.Errorf("AirDrop %d asset doesn't exist", i)
+		}
+
+		key := string(addr.PublicKey) + string(assetId)
+		if visited[key] {
+			return fmt.Errorf("AirDrop %d is duplicated", i)
+		}
+		visited[key] = true
+
+		supply := supplies[string(assetId)]
+		if err = helpers.SafeUint64Add(&supply, airdrop.Amount); err != nil {
+			return err
+		}
+		if supply > maxSupplies[string(assetId)] {
+			return fmt.Errorf("AirDrop %d exceeds the max supply of the asset", i)
+		}
+		supplies[string(assetId)] = supply
+	}
+
+	//a genesis with a signer must keep its signature
+	if (len(data.Signature) > 0 || len(data.SignerPublicKey) > 0) && !crypto.VerifySignature(data.ComputeHash(), data.Signature, data.SignerPublicKey) {
+		return errors.New("Genesis signature is invalid")
+	}
+
+	return nil
+}
+
+//ReadGenesisData decodes a genesis over the default genesis of the network and validates it. The genesis must have the expectedHash when it is set
+func ReadGenesisData(serialized []byte, defaultGenesis *GenesisDataType, expectedHash []byte) (*GenesisDataType, error) {
+
+	data := *defaultGenesis
+	if err := msgpack.Unmarshal(serialized, &data); err != nil {
+		return nil, err
+	}
+
+	if len(expectedHash) > 0 && !bytes.Equal(data.ComputeHash(), expectedHash) {
+		return nil, errors.New("Genesis hash is not matching --genesis-hash")
+	}
+
+	if err := ValidateGenesisData(&data); err != nil {
+		return nil, err
+	}
+
+	return &data, nil
+}
+
+//BuildGenesis creates a genesis signed by the signer from a spec
+func BuildGenesis(spec *GenesisSpec, defaultTarget []byte, signer *addresses.PrivateKey) (data *GenesisDataType, err error) {
+
+	data = &GenesisDataType{
+		Timestamp: spec.Timestamp,
+		AirDrops:  make([]*GenesisDataAirDropType, len(spec.AirDrops)),
+	}
+
+	if spec.Hash != "" {
+		if data.Hash, err = decodeSpecHex(spec.Hash, cryptography.HashSize, "hash"); err != nil {
+			return
+		}
+	} else {
+		data.Hash = helpers.RandomBytes(cryptography.HashSize)
+	}
+
+	if spec.Target != "" {
+		if data.Target, err = decodeSpecHex(spec.Target, cryptography.HashSize, "target"); err != nil {
+			return
+		}
+	} else {
+		data.Target = helpers.CloneBytes(defaultTarget)
+	}
+	data.KernelHash = helpers.CloneBytes(data.Target)
+
+	if data.Timestamp == 0 {
+		data.Timestamp = uint64(time.Now().Unix())
+	}
+
+	tickers := make(map[string][]byte)
+	for i, specAsset := range spec.Assets {
+
+		genesisAsset := &GenesisDataAssetType{
+			AssetId: getGenesisAssetId(data.Hash, i),
+			Asset: &asset.Asset{
+				CanMint:          specAsset.CanMint,
+				CanBurn:          specAsset.CanBurn,
+				DecimalSeparator: specAsset.DecimalSeparator,
+				MaxSupply:        specAsset.MaxSupply,
+				UpdatePublicKey:  config_coins.BURN_PUBLIC_KEY,
+				SupplyPublicKey:  config_coins.BURN_PUBLIC_KEY,
+				Name:             specAsset.Name,
+				Ticker:           specAsset.Ticker,
+				Description:      specAsset.Description,
+			},
+		}
+
+		if specAsset.UpdatePublicKey != "" {
+			if genesisAsset.Asset.UpdatePublicKey, err = decodeSpecHex(specAsset.UpdatePublicKey, cryptography.PublicKeySize, "update public key"); err != nil {
+				return
+			}
+		}
+		if specAsset.SupplyPublicKey != "" {
+			if genesisAsset.Asset.SupplyPublicKey, err = decodeSpecHex(specAsset.SupplyPublicKey, cryptography.PublicKeySize, "supply public key"); err != nil {
+				return
+			}
+		}
+
+		genesisAsset.Asset.SetKey(genesisAsset.AssetId)
+		tickers[specAsset.Ticker] = genesisAsset.AssetId
+
+		data.Assets = append(data.Assets, genesisAsset)
+	}
+
+	for i, specAirDrop := range spec.AirDrops {
+
+		data.AirDrops[i] = &GenesisDataAirDropType{
+			Address: specAirDrop.Address,
+			Amount:  specAirDrop.Amount,
+		}
+
+		if specAirDrop.Asset != "" {
+			if data.AirDrops[i].Asset = tickers[specAirDrop.Asset]; data.AirDrops[i].Asset == nil {
+				return nil, fmt.Errorf("AirDrop %d asset %s was not found", i, specAirDrop.Asset)
+			}
+		}
+	}
+
+	data.SignerPublicKey = signer.GeneratePublicKey()
+	if data.Signature, err = signer.Sign(data.ComputeHash()); err != nil {
+		return
+	}
+
+	if err = ValidateGenesisData(data); err != nil {
+		return
+	}
+
+	return
+}
+
+//BuildGenesisFile reads the spec and writes the signed genesis that can be loaded using --set-genesis
+func BuildGenesisFile(specFilename, outputFilename string, defaultTarget []byte, signer *addresses.PrivateKey) (hash []byte, err error) {
+
+	spec, err := ReadGenesisSpec(specFilename)
+	if err != nil {
+		return
+	}
+
+	data, err := BuildGenesis(spec, defaultTarget, signer)
+	if err != nil {
+		return
+	}
+
+	serialized, err := msgpack.Marshal(data)
+	if err != nil {
+		return
+	}
+
+	if err = ioutil.WriteFile(outputFilename, serialized, 0644); err != nil {
+		return
+	}
+
+	hash = data.ComputeHash()
+	if err = ioutil.WriteFile(outputFilename+".hash", []byte(hex.EncodeToString(hash)), 0644); err != nil {
+		return
+	}
+
+	return
+}
+
+func (airdrop *GenesisDataAirDropType) GetAsset() []byte {
+	if len(airdrop.Asset) == 0 {
+		return config_coins.NATIVE_ASSET_FULL
+	}
+	return airdrop.Asset
+}
+
+//ComputeHash identifies the genesis. The signature is not included
+func (data *GenesisDataType) ComputeHash() []byte {
+	clone := *data
+	clone.Signature = nil
+	serialized, err := msgpack.Marshal(&clone)
+	if err != nil {
+		panic(err)
+	}
+	return cryptography.SHA3(serialized)
+}
@@ -0,0 +1,127 @@
+package genesis
+
+import (
+	"encoding/hex"
+	"github.com/stretchr/testify/assert"
+	"github.com/vmihailenco/msgpack/v5"
+	"io/ioutil"
+	"os"
+	"pandora-pay/addresses"
+	"pandora-pay/config/config_checkpoints"
+	"path/filepath"
+	"testing"
+)
+
+func createTestGenesisSpec(t *testing.T) *GenesisSpec {
+
+	addr, err := addresses.GenerateNewPrivateKey().GenerateAddress(false, nil, true, nil, 0, nil)
+	assert.Nil(t, err)
+
+	return &GenesisSpec{
+		AirDrops: []*GenesisSpecAirDrop{
+			{Address: addr.EncodeAddr(), Amount: 1000},
+			{Address: addr.EncodeAddr(), Amount: 500, Asset: "TST"},
+		},
+		Assets: []*GenesisSpecAsset{
+			{Name: "Test", Ticker: "TST", Description: "Test asset", DecimalSeparator: 5, MaxSupply: 1000},
+		},
+	}
+}
+
+func TestBuildGenesis(t *testing.T) {
+
+	signer := addresses.GenerateNewPrivateKey()
+
+	data, err := BuildGenesis(createTestGenesisSpec(t), genesisDevnet.Target, signer)
+	assert.Nil(t, err)
+	assert.Nil(t, ValidateGenesisData(data))
+	assert.Equal(t, data.AirDrops[1].Asset, data.Assets[0].AssetId)
+
+	spec := createTestGenesisSpec(t)
+	spec.AirDrops[1].Asset = "NONE"
+	_, err = BuildGenesis(spec, genesisDevnet.Target, signer)
+	assert.NotNil(t, err, "the airdrop asset must be a genesis asset")
+
+	spec = createTestGenesisSpec(t)
+	spec.AirDrops[1].Amount = 1001
+	_, err = BuildGenesis(spec, genesisDevnet.Target, signer)
+	assert.NotNil(t, err, "the airdrops can't exceed the max supply")
+}
+
+func TestReadGenesisData(t *testing.T) {
+
+	data, err := BuildGenesis(createTestGenesisSpec(t), genesisDevnet.Target, addresses.GenerateNewPrivateKey())
+	assert.Nil(t, err)
+
+	serialize := func(data *GenesisDataType) []byte {
+		serialized, err := msgpack.Marshal(data)
+		assert.Nil(t, err)
+		return serialized
+	}
+
+	loaded, err := ReadGenesisData(serialize(data), &genesisDevnet, data.ComputeHash())
+	assert.Nil(t, err)
+	assert.Equal(t, data.ComputeHash(), loaded.ComputeHash())
+
+	_, err = ReadGenesisData(serialize(data), &genesisDevnet, genesisDevnet.Hash)
+	assert.NotNil(t, err, "a different genesis is rejected")
+
+	tampered := *data
+	tampered.AirDrops = []*GenesisDataAirDropType{{Address: data.AirDrops[0].Address, Amount: 2000}}
+	_, err = ReadGenesisData(serialize(&tampered), &genesisDevnet, nil)
+	assert.EqualError(t, err, "Genesis signature is invalid")
+
+	stripped := *data
+	stripped.Signature = nil
+	_, err = ReadGenesisData(serialize(&stripped), &genesisDevnet, nil)
+	assert.EqualError(t, err, "Genesis signature is invalid", "the signature can't be stripped")
+
+	stripped.SignerPublicKey = nil
+	_, err = ReadGenesisData(serialize(&stripped), &genesisDevnet, data.ComputeHash())
+	assert.NotNil(t, err, "the hash covers the signer")
+}
+
+func TestBuildGenesisFile(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "genesis")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	spec := `
+airDrops:
+  - address: "` + createTestGenesisSpec(t).AirDrops[0].Address + `"
+    amount: 100
+`
+	specFilename := filepath.Join(dir, "genesis.yaml")
+	assert.Nil(t, ioutil.WriteFile(specFilename, []byte(spec), 0644))
+
+	output := filepath.Join(dir, "genesis.data")
+	hash, err := BuildGenesisFile(specFilename, output, genesisDevnet.Target, addresses.GenerateNewPrivateKey())
+	assert.Nil(t, err)
+
+	hashFile, err := ioutil.ReadFile(output + ".hash")
+	assert.Nil(t, err)
+	assert.Equal(t, hex.EncodeToString(hash), string(hashFile))
+
+	serialized, err := ioutil.ReadFile(output)
+	assert.Nil(t, err)
+
+	data, err := ReadGenesisData(serialized, &genesisDevnet, hash)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(100), data.AirDrops[0].Amount)
+}
+
+func TestGenesisCheckpoint(t *testing.T) {
+
+	assert.Equal(t, genesisTestnet.Hash, config_checkpoints.TEST_NET_GENESIS_HASH, "the testnet genesis is pinned")
+
+	oldHash := config_checkpoints.GENESIS_HASH
+	defer func() { config_checkpoints.GENESIS_HASH = oldHash }()
+
+	config_checkpoints.GENESIS_HASH = config_checkpoints.TEST_NET_GENESIS_HASH
+	assert.Nil(t, config_checkpoints.VerifyGenesisHash(genesisTestnet.Hash))
+	assert.NotNil(t, config_checkpoints.VerifyGenesisHash(genesisDevnet.Hash))
+
+	config_checkpoints.GENESIS_HASH = config_checkpoints.DEV_NET_GENESIS_HASH
+	assert.Nil(t, config_checkpoints.VerifyGenesisHash(genesisDevnet.Hash), "the devnets create their own genesis")
+}
@@ -10,6 +10,7 @@ import (
 	"pandora-pay/blockchain/transactions/transaction/transaction_zether"
 	"pandora-pay/blockchain/transactions/transaction/transaction_zether/transaction_zether_payload/transaction_zether_payload_extra"
 	"pandora-pay/blockchain/transactions/transaction/transaction_zether/transaction_zether_payload/transaction_zether_payload_script"
+	"pandora-pay/config/config_coins"
 	"pandora-pay/cryptography/crypto"
 )
 
@@ -84,6 +85,8 @@ func CreateTxPreviewFromTx(tx *transaction.Transaction) (*TxPreview, error) {
 				payloadExtra = &TxPreviewZetherPayloadExtraStaking{}
 			case transaction_zether_payload_script.SCRIPT_SPEND:
 				payloadExtra = &TxPreviewZetherPayloadExtraSpend{}
+			case transaction_zether_payload_script.SCRIPT_UNSTAKING:
+				payloadExtra = &TxPreviewZetherPayloadExtraUnstaking{}
 			case transaction_zether_payload_script.SCRIPT_CONDITIONAL_PAYMENT:
 				txPayloadExtra := payload.Extra.(*transaction_zether_payload_extra.TransactionZetherPayloadExtraConditionalPayment)
 				payloadExtra = &TxPreviewZetherPayloadExtraPayToScript{txPayloadExtra.Deadline, txPayloadExtra.DefaultResolution, txPayloadExtra.MultisigThreshold}
@@ -122,3 +125,17 @@ func CreateTxPreviewFromTx(tx *transaction.Transaction) (*TxPreview, error) {
 		fee,
 	}, nil
 }
+
+//GetAssets returns the assets used by the payloads. Simple txs pay the fee using the native asset
+func (preview *TxPreview) GetAssets() map[string]bool {
+	assets := make(map[string]bool)
+	switch base := preview.TxBase.(type) {
+	case *TxPreviewZether:
+		for _, payload := range base.Payloads {
+			assets[string(payload.Asset)] = true
+		}
+	default:
+		assets[config_coins.NATIVE_ASSET_FULL_STRING] = true
+	}
+	return assets
+}
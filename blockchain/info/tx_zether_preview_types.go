@@ -15,6 +15,9 @@ type TxPreviewZetherPayloadExtraStaking struct {
 type TxPreviewZetherPayloadExtraSpend struct {
 }
 
+type TxPreviewZetherPayloadExtraUnstaking struct {
+}
+
 type TxPreviewZetherPayloadExtraPayToScript struct {
 	Deadline          uint64 `json:"deadline" msgpack:"dealine"`
 	DefaultResolution bool   `json:"defaultResolution" msgpack:"defaultResolution"`
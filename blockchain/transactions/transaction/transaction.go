@@ -8,6 +8,7 @@ import (
 	"pandora-pay/blockchain/transactions/transaction/transaction_simple"
 	"pandora-pay/blockchain/transactions/transaction/transaction_type"
 	"pandora-pay/blockchain/transactions/transaction/transaction_zether"
+	"pandora-pay/config/config_forks"
 	"pandora-pay/cryptography"
 	"pandora-pay/helpers/advanced_buffers"
 )
@@ -21,6 +22,10 @@ type Transaction struct {
 
 func (tx *Transaction) IncludeTransaction(blockHeight uint64, dataStorage *data_storage.DataStorage) error {
 
+	if uint64(tx.Version) > config_forks.GetRules(blockHeight).TxVersionMax {
+		return fmt.Errorf("Transaction version %s is not active at height %d", tx.Version.String(), blockHeight)
+	}
+
 	dataStorage.ResetChangesSize()
 
 	if err := tx.TransactionBaseInterface.IncludeTransaction(blockHeight, tx.Bloom.Hash, dataStorage); err != nil {
@@ -0,0 +1,57 @@
+package transaction_data
+
+import (
+	"bytes"
+	"errors"
+)
+
+const TX_DATA_MESSAGE_PAYMENT_ID_LENGTH = 8
+
+//TransactionDataMessage is the content that wallets store inside the payload data. The PaymentID is prefixed by a zero byte that can't start a text memo
+type TransactionDataMessage struct {
+	PaymentID []byte `json:"paymentID,omitempty" msgpack:"paymentID,omitempty"`
+	Memo      []byte `json:"memo,omitempty" msgpack:"memo,omitempty"`
+}
+
+func (message *TransactionDataMessage) Validate() error {
+	if len(message.PaymentID) != 0 && len(message.PaymentID) != TX_DATA_MESSAGE_PAYMENT_ID_LENGTH {
+		return errors.New("Invalid PaymentID. It must be an 8 byte")
+	}
+	if len(message.Memo) > 0 && message.Memo[0] == 0 {
+		return errors.New("Memo can not start with a zero byte")
+	}
+	return nil
+}
+
+func (message *TransactionDataMessage) Serialize() []byte {
+	if len(message.PaymentID) == 0 {
+		return message.Memo
+	}
+
+	out := make([]byte, 0, 1+len(message.PaymentID)+len(message.Memo))
+	out = append(out, 0)
+	out = append(out, message.PaymentID...)
+	return append(out, message.Memo...)
+}
+
+//DeserializeTransactionDataMessage removes the zero padding that is added to the encrypted payloads
+func DeserializeTransactionDataMessage(data []byte) *TransactionDataMessage {
+
+	message := &TransactionDataMessage{}
+
+	//an empty message is entirely made of padding
+	if len(bytes.TrimRight(data, "\x00")) == 0 {
+		return message
+	}
+
+	if len(data) > TX_DATA_MESSAGE_PAYMENT_ID_LENGTH && data[0] == 0 {
+		message.PaymentID = append([]byte{}, data[1:1+TX_DATA_MESSAGE_PAYMENT_ID_LENGTH]...)
+		data = data[1+TX_DATA_MESSAGE_PAYMENT_ID_LENGTH:]
+	}
+
+	if data = bytes.TrimRight(data, "\x00"); len(data) > 0 {
+		message.Memo = append([]byte{}, data...)
+	}
+
+	return message
+}
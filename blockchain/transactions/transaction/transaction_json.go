@@ -78,6 +78,9 @@ type json_Only_TransactionZether struct {
 type json_Only_TransactionZetherPayloadExtraStaking struct {
 }
 
+type json_Only_TransactionZetherPayloadExtraUnstaking struct {
+}
+
 type json_Only_TransactionZetherPayloadExtraStakingReward struct {
 	Reward                            uint64 `json:"reward"  msgpack:"reward"`
 	TemporaryAccountRegistrationIndex uint64 `json:"temporaryAccountRegistrationIndex"  msgpack:"temporaryAccountRegistrationIndex"`
@@ -278,6 +281,8 @@ func marshalJSON(tx *Transaction, marshal func(any) ([]byte, error)) ([]byte, er
 					payloadExtra.MultisigThreshold,
 					payloadExtra.MultisigPublicKeys,
 				}
+			case transaction_zether_payload_script.SCRIPT_UNSTAKING:
+				extra = &json_Only_TransactionZetherPayloadExtraUnstaking{}
 			default:
 				return nil, errors.New("Invalid zether.TxScript")
 			}
@@ -568,6 +573,15 @@ func (tx *Transaction) UnmarshalJSON(data []byte) (err error) {
 					extraJson.MultisigThreshold,
 					extraJson.MultisigPublicKeys,
 				}
+			case transaction_zether_payload_script.SCRIPT_UNSTAKING:
+				extraJson := &json_Only_TransactionZetherPayloadExtraUnstaking{}
+				if err = json.Unmarshal(data, extraJson); err != nil {
+					return err
+				}
+
+				payloads[i].Extra = &transaction_zether_payload_extra.TransactionZetherPayloadExtraUnstaking{
+					nil,
+				}
 			default:
 				return errors.New("Invalid Zether TxScript")
 			}
@@ -15,6 +15,7 @@ import (
 	"pandora-pay/config"
 	"pandora-pay/config/config_assets"
 	"pandora-pay/config/config_coins"
+	"pandora-pay/config/config_forks"
 	"pandora-pay/config/config_stake"
 	"pandora-pay/cryptography/crypto"
 	"pandora-pay/helpers"
@@ -95,6 +96,28 @@ func (payload *TransactionZetherPayload) processAssetFee(assetId []byte, txFee,
 	return accs.Update(string(plainAcc.AssetFeeLiquidities.Collector), acc)
 }
 
+//checkStakedSenders allows the staked funds to leave only through Unstaking, which applies the cooldown. The real sender is hidden in the sender side of the ring, so a staked member of the sender side makes it a staked sender.
+//Before the upgrade, a transfer to an unstaked recipient was the way to unstake
+func (payload *TransactionZetherPayload) checkStakedSenders(stakedSender, unstakedRecipient bool, blockHeight uint64) error {
+
+	if !config_forks.GetRules(blockHeight).StakedSendersChecks || !stakedSender || !bytes.Equal(payload.Asset, config_coins.NATIVE_ASSET_FULL) {
+		return nil
+	}
+
+	switch payload.PayloadScript {
+	case transaction_zether_payload_script.SCRIPT_STAKING, transaction_zether_payload_script.SCRIPT_STAKING_REWARD, transaction_zether_payload_script.SCRIPT_UNSTAKING:
+		return nil
+	}
+
+	if unstakedRecipient {
+		return errors.New("Staked senders can send to unstaked recipients only by Unstaking")
+	}
+	if payload.BurnValue > 0 {
+		return errors.New("Staked senders can not burn")
+	}
+	return nil
+}
+
 func (payload *TransactionZetherPayload) IncludePayload(txHash []byte, payloadIndex byte, publicKeyList [][]byte, blockHeight uint64, dataStorage *data_storage.DataStorage) (err error) {
 
 	var accs *accounts.Accounts
@@ -102,6 +125,10 @@ func (payload *TransactionZetherPayload) IncludePayload(txHash []byte, payloadIn
 	var reg *registration.Registration
 	var balance *crypto.ElGamal
 
+	if uint64(payload.PayloadScript) > config_forks.GetRules(blockHeight).PayloadScriptMax {
+		return fmt.Errorf("Payload script %s is not active at height %d", payload.PayloadScript.String(), blockHeight)
+	}
+
 	if !bytes.Equal(payload.Asset, config_coins.NATIVE_ASSET_FULL) {
 		if err = payload.processAssetFee(payload.Asset, payload.Statement.Fee, payload.FeeRate, payload.FeeLeadingZeros, blockHeight, dataStorage); err != nil {
 			return
@@ -126,6 +153,8 @@ func (payload *TransactionZetherPayload) IncludePayload(txHash []byte, payloadIn
 		return errors.New("publicKeyList was not precomputed")
 	}
 
+	stakedSender, unstakedRecipient := false, false
+
 	echangesAll := make([]*crypto.ElGamal, len(publicKeyList))
 	for i, publicKey := range publicKeyList {
 
@@ -154,6 +183,12 @@ func (payload *TransactionZetherPayload) IncludePayload(txHash []byte, payloadIn
 			if payload.PayloadScript == transaction_zether_payload_script.SCRIPT_STAKING && !reg.Staked {
 				return errors.New("Senders used in Staking requires all to be staked")
 			}
+			if payload.PayloadScript == transaction_zether_payload_script.SCRIPT_UNSTAKING && !reg.Staked {
+				return errors.New("Senders used in Unstaking requires all to be staked")
+			}
+			if reg.Staked {
+				stakedSender = true
+			}
 
 			verify := true
 			if payload.PayloadScript == transaction_zether_payload_script.SCRIPT_STAKING_REWARD && uint64(i) != payload.Extra.(*transaction_zether_payload_extra.TransactionZetherPayloadExtraStakingReward).TemporaryAccountRegistrationIndex {
@@ -193,8 +228,18 @@ func (payload *TransactionZetherPayload) IncludePayload(txHash []byte, payloadIn
 					update = true
 				}
 			} else { //recipient
+				if !reg.Staked {
+					unstakedRecipient = true
+				}
 				if payload.PayloadScript == transaction_zether_payload_script.SCRIPT_CONDITIONAL_PAYMENT { //nothing
 
+				} else if payload.PayloadScript == transaction_zether_payload_script.SCRIPT_UNSTAKING { //released after the cooldown
+					if reg.Staked {
+						return errors.New("Recipients used in Unstaking requires all to be unstaked")
+					}
+					if err = dataStorage.AddPendingUnstake(publicKey, echanges, blockHeight+config_stake.GetPendingUnstakeWindow(blockHeight)); err != nil {
+						return
+					}
 				} else if bytes.Equal(payload.Asset, config_coins.NATIVE_ASSET_FULL) && (reg.Staked || payload.PayloadScript == transaction_zether_payload_script.SCRIPT_STAKING_REWARD) {
 					if err = dataStorage.AddPendingStake(publicKey, echanges, blockHeight+config_stake.GetPendingStakeWindow(blockHeight)); err != nil {
 						return
@@ -215,6 +260,10 @@ func (payload *TransactionZetherPayload) IncludePayload(txHash []byte, payloadIn
 
 	}
 
+	if err = payload.checkStakedSenders(stakedSender, unstakedRecipient, blockHeight); err != nil {
+		return
+	}
+
 	if payload.PayloadScript == transaction_zether_payload_script.SCRIPT_CONDITIONAL_PAYMENT {
 		extra := payload.Extra.(*transaction_zether_payload_extra.TransactionZetherPayloadExtraConditionalPayment)
 		if err = dataStorage.AddConditionalPayment(blockHeight+extra.Deadline, txHash, payloadIndex, payload.Asset, extra.DefaultResolution, payload.Parity, publicKeyList, echangesAll, extra.MultisigThreshold, extra.MultisigPublicKeys); err != nil {
@@ -278,7 +327,7 @@ func (payload *TransactionZetherPayload) Validate(payloadIndex byte) (err error)
 
 	switch payload.PayloadScript {
 	case transaction_zether_payload_script.SCRIPT_TRANSFER:
-	case transaction_zether_payload_script.SCRIPT_STAKING, transaction_zether_payload_script.SCRIPT_STAKING_REWARD, transaction_zether_payload_script.SCRIPT_SPEND, transaction_zether_payload_script.SCRIPT_ASSET_CREATE, transaction_zether_payload_script.SCRIPT_ASSET_SUPPLY_INCREASE, transaction_zether_payload_script.SCRIPT_PLAIN_ACCOUNT_FUND, transaction_zether_payload_script.SCRIPT_CONDITIONAL_PAYMENT:
+	case transaction_zether_payload_script.SCRIPT_STAKING, transaction_zether_payload_script.SCRIPT_STAKING_REWARD, transaction_zether_payload_script.SCRIPT_SPEND, transaction_zether_payload_script.SCRIPT_ASSET_CREATE, transaction_zether_payload_script.SCRIPT_ASSET_SUPPLY_INCREASE, transaction_zether_payload_script.SCRIPT_PLAIN_ACCOUNT_FUND, transaction_zether_payload_script.SCRIPT_CONDITIONAL_PAYMENT, transaction_zether_payload_script.SCRIPT_UNSTAKING:
 		if payload.Extra == nil {
 			return errors.New("extra is not assigned")
 		}
@@ -357,6 +406,8 @@ func (payload *TransactionZetherPayload) Deserialize(r *advanced_buffers.BufferR
 		payload.Extra = &transaction_zether_payload_extra.TransactionZetherPayloadExtraSpend{}
 	case transaction_zether_payload_script.SCRIPT_CONDITIONAL_PAYMENT:
 		payload.Extra = &transaction_zether_payload_extra.TransactionZetherPayloadExtraConditionalPayment{}
+	case transaction_zether_payload_script.SCRIPT_UNSTAKING:
+		payload.Extra = &transaction_zether_payload_extra.TransactionZetherPayloadExtraUnstaking{}
 	default:
 		return errors.New("INVALID SCRIPT TYPE")
 	}
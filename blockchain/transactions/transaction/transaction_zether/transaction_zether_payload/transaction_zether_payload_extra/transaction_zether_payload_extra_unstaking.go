@@ -0,0 +1,57 @@
+package transaction_zether_payload_extra
+
+import (
+	"bytes"
+	"errors"
+	"pandora-pay/blockchain/data_storage"
+	"pandora-pay/blockchain/transactions/transaction/transaction_zether/transaction_zether_registrations"
+	"pandora-pay/config/config_coins"
+	"pandora-pay/cryptography/crypto"
+	"pandora-pay/helpers/advanced_buffers"
+)
+
+//TransactionZetherPayloadExtraUnstaking moves funds out of staked accounts. The recipients will receive them as pending unstakes after the cooldown window
+type TransactionZetherPayloadExtraUnstaking struct {
+	TransactionZetherPayloadExtraInterface
+}
+
+func (payloadExtra *TransactionZetherPayloadExtraUnstaking) BeforeIncludeTxPayload(txHash []byte, payloadRegistrations *transaction_zether_registrations.TransactionZetherDataRegistrations, payloadIndex byte, payloadAsset []byte, payloadBurnValue uint64, payloadStatement *crypto.Statement, publicKeyList [][]byte, blockHeight uint64, dataStorage *data_storage.DataStorage) error {
+	return nil
+}
+
+func (payloadExtra *TransactionZetherPayloadExtraUnstaking) AfterIncludeTxPayload(txHash []byte, payloadRegistrations *transaction_zether_registrations.TransactionZetherDataRegistrations, payloadIndex byte, payloadAsset []byte, payloadBurnValue uint64, payloadStatement *crypto.Statement, publicKeyList [][]byte, blockHeight uint64, dataStorage *data_storage.DataStorage) (err error) {
+	return
+}
+
+func (payloadExtra *TransactionZetherPayloadExtraUnstaking) ComputeAllKeys(out map[string]bool) {
+}
+
+func (payloadExtra *TransactionZetherPayloadExtraUnstaking) Validate(payloadRegistrations *transaction_zether_registrations.TransactionZetherDataRegistrations, payloadIndex byte, payloadAsset []byte, payloadBurnValue uint64, payloadStatement *crypto.Statement, payloadParity bool) (err error) {
+
+	if bytes.Equal(payloadAsset, config_coins.NATIVE_ASSET_FULL) == false {
+		return errors.New("Payload asset must be a native asset")
+	}
+
+	if payloadBurnValue != 0 {
+		return errors.New("Payload burn value must be zero")
+	}
+
+	for i, registration := range payloadRegistrations.Registrations {
+		if registration != nil && (i%2 == 0) != payloadParity && registration.RegistrationStaked {
+			return errors.New("Payload Extra Unstaking should not register staked recipients")
+		}
+	}
+
+	return
+}
+
+func (payloadExtra *TransactionZetherPayloadExtraUnstaking) Serialize(w *advanced_buffers.BufferWriter, inclSignature bool) {
+}
+
+func (payloadExtra *TransactionZetherPayloadExtraUnstaking) Deserialize(r *advanced_buffers.BufferReader) (err error) {
+	return
+}
+
+func (payloadExtra *TransactionZetherPayloadExtraUnstaking) UpdateStatement(payloadStatement *crypto.Statement) error {
+	return nil
+}
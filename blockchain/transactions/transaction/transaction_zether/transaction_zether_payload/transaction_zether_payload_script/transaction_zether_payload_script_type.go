@@ -11,6 +11,7 @@ const (
 	SCRIPT_ASSET_SUPPLY_INCREASE
 	SCRIPT_PLAIN_ACCOUNT_FUND
 	SCRIPT_CONDITIONAL_PAYMENT
+	SCRIPT_UNSTAKING
 )
 
 func (t PayloadScriptType) String() string {
@@ -31,6 +32,8 @@ func (t PayloadScriptType) String() string {
 		return "SCRIPT_PLAIN_ACCOUNT_FUND"
 	case SCRIPT_CONDITIONAL_PAYMENT:
 		return "SCRIPT_CONDITIONAL_PAYMENT"
+	case SCRIPT_UNSTAKING:
+		return "SCRIPT_UNSTAKING"
 	default:
 		return "Unknown ScriptType"
 	}
@@ -0,0 +1,35 @@
+package transaction_zether_payload
+
+import (
+	"github.com/stretchr/testify/assert"
+	"pandora-pay/blockchain/transactions/transaction/transaction_zether/transaction_zether_payload/transaction_zether_payload_script"
+	"pandora-pay/config/config_coins"
+	"pandora-pay/config/config_forks"
+	"testing"
+)
+
+func TestCheckStakedSenders(t *testing.T) {
+
+	height := config_forks.MAIN_NET_FORKS[1].Height
+
+	oldForks := config_forks.FORKS
+	defer func() { config_forks.FORKS = oldForks }()
+	config_forks.FORKS = config_forks.MAIN_NET_FORKS
+
+	transfer := &TransactionZetherPayload{PayloadScript: transaction_zether_payload_script.SCRIPT_TRANSFER, Asset: config_coins.NATIVE_ASSET_FULL}
+	assert.NotNil(t, transfer.checkStakedSenders(true, true, height))
+	assert.Nil(t, transfer.checkStakedSenders(true, true, height-1), "before the upgrade the staked funds were unstaked by transfers")
+	assert.Nil(t, transfer.checkStakedSenders(true, false, height), "staked recipients receive pending stakes")
+	assert.Nil(t, transfer.checkStakedSenders(false, true, height))
+
+	fund := &TransactionZetherPayload{PayloadScript: transaction_zether_payload_script.SCRIPT_PLAIN_ACCOUNT_FUND, Asset: config_coins.NATIVE_ASSET_FULL, BurnValue: 10}
+	assert.NotNil(t, fund.checkStakedSenders(true, false, height), "burning moves the staked funds without the cooldown")
+	assert.Nil(t, fund.checkStakedSenders(false, false, height))
+
+	unstaking := &TransactionZetherPayload{PayloadScript: transaction_zether_payload_script.SCRIPT_UNSTAKING, Asset: config_coins.NATIVE_ASSET_FULL}
+	assert.Nil(t, unstaking.checkStakedSenders(true, true, height))
+
+	asset := &TransactionZetherPayload{PayloadScript: transaction_zether_payload_script.SCRIPT_TRANSFER, Asset: make([]byte, config_coins.ASSET_LENGTH), BurnValue: 10}
+	asset.Asset[0] = 1
+	assert.Nil(t, asset.checkStakedSenders(true, true, height), "only the native asset is staked")
+}
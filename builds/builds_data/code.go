@@ -48,6 +48,8 @@ func PrepareData(data []byte) (txData *TransactionsBuilderCreateZetherTxReq, tra
 			txData.Payloads[t].Extra = &wizard.WizardZetherPayloadExtraPlainAccountFund{}
 		case transaction_zether_payload_script.SCRIPT_CONDITIONAL_PAYMENT:
 			txData.Payloads[t].Extra = &wizard.WizardZetherPayloadExtraConditionalPayment{}
+		case transaction_zether_payload_script.SCRIPT_UNSTAKING:
+			txData.Payloads[t].Extra = &wizard.WizardZetherPayloadExtraUnstaking{}
 		default:
 			err = errors.New("Invalid PayloadScriptType")
 			return
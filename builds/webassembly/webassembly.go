@@ -57,6 +57,7 @@ func initialize() {
 					"encryptWallet":          js.FuncOf(encryptWallet),
 					"decryptWallet":          js.FuncOf(decryptWallet),
 					"removeEncryptionWallet": js.FuncOf(removeEncryptionWallet),
+					"changePasswordWallet":   js.FuncOf(changePasswordWallet),
 					"logoutWallet":           js.FuncOf(logoutWallet),
 				}),
 				"setWalletNonHardening": js.FuncOf(setWalletNonHardening),
@@ -69,10 +70,12 @@ func initialize() {
 			"decryptTx":                       js.FuncOf(decryptTx),
 		}),
 		"addresses": js.ValueOf(map[string]interface{}{
-			"createAddress":      js.FuncOf(createAddress),
-			"decodeAddress":      js.FuncOf(decodeAddress),
-			"generateAddress":    js.FuncOf(generateAddress),
-			"generateNewAddress": js.FuncOf(generateNewAddress),
+			"createAddress":        js.FuncOf(createAddress),
+			"decodeAddress":        js.FuncOf(decodeAddress),
+			"generateAddress":      js.FuncOf(generateAddress),
+			"generateNewAddress":   js.FuncOf(generateNewAddress),
+			"encodePaymentRequest": js.FuncOf(encodePaymentRequest),
+			"decodePaymentRequest": js.FuncOf(decodePaymentRequest),
 		}),
 		"cryptography": js.ValueOf(map[string]interface{}{
 			"HASH_SIZE":            js.ValueOf(cryptography.HashSize),
@@ -151,6 +154,7 @@ func initialize() {
 						"SCRIPT_ASSET_SUPPLY_INCREASE": js.ValueOf(uint64(transaction_zether_payload_script.SCRIPT_ASSET_SUPPLY_INCREASE)),
 						"SCRIPT_PLAIN_ACCOUNT_FUND":    js.ValueOf(uint64(transaction_zether_payload_script.SCRIPT_PLAIN_ACCOUNT_FUND)),
 						"SCRIPT_CONDITIONAL_PAYMENT":   js.ValueOf(uint64(transaction_zether_payload_script.SCRIPT_CONDITIONAL_PAYMENT)),
+						"SCRIPT_UNSTAKING":             js.ValueOf(uint64(transaction_zether_payload_script.SCRIPT_UNSTAKING)),
 					}),
 				}),
 			}),
@@ -109,3 +109,47 @@ func generateAddress(this js.Value, args []js.Value) interface{} {
 		})
 	})
 }
+
+func encodePaymentRequest(this js.Value, args []js.Value) interface{} {
+	return webassembly_utils.PromiseFunction(func() (interface{}, error) {
+
+		parameters := struct {
+			Address   string `json:"address"`
+			Amount    uint64 `json:"amount"`
+			Asset     []byte `json:"asset"`
+			PaymentID []byte `json:"paymentID"`
+			Memo      string `json:"memo"`
+			Expiry    uint64 `json:"expiry"`
+		}{}
+
+		if err := webassembly_utils.UnmarshalBytes(args[0], &parameters); err != nil {
+			return nil, err
+		}
+
+		request, err := addresses.NewPaymentRequest(parameters.Address, parameters.Amount, parameters.Asset, parameters.PaymentID, parameters.Memo, parameters.Expiry)
+		if err != nil {
+			return nil, err
+		}
+
+		return webassembly_utils.ConvertJSONBytes([]interface{}{
+			request.EncodeURI(),
+			request.EncodeQR(),
+		})
+	})
+}
+
+func decodePaymentRequest(this js.Value, args []js.Value) interface{} {
+	return webassembly_utils.PromiseFunction(func() (interface{}, error) {
+
+		request, err := addresses.DecodePaymentRequestURI(args[0].String())
+		if err != nil {
+			return nil, err
+		}
+
+		return webassembly_utils.ConvertJSONBytes([]interface{}{
+			request,
+			request.Address.EncodeAddr(),
+			request.IsExpired(),
+		})
+	})
+}
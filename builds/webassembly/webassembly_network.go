@@ -16,6 +16,7 @@ import (
 	"pandora-pay/network/api/api_common"
 	"pandora-pay/network/api/api_common/api_faucet"
 	"pandora-pay/network/api/api_common/api_types"
+	"pandora-pay/network/light_wallet"
 	"pandora-pay/network/websocks/connection"
 	"pandora-pay/network/websocks/connection/advanced_connection_types"
 	"syscall/js"
@@ -110,12 +111,13 @@ func getNetworkAccountsKeysByIndex(this js.Value, args []js.Value) interface{} {
 func getNetworkAccountsByKeys(this js.Value, args []js.Value) interface{} {
 	return webassembly_utils.PromiseFunction(func() (interface{}, error) {
 
-		request := &api_common.APIAccountsByKeysRequest{nil, nil, false, api_types.RETURN_SERIALIZED}
+		request := &api_common.APIAccountsByKeysRequest{nil, nil, false, api_types.RETURN_SERIALIZED, false}
 		if err := webassembly_utils.UnmarshalBytes(args[0], request); err != nil {
 			return nil, err
 		}
 
-		data, err := connection.SendJSONAwaitAnswer[api_common.APIAccountsByKeysReply](app.Network.Websockets.GetFirstSocket(), []byte("accounts/by-keys"), request, nil, 0)
+		//mempool balances can't be proven, so they are refused
+		data, err := light_wallet.GetVerifiedAccountsByKeys(app.Network.Websockets, app.Chain, request)
 		if err != nil {
 			return nil, err
 		}
@@ -159,7 +161,7 @@ func getNetworkAccount(this js.Value, args []js.Value) interface{} {
 			return nil, err
 		}
 
-		result, err := connection.SendJSONAwaitAnswer[api_common.APIAccountReply](app.Network.Websockets.GetFirstSocket(), []byte("account"), request, nil, 0)
+		result, err := light_wallet.GetVerifiedAccount(app.Network.Websockets, app.Chain, request)
 		if err != nil {
 			return nil, err
 		}
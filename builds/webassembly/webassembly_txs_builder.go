@@ -78,7 +78,7 @@ func createSimpleTx(this js.Value, args []js.Value) interface{} {
 			transfer.Key = senderWalletAddr.PrivateKey.Key
 		}
 
-		tx, err := wizard.CreateSimpleTx(transfer, true, func(status string) {
+		tx, err := wizard.CreateSimpleTx(transfer, txData.Height, true, func(status string) {
 			args[1].Invoke(status)
 		})
 		if err != nil {
@@ -242,6 +242,15 @@ func removeEncryptionWallet(this js.Value, args []js.Value) interface{} {
 	})
 }
 
+func changePasswordWallet(this js.Value, args []js.Value) interface{} {
+	return webassembly_utils.PromiseFunction(func() (interface{}, error) {
+		if err := app.Wallet.Encryption.ChangePassword(args[0].String(), args[1].String(), args[2].Int()); err != nil {
+			return nil, err
+		}
+		return true, nil
+	})
+}
+
 func logoutWallet(this js.Value, args []js.Value) interface{} {
 	return webassembly_utils.PromiseFunction(func() (interface{}, error) {
 		if err := app.Wallet.Encryption.Logout(); err != nil {
@@ -8,7 +8,7 @@ package arguments
 const commands = `PANDORA PAY.
 
 Usage:
-  pandorapay [--pprof] [--network=network] [--debug] [--forging] [--new-devnet] [--run-testnet-script] [--node-name=name] [--tcp-server-port=port] [--tcp-server-address=address] [--tcp-server-auto-tls-certificate] [--tcp-server-tls-cert-file=path] [--tcp-server-tls-key-file=path] [--tor-onion=onion] [--instance=prefix] [--instance-id=id] [--set-genesis=genesis] [--create-new-genesis=args] [--store-wallet-type=type] [--store-chain-type=type] [--consensus=type] [--tcp-max-clients=limit] [--tcp-max-server-sockets=limit] [--seed-wallet-nodes-info=bool] [--wallet-encrypt=args] [--wallet-decrypt=password] [--wallet-remove-encryption] [--wallet-export-shared-staked-address=args] [--wallet-import-secret-mnemonic=mnemonic] [--wallet-import-secret-entropy=entropy] [--hcaptcha-secret=args] [--faucet-testnet-enabled=args] [--delegator-enabled=bool] [--delegator-require-auth=bool] [--delegates-maximum=args] [--auth-users=args] [--light-computations] [--balance-decryptor-disable-init] [--balance-decryptor-table-size=size] [--exit] [--skip-init-sync]
+  pandorapay [--pprof] [--network=network] [--debug] [--forging] [--forging-remote-signer=address] [--forging-signer-server=address] [--forging-signer-secret=secret] [--new-devnet] [--run-testnet-script] [--node-name=name] [--tcp-server-port=port] [--grpc-server-port=port] [--tcp-server-address=address] [--tcp-server-auto-tls-certificate] [--tcp-server-tls-cert-file=path] [--tcp-server-tls-key-file=path] [--tor-onion=onion] [--instance=prefix] [--instance-id=id] [--set-genesis=genesis] [--genesis-hash=hash] [--create-new-genesis=args] [--build-genesis=args] [--store-wallet-type=type] [--store-chain-type=type] [--consensus=type] [--tcp-max-clients=limit] [--tcp-max-server-sockets=limit] [--dns-seeds=list] [--seed-wallet-nodes-info=bool] [--state-root-assets=list] [--checkpoints=list] [--max-reorg-depth=blocks] [--api-rate-limit=args] [--wallet-encrypt=args] [--wallet-decrypt=password] [--wallet-remove-encryption] [--wallet-export-shared-staked-address=args] [--wallet-import-secret-mnemonic=mnemonic] [--wallet-import-secret-entropy=entropy] [--hcaptcha-secret=args] [--faucet-testnet-enabled=args] [--delegator-enabled=bool] [--delegator-require-auth=bool] [--delegates-maximum=args] [--delegator-fee=percent] [--delegates-inactivity=blocks] [--auth-users=args] [--auth-file=path] [--auth-hash-password=password] [--light-computations] [--balance-decryptor-disable-init] [--balance-decryptor-table-size=size] [--exit] [--skip-init-sync]
   pandorapay -h | --help
   pandorapay -v | --version
 
@@ -18,18 +18,25 @@ Options:
   --network=network                                  Select network. Accepted values: "mainnet|testnet|devnet". [default: mainnet]
   --new-devnet                                       Create a new devnet genesis.
   --run-testnet-script                               Run testnet script which will create dummy transactions in the network.
-  --set-genesis=genesis                              Manually set the Genesis via a JSON. By using argument "file" it will read it via a file.
+  --set-genesis=genesis                              Manually set the Genesis via a JSON. By using argument "file" it will read ./genesis.data and "file:path" will read it from path.
+  --genesis-hash=hash                                Reject a Genesis set by --set-genesis when its hash is different.
   --create-new-genesis=args                          Create a new Genesis. Useful for creating a new private testnet. Argument must be "0.stake,1.stake,2.stake"
+  --build-genesis=args                               Build a Genesis signed by the first wallet address from a JSON/YAML spec, print its hash and exit. Argument must be "spec.json,genesis.data"
   --store-wallet-type=type                           Set Wallet Store Type. Accepted values: "bolt|bunt|bunt-memory|memory". [default: bolt]
   --store-chain-type=type                            Set Chain Store Type. Accepted values: "bolt|bunt|bunt-memory|memory".  [default: bolt]
   --debug                                            Debug mode enabled (print log message).
   --forging                                          Start Forging blocks.
+  --forging-remote-signer=address                    Request the staking nonces and the block signatures from a remote signer. Address is unix:/path or tcp:host:port.
+  --forging-signer-server=address                    Run a remote signer for the wallet addresses. Address is unix:/path or tcp:host:port.
+  --forging-signer-secret=secret                     Shared secret used by the remote signer and the node to authenticate each other.
   --node-name=name                                   Change node name.
   --instance=prefix                                  Prefix of the instance [default: 0].
   --instance-id=id                                   Number of forked instance (when you open multiple instances). It should be a string number like "1","2","3","4" etc
   --tcp-server-port=port                             Change node tcp server port [default: 8080].
+  --grpc-server-port=port                            Open a gRPC server on the given port sharing the JSON-RPC methods and the TLS certificate.
   --tcp-max-clients=limit                            Change limit of clients [default: 50].
   --tcp-max-server-sockets=limit                     Change limit of servers [default: 500].
+  --dns-seeds=list                                   Replace the DNS seeds of the network. Comma separated host:port names, e.g. "seed.example.com:16000".
   --tcp-server-address=address                       Change node tcp address.
   --tcp-server-auto-tls-certificate                  If no certificate.crt is provided, this option will generate a valid TLS certificate via autocert package. You still need a valid domain provided and set --tcp-server-address.
   --tcp-server-tls-cert-file=path                    Load TLS certificate file from given path.
@@ -37,6 +44,10 @@ Options:
   --tor-onion=onion                                  Define your tor onion address to be used.
   --consensus=type                                   Consensus type. Accepted values: "full|wallet|none" [default: full].
   --seed-wallet-nodes-info=bool                      Storing and serving additional info to wallet nodes. [default: true]. To enable, it requires full node
+  --state-root-assets=list                           Assets, as hex, whose accounts are kept to serve proofs against the state root committed by the last block. At most 10 [default: the native asset].
+  --checkpoints=list                                 Extra checkpoints as height:hash,height:hash with hex hashes. Consensus never reorgs past a checkpoint.
+  --max-reorg-depth=blocks                           Maximum number of blocks that can be removed by a reorg. 0 means unlimited [default: 0].
+  --api-rate-limit=args                              API requests per second for every IP or authenticated user. Argument must be "cheap,expensive". 0 disables a limit [default: 100,5].
   --wallet-import-secret-mnemonic=mnemonic           Import Wallet from a given Mnemonic. It will delete your existing wallet. 
   --wallet-import-secret-entropy=entropy             Import Wallet from a given Entropy. It will delete your existing wallet.
   --wallet-encrypt=args                              Encrypt wallet. Argument must be "password,difficulty".
@@ -48,7 +59,11 @@ Options:
   --delegator-enabled=bool                           Enable Delegator. Will allow other users to Delegate to the node. Use "true" to enable it
   --delegator-require-auth=bool                      Delegator will require authentication.
   --delegates-maximum=args                           Maximum number of Delegates
-  --auth-users=args                                  Credential for Authenticated Users. Arguments must be a JSON "[{'user': 'username', 'pass': 'secret'}]".
+  --delegates-inactivity=blocks                      Remove Delegates that didn't forge or notify for a number of blocks. Use 0 to disable it. [default: 100000]
+  --delegator-fee=percent                            Percentage of the staking rewards of the Delegates kept by the Delegator. It is paid to the first wallet address, which must be staked. [default: 0]
+  --auth-users=args                                  Credential for Authenticated Users. Arguments must be a JSON "[{'user': 'username', 'pass': 'secret', 'roles': ['wallet']}]". Users without roles are admins.
+  --auth-file=path                                   JSON/YAML file with the users, their argon2 password hashes and roles: read-only, wallet, delegator, admin.
+  --auth-hash-password=password                      Print the argon2 hash of the password for --auth-file and exit.
   --light-computations                               Reduces the computations for a testnet node.
   --balance-decryptor-disable-init                   Disable first balance decryptor initialization. 
   --balance-decryptor-table-size=size                Balance Decryptor initial table size. [default: 23]
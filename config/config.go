@@ -1,16 +1,22 @@
 package config
 
 import (
+	"encoding/hex"
 	"errors"
+	"fmt"
 	"github.com/blang/semver/v4"
 	"math/big"
 	"math/rand"
 	"pandora-pay/config/config_auth"
+	"pandora-pay/config/config_checkpoints"
+	"pandora-pay/config/config_coins"
 	"pandora-pay/config/config_forging"
+	"pandora-pay/config/config_forks"
 	"pandora-pay/config/config_nodes"
 	"pandora-pay/config/globals"
 	"runtime"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -60,6 +66,7 @@ var (
 	NETWORK_SELECTED_BYTE_PREFIX     = MAIN_NET_NETWORK_BYTE_PREFIX
 	NETWORK_SELECTED_NAME            = MAIN_NET_NETWORK_NAME
 	NETWORK_SELECTED_SEEDS           = MAIN_NET_SEED_NODES
+	NETWORK_SELECTED_DNS_SEEDS       = MAIN_NET_DNS_SEEDS
 	NETWORK_SELECTED_DELEGATOR_NODES = config_nodes.MAIN_NET_DELEGATOR_NODES
 	WEBSOCKETS_NETWORK_CLIENTS_MAX   = int64(50)
 	WEBSOCKETS_NETWORK_SERVER_MAX    = int64(500)
@@ -79,6 +86,17 @@ var (
 	API_MEMPOOL_MAX_TRANSACTIONS = 50
 	API_ACCOUNT_MAX_TXS          = uint64(10)
 	API_ASSETS_INFO_MAX_RESULTS  = 10
+	API_GRAPHQL_MAX_LIST         = 100   //maximum count of the GraphQL list arguments
+	API_GRAPHQL_MAX_QUERY_LENGTH = 10000 //maximum length of a GraphQL query
+	API_GRAPHQL_MAX_DEPTH        = 10
+	API_GRAPHQL_MAX_COMPLEXITY   = 2000 //maximum number of objects a GraphQL query reads from the store
+)
+
+var (
+	WALLET_SCAN_GAP_LIMIT         = 20
+	WALLET_PAYMENTS_CONFIRMATIONS = uint64(10)
+	WALLET_FORGING_STATS_HISTORY  = 100   //number of forged blocks kept for every address
+	WALLET_TXS_INDEX_MAX          = 10000 //number of transactions with payments or messages kept by the wallet
 )
 
 var (
@@ -96,6 +114,14 @@ var (
 var (
 	CONSENSUS              ConsensusType = CONSENSUS_TYPE_FULL
 	SEED_WALLET_NODES_INFO bool
+	STATE_ROOT_ASSETS      = map[string]bool{string(config_coins.NATIVE_ASSET_FULL): true} //assets whose accounts are kept to serve state proofs
+	STATE_ROOT_MAX_ASSETS  = 10                                                            //assets that can be kept to serve state proofs, as every asset keeps all its accounts in memory
+)
+
+var (
+	API_RATE_LIMIT_CHEAP     uint64 = 100 //requests per second for every client. 0 disables the limit
+	API_RATE_LIMIT_EXPENSIVE uint64 = 5   //requests per second for every client. 0 disables the limit
+	API_RATE_LIMIT_BURST     uint64 = 2   //seconds of requests that can be consumed at once
 )
 
 var (
@@ -103,6 +129,17 @@ var (
 	NETWORK_WEBSOCKET_ADDRESS_URL_STRING string
 	NETWORK_KNOWN_NODES_LIMIT            int32 = 5000
 	NETWORK_KNOWN_NODES_LIST_RETURN            = 100
+	NETWORK_KNOWN_NODES_PER_HOST               = 8 //nodes on different ports of the same host
+)
+
+const (
+	NETWORK_DNS_SEEDS_INTERVAL             = 1 * time.Hour
+	NETWORK_DNS_SEEDS_TIMEOUT              = 10 * time.Second
+	NETWORK_PEER_EXCHANGE_INTERVAL         = 5 * time.Minute
+	NETWORK_PEER_EXCHANGE_MAX_NODES        = 20 //urls in a single push
+	NETWORK_PEER_EXCHANGE_RESOLVE_TIMEOUT  = 5 * time.Second
+	NETWORK_PEER_EXCHANGE_NEW_NODES_BUDGET = 50 //new known nodes a peer IP, or IPv6 /64, can add every window
+	NETWORK_PEER_EXCHANGE_BUDGET_WINDOW    = 1 * time.Hour
 )
 
 func InitConfig() (err error) {
@@ -112,19 +149,60 @@ func InitConfig() (err error) {
 	} else if globals.Arguments["--network"] == "testnet" {
 		NETWORK_SELECTED = TEST_NET_NETWORK_BYTE
 		NETWORK_SELECTED_SEEDS = TEST_NET_SEED_NODES
+		NETWORK_SELECTED_DNS_SEEDS = TEST_NET_DNS_SEEDS
 		NETWORK_SELECTED_DELEGATOR_NODES = config_nodes.TEST_NET_DELEGATOR_NODES
 		NETWORK_SELECTED_NAME = TEST_NET_NETWORK_NAME
 		NETWORK_SELECTED_BYTE_PREFIX = TEST_NET_NETWORK_BYTE_PREFIX
+		config_forks.FORKS = config_forks.TEST_NET_FORKS
+		config_checkpoints.CHECKPOINTS = config_checkpoints.TEST_NET_CHECKPOINTS
+		config_checkpoints.GENESIS_HASH = config_checkpoints.TEST_NET_GENESIS_HASH
 	} else if globals.Arguments["--network"] == "devnet" {
 		NETWORK_SELECTED = DEV_NET_NETWORK_BYTE
 		NETWORK_SELECTED_SEEDS = DEV_NET_SEED_NODES
+		NETWORK_SELECTED_DNS_SEEDS = DEV_NET_DNS_SEEDS
 		NETWORK_SELECTED_DELEGATOR_NODES = config_nodes.DEV_NET_DELEGATOR_NODES
 		NETWORK_SELECTED_NAME = DEV_NET_NETWORK_NAME
 		NETWORK_SELECTED_BYTE_PREFIX = DEV_NET_NETWORK_BYTE_PREFIX
+		config_forks.FORKS = config_forks.DEV_NET_FORKS
+		config_checkpoints.CHECKPOINTS = config_checkpoints.DEV_NET_CHECKPOINTS
+		config_checkpoints.GENESIS_HASH = config_checkpoints.DEV_NET_GENESIS_HASH
 	} else {
 		return errors.New("selected --network is invalid. Accepted only: mainnet, testnet, devnet")
 	}
 
+	if err = config_forks.ValidateForks(config_forks.FORKS); err != nil {
+		return
+	}
+
+	if err = config_checkpoints.ValidateCheckpoints(config_checkpoints.CHECKPOINTS); err != nil {
+		return
+	}
+
+	if globals.Arguments["--checkpoints"] != nil {
+		var checkpoints []*config_checkpoints.Checkpoint
+		if checkpoints, err = config_checkpoints.ParseCheckpoints(globals.Arguments["--checkpoints"].(string)); err != nil {
+			return
+		}
+		if err = config_checkpoints.AddCheckpoints(checkpoints); err != nil {
+			return
+		}
+	}
+
+	if globals.Arguments["--max-reorg-depth"] != nil {
+		if config_checkpoints.MAX_REORG_DEPTH, err = strconv.ParseUint(globals.Arguments["--max-reorg-depth"].(string), 10, 64); err != nil {
+			return
+		}
+	}
+
+	if globals.Arguments["--dns-seeds"] != nil {
+		NETWORK_SELECTED_DNS_SEEDS = nil
+		for _, seed := range strings.Split(globals.Arguments["--dns-seeds"].(string), ",") {
+			if seed = strings.TrimSpace(seed); seed != "" {
+				NETWORK_SELECTED_DNS_SEEDS = append(NETWORK_SELECTED_DNS_SEEDS, seed)
+			}
+		}
+	}
+
 	if globals.Arguments["--debug"] == true {
 		DEBUG = true
 	}
@@ -156,6 +234,36 @@ func InitConfig() (err error) {
 		return errors.New("invalid consensus argument")
 	}
 
+	if globals.Arguments["--state-root-assets"] != nil {
+		STATE_ROOT_ASSETS = make(map[string]bool)
+		for _, value := range strings.Split(globals.Arguments["--state-root-assets"].(string), ",") {
+			var asset []byte
+			if asset, err = hex.DecodeString(strings.TrimSpace(value)); err != nil {
+				return
+			}
+			if len(asset) != config_coins.ASSET_LENGTH {
+				return errors.New("--state-root-assets has an invalid asset")
+			}
+			STATE_ROOT_ASSETS[string(asset)] = true
+		}
+		if len(STATE_ROOT_ASSETS) > STATE_ROOT_MAX_ASSETS {
+			return fmt.Errorf("--state-root-assets accepts at most %d assets", STATE_ROOT_MAX_ASSETS)
+		}
+	}
+
+	if globals.Arguments["--api-rate-limit"] != nil {
+		values := strings.Split(globals.Arguments["--api-rate-limit"].(string), ",")
+		if len(values) != 2 {
+			return errors.New("--api-rate-limit must be cheap,expensive")
+		}
+		if API_RATE_LIMIT_CHEAP, err = strconv.ParseUint(values[0], 10, 64); err != nil {
+			return
+		}
+		if API_RATE_LIMIT_EXPENSIVE, err = strconv.ParseUint(values[1], 10, 64); err != nil {
+			return
+		}
+	}
+
 	if globals.Arguments["--light-computations"] == true {
 		LIGHT_COMPUTATIONS = true
 	}
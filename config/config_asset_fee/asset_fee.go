@@ -1,12 +1,15 @@
 package config_asset_fee
 
-import "pandora-pay/config/config_coins"
+import (
+	"pandora-pay/config/config_coins"
+	"pandora-pay/config/config_forks"
+)
 
 func GetRequiredAssetFee(blockHeight uint64) (requiredAssetFee uint64) {
 
 	var err error
 
-	if requiredAssetFee, err = config_coins.ConvertToUnitsUint64(100); err != nil {
+	if requiredAssetFee, err = config_coins.ConvertToUnitsUint64(config_forks.GetRules(blockHeight).RequiredAssetFee); err != nil {
 		panic(err)
 	}
 
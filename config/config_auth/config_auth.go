@@ -1,30 +1,212 @@
 package config_auth
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"io/ioutil"
 	"pandora-pay/config/globals"
+	"pandora-pay/helpers"
+	"pandora-pay/helpers/generics"
+	"path/filepath"
+	"strings"
 )
 
+type Role string
+
+const (
+	ROLE_READ_ONLY Role = "read-only" //reads the wallet
+	ROLE_WALLET    Role = "wallet"    //reads the wallet, creates addresses and transfers
+	ROLE_DELEGATOR Role = "delegator" //notifies and revokes delegates
+	ROLE_ADMIN     Role = "admin"     //everything, including issuing and revoking tokens
+)
+
+func (role Role) Validate() error {
+	switch role {
+	case ROLE_READ_ONLY, ROLE_WALLET, ROLE_DELEGATOR, ROLE_ADMIN:
+		return nil
+	default:
+		return fmt.Errorf("Invalid role %s", role)
+	}
+}
+
+//includes returns true if the role grants the permissions of the other role
+func (role Role) includes(other Role) bool {
+	return role == other || role == ROLE_ADMIN || (role == ROLE_WALLET && other == ROLE_READ_ONLY)
+}
+
+//ConfigAuth is a user. Password is the legacy plain text password accepted only from --auth-users
 type ConfigAuth struct {
+	Username     string `json:"user" msgpack:"user" yaml:"user"`
+	Password     string `json:"pass,omitempty" msgpack:"pass,omitempty" yaml:"pass,omitempty"`
+	PasswordHash string `json:"passHash,omitempty" msgpack:"passHash,omitempty" yaml:"passHash,omitempty"`
+	Roles        []Role `json:"roles,omitempty" msgpack:"roles,omitempty" yaml:"roles,omitempty"`
+}
+
+type ConfigAuthFile struct {
+	Users []*ConfigAuth `json:"users" yaml:"users"`
+}
+
+//Principal is the user or the token that authenticated a request
+type Principal struct {
 	Username string `json:"user" msgpack:"user"`
-	Password string `json:"pass"  msgpack:"pass"`
+	Roles    []Role `json:"roles" msgpack:"roles"`
+	TokenId  string `json:"tokenId,omitempty" msgpack:"tokenId,omitempty"`
+}
+
+func (principal *Principal) HasRole(role Role) bool {
+	if principal == nil {
+		return false
+	}
+	for _, it := range principal.Roles {
+		if it.includes(role) {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	ErrInvalidCredentials = errors.New("Invalid User or Password")
+	ErrPermissionDenied   = errors.New("Permission denied")
+)
+
+//Authorize returns an error if the principal is missing or it doesn't have the role
+func (principal *Principal) Authorize(role Role) error {
+	if principal == nil {
+		return ErrInvalidCredentials
+	}
+	if !principal.HasRole(role) {
+		return fmt.Errorf("%w. Role %s is required", ErrPermissionDenied, role)
+	}
+	return nil
 }
 
 var (
 	CONFIG_AUTH_USERS_LIST []*ConfigAuth
 	CONFIG_AUTH_USERS_MAP  map[string]*ConfigAuth
+	verifiedPasswords      = &generics.Map[string, bool]{}
+	verifiedPasswordsKey   = helpers.RandomBytes(32) //the digests of the verified passwords can't be brute forced without the key of the process
 )
 
+func (user *ConfigAuth) validate() error {
+	if user.Username == "" {
+		return errors.New("Auth user is missing the name")
+	}
+	if user.Password == "" && user.PasswordHash == "" {
+		return fmt.Errorf("Auth user %s is missing the password", user.Username)
+	}
+	for _, role := range user.Roles {
+		if err := role.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func getPasswordDigest(user *ConfigAuth, password string) string {
+	mac := hmac.New(sha256.New, verifiedPasswordsKey)
+	mac.Write([]byte(user.PasswordHash))
+	mac.Write([]byte{0})
+	mac.Write([]byte(password))
+	return string(mac.Sum(nil))
+}
+
+//IsVerificationRequired returns true when authenticating the credentials will run argon2. The callers rate limit these logins
+func IsVerificationRequired(username, password string) bool {
+	user := CONFIG_AUTH_USERS_MAP[username]
+	if user == nil || password == "" || user.PasswordHash == "" {
+		return false
+	}
+	_, ok := verifiedPasswords.Load(getPasswordDigest(user, password))
+	return !ok
+}
+
+//Authenticate returns the principal of the user or nil if the credentials are invalid
+func Authenticate(username, password string) *Principal {
+
+	user := CONFIG_AUTH_USERS_MAP[username]
+	if user == nil || password == "" {
+		return nil
+	}
+
+	if user.PasswordHash != "" {
+		//argon2 is expensive. The passwords already verified are remembered by their digest
+		digest := getPasswordDigest(user, password)
+		if _, ok := verifiedPasswords.Load(digest); !ok {
+			if !VerifyPassword(user.PasswordHash, password) {
+				return nil
+			}
+			verifiedPasswords.Store(digest, true)
+		}
+	} else if user.Password != password {
+		return nil
+	}
+
+	return &Principal{user.Username, user.Roles, ""}
+}
+
+func loadAuthFile(filename string) ([]*ConfigAuth, error) {
+
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	file := &ConfigAuthFile{}
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, file)
+	default:
+		err = json.Unmarshal(data, file)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, user := range file.Users {
+		if user.Password != "" {
+			return nil, fmt.Errorf("Auth user %s must use passHash in %s", user.Username, filename)
+		}
+	}
+
+	return file.Users, nil
+}
+
 func InitConfig() (err error) {
 
+	CONFIG_AUTH_USERS_LIST = nil
+
 	if str := globals.Arguments["--auth-users"]; str != nil {
 		if err = json.Unmarshal([]byte(str.(string)), &CONFIG_AUTH_USERS_LIST); err != nil {
 			return
 		}
+		for _, user := range CONFIG_AUTH_USERS_LIST {
+			if len(user.Roles) == 0 { //users defined before roles existed had access to everything
+				user.Roles = []Role{ROLE_ADMIN}
+			}
+		}
+	}
+
+	if str := globals.Arguments["--auth-file"]; str != nil {
+		var users []*ConfigAuth
+		if users, err = loadAuthFile(str.(string)); err != nil {
+			return
+		}
+		CONFIG_AUTH_USERS_LIST = append(CONFIG_AUTH_USERS_LIST, users...)
 	}
 
 	CONFIG_AUTH_USERS_MAP = map[string]*ConfigAuth{}
 	for _, auth := range CONFIG_AUTH_USERS_LIST {
+		if err = auth.validate(); err != nil {
+			return
+		}
+		if CONFIG_AUTH_USERS_MAP[auth.Username] != nil {
+			return fmt.Errorf("Auth user %s is duplicated", auth.Username)
+		}
 		CONFIG_AUTH_USERS_MAP[auth.Username] = auth
 	}
 
@@ -0,0 +1,89 @@
+package config_auth
+
+import (
+	"github.com/stretchr/testify/assert"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_memory"
+	"testing"
+	"time"
+)
+
+func TestAuthenticate(t *testing.T) {
+
+	hash, err := HashPassword("secret")
+	assert.NoError(t, err)
+	assert.True(t, VerifyPassword(hash, "secret"))
+	assert.False(t, VerifyPassword(hash, "secret2"))
+	assert.False(t, VerifyPassword("secret", "secret"))
+
+	oldMap := CONFIG_AUTH_USERS_MAP
+	defer func() { CONFIG_AUTH_USERS_MAP = oldMap }()
+
+	CONFIG_AUTH_USERS_MAP = map[string]*ConfigAuth{
+		"reader": {"reader", "", hash, []Role{ROLE_READ_ONLY}},
+		"legacy": {"legacy", "pass", "", []Role{ROLE_ADMIN}},
+	}
+
+	assert.Nil(t, Authenticate("reader", "wrong"))
+	assert.Nil(t, Authenticate("unknown", "secret"))
+	assert.Nil(t, Authenticate("legacy", ""))
+
+	reader := Authenticate("reader", "secret")
+	assert.NotNil(t, reader)
+	assert.NotNil(t, Authenticate("reader", "secret"), "verified passwords are remembered")
+	assert.NoError(t, reader.Authorize(ROLE_READ_ONLY))
+	assert.Error(t, reader.Authorize(ROLE_WALLET))
+
+	admin := Authenticate("legacy", "pass")
+	assert.NoError(t, admin.Authorize(ROLE_WALLET))
+	assert.NoError(t, admin.Authorize(ROLE_DELEGATOR))
+
+	var anonymous *Principal
+	assert.Error(t, anonymous.Authorize(ROLE_READ_ONLY))
+}
+
+func TestTokens(t *testing.T) {
+
+	db, err := store_db_memory.CreateStoreDBMemory("settings")
+	assert.NoError(t, err)
+	store.StoreSettings = &store.Store{Name: "settings", Opened: true, DB: db}
+	assert.NoError(t, LoadTokens())
+
+	admin := &Principal{"admin", []Role{ROLE_ADMIN}, ""}
+	reader := &Principal{"reader", []Role{ROLE_READ_ONLY}, ""}
+
+	_, _, err = IssueToken(reader, []Role{ROLE_WALLET}, 0)
+	assert.Error(t, err, "a token can't escalate the roles")
+	_, _, err = IssueToken(admin, []Role{}, 0)
+	assert.Error(t, err)
+	_, _, err = IssueToken(admin, []Role{ROLE_WALLET}, time.Now().Unix()-1)
+	assert.Error(t, err)
+
+	secret, token, err := IssueToken(admin, []Role{ROLE_WALLET}, 0)
+	assert.NoError(t, err)
+
+	principal := AuthenticateToken(secret)
+	assert.NotNil(t, principal)
+	assert.Equal(t, token.Id, principal.TokenId)
+	assert.NoError(t, principal.Authorize(ROLE_READ_ONLY))
+	assert.Error(t, principal.Authorize(ROLE_ADMIN))
+	assert.Nil(t, AuthenticateToken(secret+"0"))
+
+	other, _, err := IssueToken(admin, []Role{ROLE_READ_ONLY}, 0)
+	assert.NoError(t, err)
+
+	ok, err := RevokeToken(token.Id)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	ok, err = RevokeToken(token.Id)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	assert.Nil(t, AuthenticateToken(secret))
+
+	//after a restart the issued tokens are kept and the revoked ones stay revoked
+	tokens = map[string]*APIToken{}
+	assert.NoError(t, LoadTokens())
+	assert.Nil(t, AuthenticateToken(secret))
+	assert.NotNil(t, AuthenticateToken(other))
+	assert.Len(t, GetTokens(), 1)
+}
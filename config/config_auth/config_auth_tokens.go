@@ -0,0 +1,178 @@
+package config_auth
+
+import (
+	"encoding/hex"
+	"errors"
+	"github.com/vmihailenco/msgpack/v5"
+	"pandora-pay/cryptography"
+	"pandora-pay/helpers"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_interface"
+	"sort"
+	"sync"
+	"time"
+)
+
+//APIToken is a bearer token. Only the hash of the secret is kept, the secret is returned once when the token is issued
+type APIToken struct {
+	Id        string `json:"id" msgpack:"id"`
+	Username  string `json:"user" msgpack:"user"`
+	Roles     []Role `json:"roles" msgpack:"roles"`
+	CreatedAt int64  `json:"createdAt" msgpack:"createdAt"`
+	ExpiresAt int64  `json:"expiresAt,omitempty" msgpack:"expiresAt,omitempty"` //0 means it never expires
+	hash      string
+}
+
+//the tokens are stored by the hash of their secret. A revoked token is removed from the store
+const tokensStoreKey = "authTokens"
+
+var (
+	tokens     = map[string]*APIToken{} //by hash
+	tokensLock = &sync.RWMutex{}
+)
+
+//LoadTokens reads the tokens issued before the restart. The expired tokens are dropped
+func LoadTokens() error {
+
+	tokensLock.Lock()
+	defer tokensLock.Unlock()
+
+	loaded := map[string]*APIToken{}
+	if err := store.StoreSettings.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) error {
+		if data := reader.Get(tokensStoreKey); data != nil {
+			return msgpack.Unmarshal(data, &loaded)
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	now := time.Now().Unix()
+	tokens = map[string]*APIToken{}
+	for hash, token := range loaded {
+		if token.ExpiresAt == 0 || token.ExpiresAt > now {
+			token.hash = hash
+			tokens[hash] = token
+		}
+	}
+
+	return saveTokens()
+}
+
+//saveTokens must be called with tokensLock locked
+func saveTokens() error {
+	data, err := msgpack.Marshal(tokens)
+	if err != nil {
+		return err
+	}
+	return store.StoreSettings.DB.Update(func(writer store_db_interface.StoreDBTransactionInterface) error {
+		writer.Put(tokensStoreKey, data)
+		return nil
+	})
+}
+
+func hashToken(secret string) string {
+	return hex.EncodeToString(cryptography.SHA3([]byte(secret)))
+}
+
+//IssueToken creates a token for the issuer. The token can't have roles that the issuer doesn't have
+func IssueToken(issuer *Principal, roles []Role, expiresAt int64) (string, *APIToken, error) {
+
+	if len(roles) == 0 {
+		return "", nil, errors.New("Token requires at least one role")
+	}
+	for _, role := range roles {
+		if err := role.Validate(); err != nil {
+			return "", nil, err
+		}
+		if !issuer.HasRole(role) {
+			return "", nil, errors.New("Token can't have roles that the issuer doesn't have")
+		}
+	}
+
+	now := time.Now().Unix()
+	if expiresAt != 0 && expiresAt <= now {
+		return "", nil, errors.New("Token expiration is in the past")
+	}
+
+	secret := hex.EncodeToString(helpers.RandomBytes(32))
+	token := &APIToken{
+		hex.EncodeToString(helpers.RandomBytes(8)),
+		issuer.Username,
+		roles,
+		now,
+		expiresAt,
+		hashToken(secret),
+	}
+
+	tokensLock.Lock()
+	defer tokensLock.Unlock()
+
+	tokens[token.hash] = token
+	if err := saveTokens(); err != nil {
+		delete(tokens, token.hash)
+		return "", nil, err
+	}
+
+	return secret, token, nil
+}
+
+//RevokeToken removes the token from the store, so it stays revoked after a restart
+func RevokeToken(id string) (bool, error) {
+	tokensLock.Lock()
+	defer tokensLock.Unlock()
+
+	for hash, token := range tokens {
+		if token.Id == id {
+			delete(tokens, hash)
+			if err := saveTokens(); err != nil {
+				tokens[hash] = token
+				return false, err
+			}
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func GetTokens() []*APIToken {
+	tokensLock.RLock()
+	defer tokensLock.RUnlock()
+
+	list := make([]*APIToken, 0, len(tokens))
+	for _, token := range tokens {
+		list = append(list, token)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].CreatedAt < list[j].CreatedAt
+	})
+	return list
+}
+
+//AuthenticateToken returns the principal of the token or nil if the token is unknown or expired
+func AuthenticateToken(secret string) *Principal {
+
+	if secret == "" {
+		return nil
+	}
+
+	hash := hashToken(secret)
+
+	tokensLock.RLock()
+	token := tokens[hash]
+	tokensLock.RUnlock()
+
+	if token == nil {
+		return nil
+	}
+
+	if token.ExpiresAt != 0 && token.ExpiresAt <= time.Now().Unix() {
+		tokensLock.Lock()
+		delete(tokens, hash)
+		saveTokens() //the expired tokens are also dropped by LoadTokens
+		tokensLock.Unlock()
+		return nil
+	}
+
+	return &Principal{token.Username, token.Roles, token.Id}
+}
@@ -0,0 +1,166 @@
+package config_checkpoints
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"pandora-pay/cryptography"
+	"pandora-pay/helpers"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//Checkpoint pins the hash of the block at Height. Consensus never accepts a different block at that height and never reorgs past it
+type Checkpoint struct {
+	Height uint64 `json:"height" msgpack:"height"`
+	Hash   []byte `json:"hash" msgpack:"hash"`
+}
+
+//hardcoded checkpoints must be sorted ascending by height
+var (
+	MAIN_NET_CHECKPOINTS = []*Checkpoint{}
+	TEST_NET_CHECKPOINTS = []*Checkpoint{}
+	DEV_NET_CHECKPOINTS  = []*Checkpoint{}
+)
+
+//genesis hashes of the released networks. The mainnet genesis is not released yet and the devnets create their own genesis
+var (
+	MAIN_NET_GENESIS_HASH []byte
+	TEST_NET_GENESIS_HASH = helpers.DecodeHex("f4a2f9d1a71d1dfc448be029e381df81acc2e80ebf3607e51c60f085b16ca34b")
+	DEV_NET_GENESIS_HASH  []byte
+)
+
+var (
+	GENESIS_HASH           = MAIN_NET_GENESIS_HASH
+	CHECKPOINTS            = MAIN_NET_CHECKPOINTS
+	MAX_REORG_DEPTH uint64 = 0 //0 means unlimited
+)
+
+func ValidateCheckpoints(checkpoints []*Checkpoint) error {
+	for i, checkpoint := range checkpoints {
+		if len(checkpoint.Hash) != cryptography.HashSize {
+			return fmt.Errorf("Checkpoint %d hash is invalid", checkpoint.Height)
+		}
+		if i > 0 && checkpoint.Height <= checkpoints[i-1].Height {
+			return fmt.Errorf("Checkpoint %d must be after %d", checkpoint.Height, checkpoints[i-1].Height)
+		}
+	}
+	return nil
+}
+
+//ParseCheckpoints parses a list like height:hash,height:hash with the hashes encoded in hex
+func ParseCheckpoints(data string) ([]*Checkpoint, error) {
+
+	out := make([]*Checkpoint, 0)
+	for _, part := range strings.Split(data, ",") {
+
+		if part = strings.TrimSpace(part); part == "" {
+			continue
+		}
+
+		values := strings.Split(part, ":")
+		if len(values) != 2 {
+			return nil, fmt.Errorf("Checkpoint %s is invalid. Expected height:hash", part)
+		}
+
+		height, err := strconv.ParseUint(values[0], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+
+		hash, err := hex.DecodeString(values[1])
+		if err != nil {
+			return nil, err
+		}
+
+		out = append(out, &Checkpoint{height, hash})
+	}
+
+	return out, nil
+}
+
+//AddCheckpoints merges extra checkpoints into CHECKPOINTS. A height that is already pinned must have the same hash
+func AddCheckpoints(checkpoints []*Checkpoint) error {
+
+	list := append([]*Checkpoint{}, CHECKPOINTS...)
+	for _, checkpoint := range checkpoints {
+		if existing := getCheckpoint(list, checkpoint.Height); existing != nil {
+			if !bytes.Equal(existing.Hash, checkpoint.Hash) {
+				return fmt.Errorf("Checkpoint %d conflicts with an existing one", checkpoint.Height)
+			}
+			continue
+		}
+		list = append(list, checkpoint)
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Height < list[j].Height
+	})
+
+	if err := ValidateCheckpoints(list); err != nil {
+		return err
+	}
+
+	CHECKPOINTS = list
+	return nil
+}
+
+func getCheckpoint(checkpoints []*Checkpoint, blockHeight uint64) *Checkpoint {
+	for _, checkpoint := range checkpoints {
+		if checkpoint.Height == blockHeight {
+			return checkpoint
+		}
+	}
+	return nil
+}
+
+func GetCheckpoint(blockHeight uint64) *Checkpoint {
+	return getCheckpoint(CHECKPOINTS, blockHeight)
+}
+
+//GetLastCheckpoint returns the highest checkpoint below chainHeight (the number of blocks)
+func GetLastCheckpoint(chainHeight uint64) (last *Checkpoint) {
+	for _, checkpoint := range CHECKPOINTS {
+		if checkpoint.Height >= chainHeight {
+			break
+		}
+		last = checkpoint
+	}
+	return
+}
+
+//VerifyGenesisHash rejects a genesis that is different from the one pinned for the network
+func VerifyGenesisHash(hash []byte) error {
+	if GENESIS_HASH != nil && !bytes.Equal(GENESIS_HASH, hash) {
+		return errors.New("Genesis doesn't match the checkpoint")
+	}
+	return nil
+}
+
+//VerifyBlockHash rejects a block that is different from the one pinned at its height
+func VerifyBlockHash(blockHeight uint64, hash []byte) error {
+	if checkpoint := GetCheckpoint(blockHeight); checkpoint != nil && !bytes.Equal(checkpoint.Hash, hash) {
+		return fmt.Errorf("Block %d doesn't match the checkpoint", blockHeight)
+	}
+	return nil
+}
+
+//ValidateReorg checks that the blocks starting with removedHeight can be removed from a chain of chainHeight blocks
+func ValidateReorg(chainHeight, removedHeight uint64) error {
+
+	if removedHeight >= chainHeight {
+		return nil
+	}
+
+	if MAX_REORG_DEPTH > 0 && chainHeight-removedHeight > MAX_REORG_DEPTH {
+		return fmt.Errorf("Reorg of %d blocks exceeds the maximum reorg depth %d", chainHeight-removedHeight, MAX_REORG_DEPTH)
+	}
+
+	if last := GetLastCheckpoint(chainHeight); last != nil && removedHeight <= last.Height {
+		return errors.New("Reorg would remove a checkpointed block")
+	}
+
+	return nil
+}
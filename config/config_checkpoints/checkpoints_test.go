@@ -0,0 +1,54 @@
+package config_checkpoints
+
+import (
+	"encoding/hex"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"pandora-pay/cryptography"
+	"testing"
+)
+
+func TestCheckpoints(t *testing.T) {
+
+	assert.NoError(t, ValidateCheckpoints(MAIN_NET_CHECKPOINTS))
+	assert.NoError(t, ValidateCheckpoints(TEST_NET_CHECKPOINTS))
+	assert.NoError(t, ValidateCheckpoints(DEV_NET_CHECKPOINTS))
+
+	oldCheckpoints, oldDepth := CHECKPOINTS, MAX_REORG_DEPTH
+	defer func() { CHECKPOINTS, MAX_REORG_DEPTH = oldCheckpoints, oldDepth }()
+
+	CHECKPOINTS = []*Checkpoint{}
+	MAX_REORG_DEPTH = 0
+
+	hash100, hash50 := cryptography.RandomHash(), cryptography.RandomHash()
+
+	checkpoints, err := ParseCheckpoints(fmt.Sprintf("100:%s, 50:%s", hex.EncodeToString(hash100), hex.EncodeToString(hash50)))
+	assert.NoError(t, err)
+	assert.NoError(t, AddCheckpoints(checkpoints))
+	assert.Equal(t, uint64(50), CHECKPOINTS[0].Height)
+	assert.Equal(t, uint64(100), CHECKPOINTS[1].Height)
+
+	assert.NoError(t, AddCheckpoints([]*Checkpoint{{100, hash100}}))
+	assert.Error(t, AddCheckpoints([]*Checkpoint{{100, hash50}}))
+
+	_, err = ParseCheckpoints("100")
+	assert.Error(t, err)
+	_, err = ParseCheckpoints("100:zz")
+	assert.Error(t, err)
+
+	assert.NoError(t, VerifyBlockHash(100, hash100))
+	assert.Error(t, VerifyBlockHash(100, hash50))
+	assert.NoError(t, VerifyBlockHash(101, hash50))
+
+	assert.Nil(t, GetLastCheckpoint(50))
+	assert.Equal(t, uint64(50), GetLastCheckpoint(51).Height)
+	assert.Equal(t, uint64(100), GetLastCheckpoint(1000).Height)
+
+	assert.NoError(t, ValidateReorg(1000, 101))
+	assert.Error(t, ValidateReorg(1000, 100))
+	assert.NoError(t, ValidateReorg(100, 60))
+
+	MAX_REORG_DEPTH = 10
+	assert.NoError(t, ValidateReorg(1000, 990))
+	assert.Error(t, ValidateReorg(1000, 989))
+}
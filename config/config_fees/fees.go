@@ -1,10 +1,18 @@
 package config_fees
 
-var (
-	FEE_PER_BYTE             = uint64(10)
-	FEE_PER_BYTE_ZETHER      = uint64(20)
-	FEE_PER_BYTE_EXTRA_SPACE = uint64(100)
-)
+import "pandora-pay/config/config_forks"
+
+func GetFeePerByte(blockHeight uint64) uint64 {
+	return config_forks.GetRules(blockHeight).FeePerByte
+}
+
+func GetFeePerByteZether(blockHeight uint64) uint64 {
+	return config_forks.GetRules(blockHeight).FeePerByteZether
+}
+
+func GetFeePerByteExtraSpace(blockHeight uint64) uint64 {
+	return config_forks.GetRules(blockHeight).FeePerByteExtraSpace
+}
 
 func ComputeTxFee(size, feePerByte, extraSpace, feePerByeExtraSpace uint64) uint64 {
 	return size*feePerByte + extraSpace*feePerByeExtraSpace
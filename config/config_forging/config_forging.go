@@ -1,9 +1,19 @@
 package config_forging
 
-import "pandora-pay/config/globals"
+import (
+	"errors"
+	"pandora-pay/config/globals"
+)
 
 var (
 	FORGING_ENABLED = true
+
+	/* FORGING_REMOTE_SIGNER
+	the staking nonces and the block signatures are requested from a separate signer process. Format network:address (unix:/path or tcp:host:port)
+	*/
+	FORGING_REMOTE_SIGNER = ""
+	FORGING_SIGNER_SERVER = ""
+	FORGING_SIGNER_SECRET = "" //shared secret used by both the signer and the node to authenticate each other
 )
 
 func InitConfig() (err error) {
@@ -12,5 +22,19 @@ func InitConfig() (err error) {
 		FORGING_ENABLED = false
 	}
 
+	if globals.Arguments["--forging-remote-signer"] != nil {
+		FORGING_REMOTE_SIGNER = globals.Arguments["--forging-remote-signer"].(string)
+	}
+	if globals.Arguments["--forging-signer-server"] != nil {
+		FORGING_SIGNER_SERVER = globals.Arguments["--forging-signer-server"].(string)
+	}
+	if globals.Arguments["--forging-signer-secret"] != nil {
+		FORGING_SIGNER_SECRET = globals.Arguments["--forging-signer-secret"].(string)
+	}
+
+	if (FORGING_REMOTE_SIGNER != "" || FORGING_SIGNER_SERVER != "") && len(FORGING_SIGNER_SECRET) < 16 {
+		return errors.New("--forging-signer-secret must have at least 16 characters")
+	}
+
 	return
 }
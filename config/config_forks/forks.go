@@ -0,0 +1,168 @@
+package config_forks
+
+import (
+	"errors"
+	"fmt"
+)
+
+//ForkRules are the consensus parameters introduced by an upgrade. Amounts are expressed in coins
+type ForkRules struct {
+	RequiredStake        uint64 `json:"requiredStake" msgpack:"requiredStake"`
+	BlockReward          uint64 `json:"blockReward" msgpack:"blockReward"`
+	RequiredAssetFee     uint64 `json:"requiredAssetFee" msgpack:"requiredAssetFee"`
+	FeePerByte           uint64 `json:"feePerByte" msgpack:"feePerByte"`
+	FeePerByteZether     uint64 `json:"feePerByteZether" msgpack:"feePerByteZether"`
+	FeePerByteExtraSpace uint64 `json:"feePerByteExtraSpace" msgpack:"feePerByteExtraSpace"`
+	BlockVersion         uint64 `json:"blockVersion" msgpack:"blockVersion"`
+	TxVersionMax         uint64 `json:"txVersionMax" msgpack:"txVersionMax"`                 //highest transaction_type.TransactionVersion accepted
+	PayloadScriptMax     uint64 `json:"payloadScriptMax" msgpack:"payloadScriptMax"`         //highest transaction_zether_payload_script.PayloadScriptType accepted
+	StakedSendersChecks  bool   `json:"stakedSendersChecks" msgpack:"stakedSendersChecks"`   //staked funds can leave only by Unstaking
+	PendingStakeWindow   uint64 `json:"pendingStakeWindow" msgpack:"pendingStakeWindow"`     //blocks until the received stakes are staked
+	PendingUnstakeWindow uint64 `json:"pendingUnstakeWindow" msgpack:"pendingUnstakeWindow"` //blocks until the unstaked funds are released
+}
+
+//Fork is a named network upgrade that activates its rules starting with the block Height
+type Fork struct {
+	Name   string     `json:"name" msgpack:"name"`
+	Height uint64     `json:"height" msgpack:"height"`
+	Rules  *ForkRules `json:"rules" msgpack:"rules"`
+}
+
+const (
+	FORK_GENESIS    = "genesis"
+	FORK_UNSTAKING  = "unstaking"
+	FORK_STATE_ROOT = "stateRoot"
+)
+
+var genesisRules = &ForkRules{
+	RequiredStake:        100,
+	BlockReward:          4000,
+	RequiredAssetFee:     100,
+	FeePerByte:           10,
+	FeePerByteZether:     20,
+	FeePerByteExtraSpace: 100,
+	BlockVersion:         0,
+	TxVersionMax:         1,
+	PayloadScriptMax:     7,
+	StakedSendersChecks:  false,
+	PendingStakeWindow:   60,
+	PendingUnstakeWindow: 0,
+}
+
+//unstakingRules activate SCRIPT_UNSTAKING and its cooldown
+var unstakingRules = &ForkRules{
+	RequiredStake:        100,
+	BlockReward:          4000,
+	RequiredAssetFee:     100,
+	FeePerByte:           10,
+	FeePerByteZether:     20,
+	FeePerByteExtraSpace: 100,
+	BlockVersion:         0,
+	TxVersionMax:         1,
+	PayloadScriptMax:     8,
+	StakedSendersChecks:  true,
+	PendingStakeWindow:   60,
+	PendingUnstakeWindow: 1000,
+}
+
+//stateRootRules make the blocks commit the accounts state root of the chain they extend
+var stateRootRules = &ForkRules{
+	RequiredStake:        100,
+	BlockReward:          4000,
+	RequiredAssetFee:     100,
+	FeePerByte:           10,
+	FeePerByteZether:     20,
+	FeePerByteExtraSpace: 100,
+	BlockVersion:         1,
+	TxVersionMax:         1,
+	PayloadScriptMax:     8,
+	StakedSendersChecks:  true,
+	PendingStakeWindow:   60,
+	PendingUnstakeWindow: 1000,
+}
+
+//schedules must be sorted ascending by height and start with the genesis at height 0
+var (
+	MAIN_NET_FORKS = []*Fork{
+		{FORK_GENESIS, 0, genesisRules},
+		{FORK_UNSTAKING, 1200000, unstakingRules},
+		{FORK_STATE_ROOT, 1300000, stateRootRules},
+	}
+	TEST_NET_FORKS = []*Fork{
+		{FORK_GENESIS, 0, genesisRules},
+		{FORK_UNSTAKING, 600000, unstakingRules},
+		{FORK_STATE_ROOT, 700000, stateRootRules},
+	}
+	DEV_NET_FORKS = []*Fork{
+		{FORK_GENESIS, 0, genesisRules},
+		{FORK_UNSTAKING, 100, unstakingRules},
+		{FORK_STATE_ROOT, 200, stateRootRules},
+	}
+)
+
+var FORKS = MAIN_NET_FORKS
+
+func ValidateForks(forks []*Fork) error {
+
+	if len(forks) == 0 || forks[0].Height != 0 || forks[0].Rules == nil {
+		return errors.New("Fork schedule must start with a fork at height 0")
+	}
+
+	names := make(map[string]bool)
+	for i, fork := range forks {
+		if fork.Rules == nil {
+			return fmt.Errorf("Fork %s has no rules", fork.Name)
+		}
+		if names[fork.Name] {
+			return fmt.Errorf("Fork %s is duplicated", fork.Name)
+		}
+		names[fork.Name] = true
+		if i > 0 && fork.Height <= forks[i-1].Height {
+			return fmt.Errorf("Fork %s must activate after %s", fork.Name, forks[i-1].Name)
+		}
+		if i > 0 && fork.Rules.BlockVersion < forks[i-1].Rules.BlockVersion {
+			return fmt.Errorf("Fork %s can not decrease the block version", fork.Name)
+		}
+		if fork.Rules.StakedSendersChecks && fork.Rules.PendingUnstakeWindow == 0 {
+			return fmt.Errorf("Fork %s requires an unstaking cooldown", fork.Name)
+		}
+	}
+
+	return nil
+}
+
+//GetActiveFork returns the latest upgrade activated at blockHeight
+func GetActiveFork(blockHeight uint64) *Fork {
+	active := FORKS[0]
+	for _, fork := range FORKS[1:] {
+		if fork.Height > blockHeight {
+			break
+		}
+		active = fork
+	}
+	return active
+}
+
+func GetRules(blockHeight uint64) *ForkRules {
+	return GetActiveFork(blockHeight).Rules
+}
+
+func IsActive(name string, blockHeight uint64) bool {
+	for _, fork := range FORKS {
+		if fork.Name == name {
+			return blockHeight >= fork.Height
+		}
+	}
+	return false
+}
+
+//GetUpcomingForks returns the upgrades that are not active yet at blockHeight
+func GetUpcomingForks(blockHeight uint64) []*Fork {
+	out := make([]*Fork, 0)
+	for _, fork := range FORKS {
+		if fork.Height > blockHeight {
+			out = append(out, fork)
+		}
+	}
+	return out
+}
@@ -0,0 +1,59 @@
+package config_forks
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestForksSchedule(t *testing.T) {
+
+	assert.NoError(t, ValidateForks(MAIN_NET_FORKS))
+	assert.NoError(t, ValidateForks(TEST_NET_FORKS))
+	assert.NoError(t, ValidateForks(DEV_NET_FORKS))
+
+	for _, forks := range [][]*Fork{MAIN_NET_FORKS, TEST_NET_FORKS, DEV_NET_FORKS} {
+		assert.Equal(t, FORK_UNSTAKING, forks[1].Name)
+		assert.Greater(t, forks[1].Height, uint64(0), "unstaking is an upgrade of the existing chains")
+		assert.Equal(t, uint64(7), forks[0].Rules.PayloadScriptMax)
+		assert.False(t, forks[0].Rules.StakedSendersChecks)
+		assert.Equal(t, uint64(8), forks[1].Rules.PayloadScriptMax)
+		assert.True(t, forks[1].Rules.StakedSendersChecks)
+		assert.Equal(t, uint64(0), forks[0].Rules.PendingUnstakeWindow)
+		assert.Equal(t, uint64(1000), forks[1].Rules.PendingUnstakeWindow)
+		assert.Equal(t, FORK_STATE_ROOT, forks[2].Name)
+		assert.Equal(t, uint64(0), forks[1].Rules.BlockVersion)
+		assert.Equal(t, uint64(1), forks[2].Rules.BlockVersion, "the blocks commit the state root since the upgrade")
+	}
+
+	oldForks := FORKS
+	defer func() { FORKS = oldForks }()
+
+	upgradeRules := *genesisRules
+	upgradeRules.BlockVersion = 1
+
+	FORKS = []*Fork{
+		{FORK_GENESIS, 0, genesisRules},
+		{"upgrade", 100, &upgradeRules},
+	}
+	assert.NoError(t, ValidateForks(FORKS))
+
+	assert.Equal(t, FORK_GENESIS, GetActiveFork(99).Name)
+	assert.Equal(t, "upgrade", GetActiveFork(100).Name)
+	assert.Equal(t, uint64(0), GetRules(0).BlockVersion)
+	assert.Equal(t, uint64(1), GetRules(1000).BlockVersion)
+
+	assert.False(t, IsActive("upgrade", 99))
+	assert.True(t, IsActive("upgrade", 100))
+	assert.False(t, IsActive("unknown", 100))
+
+	assert.Len(t, GetUpcomingForks(50), 1)
+	assert.Len(t, GetUpcomingForks(100), 0)
+
+	assert.Error(t, ValidateForks([]*Fork{{"upgrade", 100, &upgradeRules}}))
+	assert.Error(t, ValidateForks([]*Fork{{FORK_GENESIS, 0, &upgradeRules}, {"upgrade", 100, genesisRules}}))
+	assert.Error(t, ValidateForks([]*Fork{{FORK_GENESIS, 0, genesisRules}, {FORK_GENESIS, 100, genesisRules}}))
+
+	noCooldownRules := *unstakingRules
+	noCooldownRules.PendingUnstakeWindow = 0
+	assert.Error(t, ValidateForks([]*Fork{{FORK_GENESIS, 0, genesisRules}, {FORK_UNSTAKING, 100, &noCooldownRules}}))
+}
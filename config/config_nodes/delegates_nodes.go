@@ -1,6 +1,7 @@
 package config_nodes
 
 import (
+	"errors"
 	"pandora-pay/config/globals"
 	"strconv"
 )
@@ -63,6 +64,22 @@ var (
 	DELEGATOR_ENABLED      = false
 	DELEGATOR_REQUIRE_AUTH = false
 	DELEGATES_MAXIMUM      = 10000
+
+	/* DELEGATOR_FEE
+	percentage of the staking reward of every block forged with a delegated stake that is transferred from the delegate to the delegator
+	*/
+	DELEGATOR_FEE = uint64(0)
+
+	/* DELEGATES_INACTIVITY_BLOCKS
+	delegates that didn't forge or notify the node for this number of blocks will be removed. Zero disables it
+	*/
+	DELEGATES_INACTIVITY_BLOCKS         = uint64(100000)
+	DELEGATES_INACTIVITY_CHECK_INTERVAL = uint64(100)
+
+	/* DELEGATES_REVOKE_MAX_EXPIRY
+	a signed revoke can expire at most this number of blocks after the current block
+	*/
+	DELEGATES_REVOKE_MAX_EXPIRY = uint64(100)
 )
 
 func InitConfig() (err error) {
@@ -73,6 +90,21 @@ func InitConfig() (err error) {
 		}
 	}
 
+	if globals.Arguments["--delegator-fee"] != nil {
+		if DELEGATOR_FEE, err = strconv.ParseUint(globals.Arguments["--delegator-fee"].(string), 10, 64); err != nil {
+			return
+		}
+		if DELEGATOR_FEE > 100 {
+			return errors.New("--delegator-fee must be a percentage between 0 and 100")
+		}
+	}
+
+	if globals.Arguments["--delegates-inactivity"] != nil {
+		if DELEGATES_INACTIVITY_BLOCKS, err = strconv.ParseUint(globals.Arguments["--delegates-inactivity"].(string), 10, 64); err != nil {
+			return
+		}
+	}
+
 	if globals.Arguments["--delegator-enabled"] == "true" {
 		DELEGATOR_ENABLED = true
 	}
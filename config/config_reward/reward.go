@@ -4,13 +4,14 @@ import (
 	"math"
 	"pandora-pay/config"
 	"pandora-pay/config/config_coins"
+	"pandora-pay/config/config_forks"
 )
 
 func GetRewardAt(blockHeight uint64) (reward uint64) {
 
 	cycle := int(math.Floor(float64(blockHeight) / blocksPerCycle()))
 
-	reward = 4000 / (1 << cycle)
+	reward = config_forks.GetRules(blockHeight).BlockReward / (1 << cycle)
 
 	if reward < 1 {
 		reward = 0
@@ -2,6 +2,7 @@ package config_stake
 
 import (
 	"pandora-pay/config/config_coins"
+	"pandora-pay/config/config_forks"
 	"pandora-pay/config/globals"
 )
 
@@ -9,13 +10,14 @@ func GetRequiredStake(blockHeight uint64) (requiredStake uint64) {
 
 	var err error
 
-	if requiredStake, err = config_coins.ConvertToUnitsUint64(100); err != nil {
+	if requiredStake, err = config_coins.ConvertToUnitsUint64(config_forks.GetRules(blockHeight).RequiredStake); err != nil {
 		panic(err)
 	}
 
 	return
 }
 
+//GetPendingStakeWindow returns the blocks until the received stakes are staked. A new devnet uses shorter windows
 func GetPendingStakeWindow(blockHeight uint64) uint64 {
 
 	if globals.Arguments["--new-devnet"] == true {
@@ -26,5 +28,15 @@ func GetPendingStakeWindow(blockHeight uint64) uint64 {
 		return 10
 	}
 
-	return 60
+	return config_forks.GetRules(blockHeight).PendingStakeWindow
+}
+
+//GetPendingUnstakeWindow returns the cooldown of the Unstaking payloads
+func GetPendingUnstakeWindow(blockHeight uint64) uint64 {
+
+	if globals.Arguments["--new-devnet"] == true {
+		return 10
+	}
+
+	return config_forks.GetRules(blockHeight).PendingUnstakeWindow
 }
@@ -37,3 +37,10 @@ var (
 		},
 	}
 )
+
+//DNS seeds are host:port names. Their A and AAAA records are the IPs of nodes listening on the port, and their TXT records can hold full node urls
+var (
+	MAIN_NET_DNS_SEEDS = []string{}
+	TEST_NET_DNS_SEEDS = []string{}
+	DEV_NET_DNS_SEEDS  = []string{}
+)
@@ -1,6 +1,8 @@
 package merkle_tree
 
 import (
+	"bytes"
+	"errors"
 	"math"
 	"pandora-pay/cryptography"
 )
@@ -65,3 +67,55 @@ func MerkleRoot(hashes [][]byte) []byte {
 	merkles := buildMerkleTree(hashes)
 	return merkles[len(merkles)-1] //return last element
 }
+
+//MerkleProof returns the siblings of the leaf from the bottom to the root. An empty sibling means that the node was hashed with itself
+func MerkleProof(hashes [][]byte, index int) ([][]byte, error) {
+
+	if index < 0 || index >= len(hashes) {
+		return nil, errors.New("Leaf index is invalid")
+	}
+
+	nodes := buildMerkleTree(hashes)
+
+	proof := make([][]byte, 0)
+	for levelStart, levelSize := 0, roundNextPowerOfTwo(len(hashes)); levelSize > 1; levelStart, levelSize = levelStart+levelSize, levelSize/2 {
+		sibling := nodes[levelStart+(index^1)]
+		if sibling == nil {
+			sibling = []byte{}
+		}
+		proof = append(proof, sibling)
+		index /= 2
+	}
+
+	return proof, nil
+}
+
+func VerifyMerkleProof(root, leaf []byte, index, count int, proof [][]byte) bool {
+
+	if index < 0 || index >= count {
+		return false
+	}
+
+	depth := 0
+	for size := roundNextPowerOfTwo(count); size > 1; size /= 2 {
+		depth++
+	}
+	if len(proof) != depth {
+		return false
+	}
+
+	hash := leaf
+	for _, sibling := range proof {
+		if len(sibling) == 0 {
+			sibling = hash
+		}
+		if index%2 == 0 {
+			hash = hashMerkleNode(append([]byte{}, hash...), sibling)
+		} else {
+			hash = hashMerkleNode(append([]byte{}, sibling...), hash)
+		}
+		index /= 2
+	}
+
+	return bytes.Equal(hash, root)
+}
@@ -18,3 +18,28 @@ func TestMerkleRoot(t *testing.T) {
 	assert.Equal(t, root, hash, "Merkle Tree Hashes are invalid")
 
 }
+
+func TestMerkleProof(t *testing.T) {
+
+	for count := 1; count < 20; count++ {
+
+		hashes := make([][]byte, count)
+		for i := range hashes {
+			hashes[i] = cryptography.RandomHash()
+		}
+
+		root := MerkleRoot(hashes)
+
+		for i := range hashes {
+			proof, err := MerkleProof(hashes, i)
+			assert.NoError(t, err)
+			assert.True(t, VerifyMerkleProof(root, hashes[i], i, count, proof), "Merkle Proof is invalid")
+
+			if count > 1 {
+				assert.False(t, VerifyMerkleProof(root, hashes[i], (i+1)%count, count, proof), "Merkle Proof should be bound to the index")
+			}
+			assert.False(t, VerifyMerkleProof(root, cryptography.RandomHash(), i, count, proof), "Merkle Proof should be bound to the leaf")
+		}
+	}
+
+}
@@ -54,6 +54,7 @@ var commands = []Command{
 	{Name: "Wallet:TX", Text: "Private Asset Supply Increase"},
 	{Name: "Wallet:TX", Text: "Private Plain Account Fund"},
 	{Name: "Wallet:TX", Text: "Private Conditional Payment"},
+	{Name: "Wallet:TX", Text: "Private Unstake"},
 	{Name: "Wallet:TX", Text: "Public Update Asset Fee Liquidity"},
 	{Name: "Wallet:TX", Text: "Public Resolution Conditional Payment"},
 	{Name: "Wallet", Text: "Export Addresses"},
@@ -112,7 +112,7 @@ func (mempool *Mempool) processTxsToMempool(txs []*transaction.Transaction, heig
 		}
 
 		computedFeePerByte := minerFee
-		if errs[i] = helpers.SafeUint64Sub(&computedFeePerByte, tx.SpaceExtra*config_fees.FEE_PER_BYTE_EXTRA_SPACE); errs[i] != nil {
+		if errs[i] = helpers.SafeUint64Sub(&computedFeePerByte, tx.SpaceExtra*config_fees.GetFeePerByteExtraSpace(height)); errs[i] != nil {
 			continue
 		}
 
@@ -121,13 +121,13 @@ func (mempool *Mempool) processTxsToMempool(txs []*transaction.Transaction, heig
 		requiredFeePerByte := uint64(0)
 		switch tx.Version {
 		case transaction_type.TX_SIMPLE:
-			requiredFeePerByte = config_fees.FEE_PER_BYTE
+			requiredFeePerByte = config_fees.GetFeePerByte(height)
 			txBase := tx.TransactionBaseInterface.(*transaction_simple.TransactionSimple)
 			if txBase.TxScript == transaction_simple.SCRIPT_RESOLUTION_CONDITIONAL_PAYMENT {
 				checkFee = false
 			}
 		case transaction_type.TX_ZETHER:
-			requiredFeePerByte = config_fees.FEE_PER_BYTE_ZETHER
+			requiredFeePerByte = config_fees.GetFeePerByteZether(height)
 		default:
 			errs[i] = errors.New("Invalid Tx.Version")
 			continue
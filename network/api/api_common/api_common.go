@@ -1,16 +1,19 @@
 package api_common
 
 import (
-	"encoding/base64"
+	"github.com/graph-gophers/graphql-go"
 	"pandora-pay/blockchain"
 	"pandora-pay/blockchain/blockchain_sync"
+	"pandora-pay/blockchain/forging"
 	"pandora-pay/config"
 	"pandora-pay/config/config_nodes"
 	"pandora-pay/helpers/generics"
 	"pandora-pay/mempool"
 	"pandora-pay/network/api/api_common/api_delegator_node"
 	"pandora-pay/network/api/api_common/api_faucet"
+	"pandora-pay/network/api/api_common/api_webhooks"
 	"pandora-pay/network/known_nodes"
+	"pandora-pay/network/rate_limiter"
 	"pandora-pay/recovery"
 	"pandora-pay/txs_builder"
 	"pandora-pay/txs_validator"
@@ -35,29 +38,18 @@ type APICommon struct {
 	localChainSync            *generics.Value[*blockchain_sync.BlockchainSyncData]
 	Faucet                    *api_faucet.Faucet
 	DelegatorNode             *api_delegator_node.DelegatorNode
+	Webhooks                  *api_webhooks.Webhooks
+	RateLimiter               *rate_limiter.RateLimiter
 	ApiStore                  *APIStore
 	mempoolProcessedThisBlock *generics.Value[*generics.Map[string, *mempoolNewTxReply]]
 	temporaryList             *generics.Value[*APINetworkNodesReply]
 	temporaryListCreation     *generics.Value[time.Time]
+	graphQLSchema             *graphql.Schema
 }
 
 //make sure it is safe to read
 func (api *APICommon) readLocalBlockchain(newChainDataUpdate *blockchain.BlockchainDataUpdate) {
-	newLocalChain := &APIBlockchain{
-		newChainDataUpdate.Update.Height,
-		base64.StdEncoding.EncodeToString(newChainDataUpdate.Update.Hash),
-		base64.StdEncoding.EncodeToString(newChainDataUpdate.Update.PrevHash),
-		base64.StdEncoding.EncodeToString(newChainDataUpdate.Update.KernelHash),
-		base64.StdEncoding.EncodeToString(newChainDataUpdate.Update.PrevKernelHash),
-		newChainDataUpdate.Update.Timestamp,
-		newChainDataUpdate.Update.TransactionsCount,
-		newChainDataUpdate.Update.AccountsCount,
-		newChainDataUpdate.Update.AssetsCount,
-		newChainDataUpdate.Update.Target.String(),
-		newChainDataUpdate.Update.Supply,
-		newChainDataUpdate.Update.BigTotalDifficulty.String(),
-	}
-	api.localChain.Store(newLocalChain)
+	api.localChain.Store(NewAPIBlockchain(newChainDataUpdate.Update))
 }
 
 //make sure it is safe to read
@@ -65,7 +57,7 @@ func (api *APICommon) readLocalBlockchainSync(newLocalSync *blockchain_sync.Bloc
 	api.localChainSync.Store(newLocalSync)
 }
 
-func NewAPICommon(knownNodes *known_nodes.KnownNodes, mempool *mempool.Mempool, chain *blockchain.Blockchain, wallet *wallet.Wallet, txsValidator *txs_validator.TxsValidator, txsBuilder *txs_builder.TxsBuilder, apiStore *APIStore) (api *APICommon, err error) {
+func NewAPICommon(knownNodes *known_nodes.KnownNodes, mempool *mempool.Mempool, chain *blockchain.Blockchain, forging *forging.Forging, wallet *wallet.Wallet, txsValidator *txs_validator.TxsValidator, txsBuilder *txs_builder.TxsBuilder, apiStore *APIStore) (api *APICommon, err error) {
 
 	var faucet *api_faucet.Faucet
 	if config.NETWORK_SELECTED == config.TEST_NET_NETWORK_BYTE || config.NETWORK_SELECTED == config.DEV_NET_NETWORK_BYTE {
@@ -76,7 +68,15 @@ func NewAPICommon(knownNodes *known_nodes.KnownNodes, mempool *mempool.Mempool,
 
 	var delegatorNode *api_delegator_node.DelegatorNode
 	if config_nodes.DELEGATOR_ENABLED {
-		delegatorNode = api_delegator_node.NewDelegatorNode(chain, wallet)
+		delegatorNode = api_delegator_node.NewDelegatorNode(chain, forging, wallet, txsBuilder)
+	}
+
+	//webhooks are notified by the subscriptions which are processed only by the nodes that seed wallets
+	var webhooks *api_webhooks.Webhooks
+	if config.SEED_WALLET_NODES_INFO {
+		if webhooks, err = api_webhooks.NewWebhooks(); err != nil {
+			return
+		}
 	}
 
 	api = &APICommon{
@@ -90,10 +90,17 @@ func NewAPICommon(knownNodes *known_nodes.KnownNodes, mempool *mempool.Mempool,
 		&generics.Value[*blockchain_sync.BlockchainSyncData]{},
 		faucet,
 		delegatorNode,
+		webhooks,
+		rate_limiter.NewRateLimiter(),
 		apiStore,
 		&generics.Value[*generics.Map[string, *mempoolNewTxReply]]{},
 		&generics.Value[*APINetworkNodesReply]{},
 		&generics.Value[time.Time]{},
+		nil,
+	}
+
+	if api.graphQLSchema, err = api.createGraphQLSchema(); err != nil {
+		return
 	}
 
 	api.temporaryListCreation.Store(time.Now())
@@ -0,0 +1,28 @@
+package api_delegator_node
+
+import (
+	"errors"
+	"net/http"
+	"pandora-pay/cryptography"
+	"pandora-pay/helpers"
+)
+
+type ApiDelegatorNodeDelegateStatsRequest struct {
+	PublicKey helpers.Base64 `json:"publicKey" msgpack:"publicKey"`
+}
+
+type ApiDelegatorNodeDelegateStatsReply struct {
+	*DelegateStats
+}
+
+func (api *DelegatorNode) GetDelegateStats(r *http.Request, args *ApiDelegatorNodeDelegateStatsRequest, reply *ApiDelegatorNodeDelegateStatsReply) (err error) {
+
+	if len(args.PublicKey) != cryptography.PublicKeySize {
+		return errors.New("Invalid PublicKey")
+	}
+
+	if reply.DelegateStats, err = api.getDelegateStats(args.PublicKey); err != nil {
+		return
+	}
+	return
+}
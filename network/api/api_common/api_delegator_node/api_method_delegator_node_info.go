@@ -10,11 +10,13 @@ type ApiDelegatorNodeInfoReply struct {
 	MaximumAllowed int    `json:"maximumAllowed" msgpack:"maximumAllowed"`
 	DelegatesCount int    `json:"delegatesCount" msgpack:"delegatesCount"`
 	Blocks         uint64 `json:"blocks" msgpack:"blocks"`
+	DelegatorFee   uint64 `json:"delegatorFee" msgpack:"delegatorFee"` //percentage of the staking rewards
 }
 
 func (api *DelegatorNode) GetDelegatorNodeInfo(r *http.Request, args *struct{}, reply *ApiDelegatorNodeInfoReply) error {
 	reply.MaximumAllowed = config_nodes.DELEGATES_MAXIMUM
 	reply.DelegatesCount = api.wallet.GetDelegatesCount()
 	reply.Blocks = atomic.LoadUint64(&api.chainHeight)
+	reply.DelegatorFee = config_nodes.DELEGATOR_FEE
 	return nil
 }
@@ -9,6 +9,7 @@ import (
 	"pandora-pay/blockchain/data_storage/accounts"
 	"pandora-pay/blockchain/data_storage/accounts/account"
 	"pandora-pay/blockchain/data_storage/registrations/registration"
+	"pandora-pay/config/config_auth"
 	"pandora-pay/config/config_coins"
 	"pandora-pay/config/config_nodes"
 	"pandora-pay/config/config_stake"
@@ -17,6 +18,7 @@ import (
 	"pandora-pay/store/store_db/store_db_interface"
 	"pandora-pay/wallet/wallet_address"
 	"pandora-pay/wallet/wallet_address/shared_staked"
+	"sync/atomic"
 )
 
 type ApiDelegatorNodeNotifyRequest struct {
@@ -28,11 +30,7 @@ type ApiDelegatorNodeNotifyReply struct {
 	Result bool `json:"result" msgpack:"result"`
 }
 
-func (api *DelegatorNode) DelegatorNotify(r *http.Request, args *ApiDelegatorNodeNotifyRequest, reply *ApiDelegatorNodeNotifyReply, authenticated bool) (err error) {
-
-	if config_nodes.DELEGATOR_REQUIRE_AUTH && !authenticated {
-		return errors.New("Invalid User or Password")
-	}
+func (api *DelegatorNode) DelegatorNotify(r *http.Request, args *ApiDelegatorNodeNotifyRequest, reply *ApiDelegatorNodeNotifyReply, principal *config_auth.Principal) (err error) {
 
 	sharedStakedPrivateKey, err := addresses.NewPrivateKey(args.SharedStakedPrivateKey)
 	if err != nil {
@@ -43,7 +41,7 @@ func (api *DelegatorNode) DelegatorNotify(r *http.Request, args *ApiDelegatorNod
 	addr := api.wallet.GetWalletAddressByPublicKey(sharedStakedPublicKey, true)
 	if addr != nil && addr.PrivateKey == nil {
 		reply.Result = true
-		return
+		return api.setDelegateActive(sharedStakedPublicKey, atomic.LoadUint64(&api.chainHeight))
 	}
 
 	var acc *account.Account
@@ -66,6 +64,11 @@ func (api *DelegatorNode) DelegatorNotify(r *http.Request, args *ApiDelegatorNod
 			return errors.New("Account is not staked")
 		}
 
+		//the delegator fee is transferred by the delegator node, which can't sign with the spend key
+		if config_nodes.DELEGATOR_FEE > 0 && len(reg.SpendPublicKey) > 0 {
+			return errors.New("Delegates with a spend key can't pay the delegator fee")
+		}
+
 		var accs *accounts.Accounts
 		if accs, err = dataStorage.AccsCollection.GetMap(config_coins.NATIVE_ASSET_FULL); err != nil {
 			return
@@ -117,6 +120,10 @@ func (api *DelegatorNode) DelegatorNotify(r *http.Request, args *ApiDelegatorNod
 		return
 	}
 
+	if err = api.setDelegateActive(sharedStakedPublicKey, chainHeight); err != nil {
+		return
+	}
+
 	reply.Result = true
 
 	return nil
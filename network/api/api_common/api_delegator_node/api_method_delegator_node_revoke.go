@@ -0,0 +1,113 @@
+package api_delegator_node
+
+import (
+	"errors"
+	"fmt"
+	"github.com/vmihailenco/msgpack/v5"
+	"net/http"
+	"pandora-pay/config/config_auth"
+	"pandora-pay/config/config_nodes"
+	"pandora-pay/cryptography"
+	"pandora-pay/cryptography/crypto"
+	"pandora-pay/helpers"
+	"pandora-pay/helpers/advanced_buffers"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_interface"
+	"sync/atomic"
+)
+
+type ApiDelegatorNodeRevokeRequest struct {
+	PublicKey helpers.Base64 `json:"publicKey" msgpack:"publicKey"`
+	ExpiresAt uint64         `json:"expiresAt" msgpack:"expiresAt"`
+	Signature helpers.Base64 `json:"signature" msgpack:"signature"`
+}
+
+type ApiDelegatorNodeRevokeReply struct {
+	Result bool `json:"result" msgpack:"result"`
+}
+
+//GetDelegatorRevokeMessage is the message that the delegate signs to stop the node from staking its delegated stake. The signature can be used only once until the block height expiresAt
+func GetDelegatorRevokeMessage(publicKey []byte, expiresAt uint64) []byte {
+	w := advanced_buffers.NewBufferWriter()
+	w.Write([]byte("delegator/revoke"))
+	w.Write(publicKey)
+	w.WriteUvarint(expiresAt)
+	return cryptography.SHA3(w.Bytes())
+}
+
+//useRevoke marks a signed revoke as used and forgets the expired ones. The used revokes are stored until they expire, so they can't be replayed after a restart
+func (api *DelegatorNode) useRevoke(message []byte, expiresAt, chainHeight uint64) (ok bool, err error) {
+
+	api.revokesUsedLock.Lock()
+	defer api.revokesUsedLock.Unlock()
+
+	err = store.StoreSettings.DB.Update(func(writer store_db_interface.StoreDBTransactionInterface) (err error) {
+
+		revokesUsed := make(map[string]uint64) //signed revokes that were used and their expiry
+		if data := writer.Get("delegatorRevokesUsed"); data != nil {
+			if err = msgpack.Unmarshal(data, &revokesUsed); err != nil {
+				return
+			}
+		}
+
+		for key, expiry := range revokesUsed {
+			if expiry < chainHeight {
+				delete(revokesUsed, key)
+			}
+		}
+
+		if _, found := revokesUsed[string(message)]; found {
+			return
+		}
+		revokesUsed[string(message)] = expiresAt
+
+		data, err := msgpack.Marshal(revokesUsed)
+		if err != nil {
+			return
+		}
+
+		writer.Put("delegatorRevokesUsed", data)
+		ok = true
+		return
+	})
+
+	return
+}
+
+func (api *DelegatorNode) DelegatorRevoke(r *http.Request, args *ApiDelegatorNodeRevokeRequest, reply *ApiDelegatorNodeRevokeReply, principal *config_auth.Principal) (err error) {
+
+	if len(args.PublicKey) != cryptography.PublicKeySize {
+		return errors.New("Invalid PublicKey")
+	}
+
+	chainHeight := atomic.LoadUint64(&api.chainHeight)
+	if args.ExpiresAt < chainHeight {
+		return errors.New("Revoke expired")
+	}
+	if args.ExpiresAt-chainHeight > config_nodes.DELEGATES_REVOKE_MAX_EXPIRY {
+		return fmt.Errorf("Revoke must expire in at most %d blocks", config_nodes.DELEGATES_REVOKE_MAX_EXPIRY)
+	}
+
+	message := GetDelegatorRevokeMessage(args.PublicKey, args.ExpiresAt)
+	if !crypto.VerifySignature(message, args.Signature, args.PublicKey) {
+		return errors.New("Invalid Signature")
+	}
+
+	var ok bool
+	if ok, err = api.useRevoke(message, args.ExpiresAt, chainHeight); err != nil {
+		return
+	}
+	if !ok {
+		return errors.New("Revoke was already used")
+	}
+
+	if reply.Result, err = api.revokeDelegate(args.PublicKey, DELEGATE_REVOKED_BY_DELEGATE); err != nil {
+		return
+	}
+
+	if !reply.Result {
+		return errors.New("Delegate was not found")
+	}
+
+	return
+}
@@ -0,0 +1,63 @@
+package api_delegator_node
+
+import (
+	"github.com/stretchr/testify/assert"
+	"pandora-pay/config/config_auth"
+	"pandora-pay/config/config_nodes"
+	"pandora-pay/cryptography"
+	"pandora-pay/helpers"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_memory"
+	"sync/atomic"
+	"testing"
+)
+
+func TestDelegatorRevokeMessage(t *testing.T) {
+
+	publicKey := helpers.RandomBytes(cryptography.PublicKeySize)
+	assert.NotEqual(t, GetDelegatorRevokeMessage(publicKey, 10), GetDelegatorRevokeMessage(publicKey, 11), "the message is bound to the expiry")
+
+	db, err := store_db_memory.CreateStoreDBMemory("settings")
+	assert.Nil(t, err)
+	store.StoreSettings = &store.Store{Name: "settings", Opened: true, DB: db}
+
+	api := &DelegatorNode{}
+
+	message := GetDelegatorRevokeMessage(publicKey, 10)
+	ok, err := api.useRevoke(message, 10, 5)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	ok, err = api.useRevoke(message, 10, 6)
+	assert.Nil(t, err)
+	assert.False(t, ok, "a signed revoke can't be replayed")
+
+	ok, err = (&DelegatorNode{}).useRevoke(message, 10, 6)
+	assert.Nil(t, err)
+	assert.False(t, ok, "the used revokes are kept after a restart")
+
+	ok, err = api.useRevoke(GetDelegatorRevokeMessage(publicKey, 20), 20, 11)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+
+	ok, err = api.useRevoke(message, 10, 11)
+	assert.Nil(t, err)
+	assert.True(t, ok, "the expired revokes are forgotten")
+}
+
+func TestDelegatorRevokeExpiry(t *testing.T) {
+
+	api := &DelegatorNode{}
+	atomic.StoreUint64(&api.chainHeight, 1000)
+
+	publicKey := helpers.RandomBytes(cryptography.PublicKeySize)
+	reply := &ApiDelegatorNodeRevokeReply{}
+
+	err := api.DelegatorRevoke(nil, &ApiDelegatorNodeRevokeRequest{publicKey, 999, nil}, reply, &config_auth.Principal{})
+	assert.EqualError(t, err, "Revoke expired")
+
+	err = api.DelegatorRevoke(nil, &ApiDelegatorNodeRevokeRequest{publicKey, 1000 + config_nodes.DELEGATES_REVOKE_MAX_EXPIRY + 1, nil}, reply, &config_auth.Principal{})
+	assert.NotNil(t, err)
+
+	err = api.DelegatorRevoke(nil, &ApiDelegatorNodeRevokeRequest{publicKey, 1000, helpers.RandomBytes(65)}, reply, &config_auth.Principal{})
+	assert.EqualError(t, err, "Invalid Signature")
+}
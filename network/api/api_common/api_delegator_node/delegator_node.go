@@ -2,22 +2,38 @@ package api_delegator_node
 
 import (
 	"pandora-pay/blockchain"
+	"pandora-pay/blockchain/forging"
+	"pandora-pay/helpers/multicast"
+	"pandora-pay/recovery"
+	"pandora-pay/txs_builder"
 	"pandora-pay/wallet"
+	"sync"
 )
 
 type DelegatorNode struct {
-	chainHeight uint64 //use atomic
-	wallet      *wallet.Wallet
-	chain       *blockchain.Blockchain
+	chainHeight           uint64 //use atomic
+	wallet                *wallet.Wallet
+	chain                 *blockchain.Blockchain
+	forging               *forging.Forging
+	txsBuilder            *txs_builder.TxsBuilder
+	UpdateDelegateRevoked *multicast.MulticastChannel[*DelegateRevoked]
+	revokesUsedLock       sync.Mutex
 }
 
-func NewDelegatorNode(chain *blockchain.Blockchain, wallet *wallet.Wallet) (delegator *DelegatorNode) {
+func NewDelegatorNode(chain *blockchain.Blockchain, forging *forging.Forging, wallet *wallet.Wallet, txsBuilder *txs_builder.TxsBuilder) (delegator *DelegatorNode) {
 
 	delegator = &DelegatorNode{
 		0,
 		wallet,
 		chain,
+		forging,
+		txsBuilder,
+		multicast.NewMulticastChannel[*DelegateRevoked](),
+		sync.Mutex{},
 	}
 
+	recovery.SafeGo(delegator.processBlocksForged)
+	recovery.SafeGo(delegator.processChainUpdates)
+
 	return
 }
@@ -0,0 +1,149 @@
+package api_delegator_node
+
+import (
+	"context"
+	"fmt"
+	"pandora-pay/config/config_coins"
+	"pandora-pay/config/config_nodes"
+	"pandora-pay/config/config_stake"
+	"pandora-pay/gui"
+	"sync/atomic"
+)
+
+type DelegateRevokedReason byte
+
+const (
+	DELEGATE_REVOKED_BY_DELEGATE DelegateRevokedReason = iota
+	DELEGATE_REVOKED_STAKE_BELOW_REQUIRED
+	DELEGATE_REVOKED_INACTIVE
+)
+
+func (t DelegateRevokedReason) String() string {
+	switch t {
+	case DELEGATE_REVOKED_BY_DELEGATE:
+		return "DELEGATE_REVOKED_BY_DELEGATE"
+	case DELEGATE_REVOKED_STAKE_BELOW_REQUIRED:
+		return "DELEGATE_REVOKED_STAKE_BELOW_REQUIRED"
+	case DELEGATE_REVOKED_INACTIVE:
+		return "DELEGATE_REVOKED_INACTIVE"
+	default:
+		return "Unknown DelegateRevokedReason"
+	}
+}
+
+type DelegateRevoked struct {
+	PublicKey   []byte
+	Reason      DelegateRevokedReason
+	BlockHeight uint64
+}
+
+//revokeDelegate removes a delegated stake from the wallet and from forging. Only shared staked addresses can be revoked
+func (api *DelegatorNode) revokeDelegate(publicKey []byte, reason DelegateRevokedReason) (bool, error) {
+
+	addr := api.wallet.GetWalletAddressByPublicKey(publicKey, true)
+	if addr == nil || !addr.IsSharedStaked {
+		return false, nil
+	}
+
+	removed, err := api.wallet.RemoveAddressByPublicKey(publicKey, true)
+	if err != nil || !removed {
+		return false, err
+	}
+
+	api.UpdateDelegateRevoked.Broadcast(&DelegateRevoked{publicKey, reason, atomic.LoadUint64(&api.chainHeight)})
+	gui.GUI.Info(fmt.Sprintf("Delegate %x revoked: %s", publicKey, reason))
+
+	return true, nil
+}
+
+//checkDelegatesInactivity revokes the delegates that didn't forge or notify the node in the last DELEGATES_INACTIVITY_BLOCKS
+func (api *DelegatorNode) checkDelegatesInactivity(chainHeight uint64) (err error) {
+
+	api.wallet.Lock.RLock()
+	delegates := make([][]byte, 0, len(api.wallet.Addresses))
+	for _, addr := range api.wallet.Addresses {
+		if addr.IsSharedStaked {
+			delegates = append(delegates, addr.PublicKey)
+		}
+	}
+	api.wallet.Lock.RUnlock()
+
+	for _, publicKey := range delegates {
+
+		var stats *DelegateStats
+		if stats, err = api.getDelegateStats(publicKey); err != nil {
+			return
+		}
+
+		//delegates added before the activity was tracked start counting from now
+		if stats.LastActiveHeight == 0 {
+			if err = api.setDelegateActive(publicKey, chainHeight); err != nil {
+				return
+			}
+			continue
+		}
+
+		if chainHeight > stats.LastActiveHeight && chainHeight-stats.LastActiveHeight >= config_nodes.DELEGATES_INACTIVITY_BLOCKS {
+			if _, err = api.revokeDelegate(publicKey, DELEGATE_REVOKED_INACTIVE); err != nil {
+				return
+			}
+		}
+	}
+
+	return
+}
+
+func (api *DelegatorNode) processChainUpdates() {
+
+	updateNewChainCn := api.chain.UpdateNewChainUpdate.AddListener()
+	defer api.chain.UpdateNewChainUpdate.RemoveChannel(updateNewChainCn)
+
+	for {
+		update, ok := <-updateNewChainCn
+		if !ok {
+			return
+		}
+
+		atomic.StoreUint64(&api.chainHeight, update.BlockHeight)
+
+		for k, v := range update.Registrations.Committed {
+			if v.Stored == "delete" || (v.Stored == "update" && !v.Element.Staked) {
+				if _, err := api.revokeDelegate([]byte(k), DELEGATE_REVOKED_STAKE_BELOW_REQUIRED); err != nil {
+					gui.GUI.Error("Error revoking delegate", err)
+				}
+			}
+		}
+
+		if accs, _ := update.AccsCollection.GetMapIfExists(config_coins.NATIVE_ASSET_FULL); accs != nil {
+			for k, v := range accs.HashMap.Committed {
+
+				addr := api.wallet.GetWalletAddressByPublicKey([]byte(k), true)
+				if addr == nil || !addr.IsSharedStaked {
+					continue
+				}
+
+				revoke := v.Stored == "delete"
+				if v.Stored == "update" {
+					stakingAmount, err := api.wallet.DecryptBalanceByPublicKey(addr.PublicKey, v.Element.Balance.Amount.Serialize(), config_coins.NATIVE_ASSET_FULL, false, 0, true, true, context.Background(), func(string) {})
+					if err != nil {
+						gui.GUI.Error("Error decrypting delegate balance", err)
+						continue
+					}
+					revoke = stakingAmount < config_stake.GetRequiredStake(update.BlockHeight)
+				}
+
+				if revoke {
+					if _, err := api.revokeDelegate(addr.PublicKey, DELEGATE_REVOKED_STAKE_BELOW_REQUIRED); err != nil {
+						gui.GUI.Error("Error revoking delegate", err)
+					}
+				}
+			}
+		}
+
+		if config_nodes.DELEGATES_INACTIVITY_BLOCKS > 0 && update.BlockHeight%config_nodes.DELEGATES_INACTIVITY_CHECK_INTERVAL == 0 {
+			if err := api.checkDelegatesInactivity(update.BlockHeight); err != nil {
+				gui.GUI.Error("Error checking delegates inactivity", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package api_delegator_node
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/vmihailenco/msgpack/v5"
+	"pandora-pay/blockchain/forging"
+	"pandora-pay/config/config_coins"
+	"pandora-pay/config/config_nodes"
+	"pandora-pay/gui"
+	"pandora-pay/helpers"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_interface"
+	"pandora-pay/txs_builder"
+	"pandora-pay/txs_builder/wizard"
+)
+
+//DelegateStats is the accounting of the blocks forged by the node using a delegated stake
+type DelegateStats struct {
+	PublicKey        []byte `json:"publicKey" msgpack:"publicKey"`
+	BlocksForged     uint64 `json:"blocksForged" msgpack:"blocksForged"`
+	StakingRewards   uint64 `json:"stakingRewards" msgpack:"stakingRewards"` //kept by the delegate
+	DelegatorFees    uint64 `json:"delegatorFees" msgpack:"delegatorFees"`   //transferred to the delegator
+	LastBlockHeight  uint64 `json:"lastBlockHeight" msgpack:"lastBlockHeight"`
+	LastActiveHeight uint64 `json:"lastActiveHeight" msgpack:"lastActiveHeight"` //last block height in which the delegate forged or notified the node
+}
+
+func getDelegateStatsKey(publicKey []byte) string {
+	return "delegateStats:" + hex.EncodeToString(publicKey)
+}
+
+func (api *DelegatorNode) getDelegateStats(publicKey []byte) (stats *DelegateStats, err error) {
+
+	stats = &DelegateStats{PublicKey: publicKey}
+
+	err = store.StoreSettings.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) error {
+		if data := reader.Get(getDelegateStatsKey(publicKey)); data != nil {
+			return msgpack.Unmarshal(data, stats)
+		}
+		return nil
+	})
+
+	return
+}
+
+func (api *DelegatorNode) updateDelegateStats(publicKey []byte, callback func(stats *DelegateStats) error) error {
+
+	return store.StoreSettings.DB.Update(func(writer store_db_interface.StoreDBTransactionInterface) (err error) {
+
+		key := getDelegateStatsKey(publicKey)
+
+		stats := &DelegateStats{PublicKey: publicKey}
+		if data := writer.Get(key); data != nil {
+			if err = msgpack.Unmarshal(data, stats); err != nil {
+				return
+			}
+		}
+
+		if err = callback(stats); err != nil {
+			return
+		}
+
+		data, err := msgpack.Marshal(stats)
+		if err != nil {
+			return
+		}
+
+		writer.Put(key, data)
+		return
+	})
+}
+
+func (api *DelegatorNode) setDelegateActive(publicKey []byte, blockHeight uint64) error {
+	return api.updateDelegateStats(publicKey, func(stats *DelegateStats) error {
+		stats.LastActiveHeight = blockHeight
+		return nil
+	})
+}
+
+//getDelegatorFee is the share of the staking reward kept by the delegator
+func getDelegatorFee(reward uint64) (fee uint64, err error) {
+	if config_nodes.DELEGATOR_FEE == 0 {
+		return
+	}
+	fee = reward
+	if err = helpers.SafeUint64Mul(&fee, config_nodes.DELEGATOR_FEE); err != nil {
+		return
+	}
+	return fee / 100, nil
+}
+
+//chargeDelegatorFee transfers the fee from the delegate to the first wallet address. Staked delegates can only send to staked recipients, so the rings use only staked accounts
+func (api *DelegatorNode) chargeDelegatorFee(delegate []byte, fee uint64) (err error) {
+
+	sender := api.wallet.GetWalletAddressByPublicKey(delegate, true)
+	if sender == nil {
+		return errors.New("Delegate was not found")
+	}
+
+	recipient, err := api.wallet.GetWalletAddress(0, true)
+	if err != nil {
+		return
+	}
+	if recipient.IsSharedStaked {
+		return errors.New("The first wallet address is a delegated stake and can't receive the delegator fee")
+	}
+
+	txData := &txs_builder.TxBuilderCreateZetherTxData{
+		Payloads: []*txs_builder.TxBuilderCreateZetherTxPayload{{
+			Sender:            sender.AddressEncoded,
+			Asset:             config_coins.NATIVE_ASSET_FULL,
+			Recipient:         recipient.AddressEncoded,
+			Amount:            fee,
+			Memo:              "Delegator fee",
+			Fee:               &wizard.WizardZetherTransactionFee{&wizard.WizardTransactionFee{0, 0, 0, true}, false, 0, 0},
+			RingConfiguration: &txs_builder.ZetherRingConfiguration{64, &txs_builder.ZetherSenderRingType{true, false, nil, 0}, &txs_builder.ZetherRecipientRingType{true, false, nil, 0}},
+		}},
+	}
+
+	_, err = api.txsBuilder.CreateZetherTx(txData, nil, true, true, false, false, context.Background(), func(status string) {})
+	return
+}
+
+//addBlockForged records the block and the part of the staking reward that was kept by the delegator
+func (api *DelegatorNode) addBlockForged(blockForged *forging.ForgingBlockForged, fee uint64) error {
+
+	return api.updateDelegateStats(blockForged.PublicKey, func(stats *DelegateStats) (err error) {
+
+		if fee > blockForged.Reward {
+			return errors.New("Delegator fee is bigger than the reward")
+		}
+
+		stats.BlocksForged += 1
+		if err = helpers.SafeUint64Add(&stats.StakingRewards, blockForged.Reward-fee); err != nil {
+			return
+		}
+		if err = helpers.SafeUint64Add(&stats.DelegatorFees, fee); err != nil {
+			return
+		}
+		stats.LastBlockHeight = blockForged.BlockHeight
+		stats.LastActiveHeight = blockForged.BlockHeight
+
+		return
+	})
+}
+
+func (api *DelegatorNode) processBlocksForged() {
+
+	blockForgedCn := api.forging.BlockForged.AddListener()
+	defer api.forging.BlockForged.RemoveChannel(blockForgedCn)
+
+	for {
+		blockForged, ok := <-blockForgedCn
+		if !ok {
+			return
+		}
+
+		addr := api.wallet.GetWalletAddressByPublicKey(blockForged.PublicKey, true)
+		if addr == nil || !addr.IsSharedStaked {
+			continue
+		}
+
+		fee, err := getDelegatorFee(blockForged.Reward)
+		if err != nil {
+			gui.GUI.Error(fmt.Errorf("Error computing the delegator fee for block %d: %s", blockForged.BlockHeight, err))
+			fee = 0
+		}
+
+		//the fee is recorded only when its transfer was accepted
+		if fee > 0 {
+			if err = api.chargeDelegatorFee(blockForged.PublicKey, fee); err != nil {
+				gui.GUI.Error(fmt.Errorf("Error charging the delegator fee for block %d: %s", blockForged.BlockHeight, err))
+				fee = 0
+			}
+		}
+
+		if err = api.addBlockForged(blockForged, fee); err != nil {
+			gui.GUI.Error(fmt.Errorf("Error storing delegate stats for block %d: %s", blockForged.BlockHeight, err))
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package api_delegator_node
+
+import (
+	"github.com/stretchr/testify/assert"
+	"pandora-pay/blockchain/forging"
+	"pandora-pay/config/config_nodes"
+	"pandora-pay/cryptography"
+	"pandora-pay/helpers"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_memory"
+	"testing"
+)
+
+func TestDelegatorFee(t *testing.T) {
+
+	oldFee := config_nodes.DELEGATOR_FEE
+	defer func() { config_nodes.DELEGATOR_FEE = oldFee }()
+
+	config_nodes.DELEGATOR_FEE = 0
+	fee, err := getDelegatorFee(1000)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(0), fee)
+
+	config_nodes.DELEGATOR_FEE = 15
+	fee, err = getDelegatorFee(1001)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(150), fee, "the fee is rounded down in favour of the delegate")
+
+	_, err = getDelegatorFee(^uint64(0))
+	assert.NotNil(t, err)
+}
+
+func TestDelegateStatsFees(t *testing.T) {
+
+	db, err := store_db_memory.CreateStoreDBMemory("settings")
+	assert.Nil(t, err)
+	store.StoreSettings = &store.Store{Name: "settings", Opened: true, DB: db}
+
+	api := &DelegatorNode{}
+	publicKey := helpers.RandomBytes(cryptography.PublicKeySize)
+
+	assert.Nil(t, api.addBlockForged(&forging.ForgingBlockForged{PublicKey: publicKey, BlockHeight: 10, Reward: 1000}, 150))
+	assert.Nil(t, api.addBlockForged(&forging.ForgingBlockForged{PublicKey: publicKey, BlockHeight: 11, Reward: 1000}, 0))
+	assert.NotNil(t, api.addBlockForged(&forging.ForgingBlockForged{PublicKey: publicKey, BlockHeight: 12, Reward: 100}, 150), "the fee can't be bigger than the reward")
+
+	stats, err := api.getDelegateStats(publicKey)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(2), stats.BlocksForged)
+	assert.Equal(t, uint64(1850), stats.StakingRewards)
+	assert.Equal(t, uint64(150), stats.DelegatorFees)
+	assert.Equal(t, uint64(11), stats.LastBlockHeight)
+}
@@ -0,0 +1,817 @@
+package api_common
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"github.com/graph-gophers/graphql-go"
+	"github.com/vmihailenco/msgpack/v5"
+	"math"
+	"pandora-pay/blockchain/blocks/block"
+	"pandora-pay/blockchain/data_storage/accounts"
+	"pandora-pay/blockchain/data_storage/accounts/account"
+	"pandora-pay/blockchain/data_storage/assets"
+	"pandora-pay/blockchain/data_storage/assets/asset"
+	"pandora-pay/blockchain/data_storage/registrations"
+	"pandora-pay/blockchain/info"
+	"pandora-pay/blockchain/transactions/transaction"
+	"pandora-pay/blockchain/transactions/transaction/transaction_simple"
+	"pandora-pay/blockchain/transactions/transaction/transaction_type"
+	"pandora-pay/blockchain/transactions/transaction/transaction_zether"
+	"pandora-pay/blockchain/transactions/transaction/transaction_zether/transaction_zether_payload"
+	"pandora-pay/blockchain/transactions/transaction/transaction_zether/transaction_zether_payload/transaction_zether_payload_extra"
+	"pandora-pay/config"
+	"pandora-pay/config/config_coins"
+	"pandora-pay/cryptography"
+	"pandora-pay/helpers/advanced_buffers"
+	"pandora-pay/helpers/generics"
+	"pandora-pay/store/store_db/store_db_interface"
+	"reflect"
+	"strconv"
+)
+
+const graphQLSDL = `
+schema {
+	query: Query
+}
+
+"Unsigned 64 bit integer"
+scalar Uint64
+
+"Bytes encoded in base64"
+scalar Base64
+
+type Query {
+	chain: Chain!
+	block(height: Uint64, hash: Base64): Block
+	blocks(start: Uint64!, count: Int = 10): [Block!]!
+	tx(height: Uint64, hash: Base64): Tx
+	asset(hash: Base64!): Asset
+	account(publicKey: Base64!): Account
+}
+
+type Chain {
+	"Number of blocks"
+	height: Uint64!
+	hash: String!
+	prevHash: String!
+	kernelHash: String!
+	prevKernelHash: String!
+	timestamp: Uint64!
+	transactionsCount: Uint64!
+	accountsCount: Uint64!
+	assetsCount: Uint64!
+	target: String!
+	supply: Uint64!
+	totalDifficulty: String!
+	lastBlock: Block
+}
+
+type Block {
+	hash: Base64!
+	height: Uint64!
+	version: Uint64!
+	merkleHash: Base64!
+	prevHash: Base64!
+	prevKernelHash: Base64!
+	timestamp: Uint64!
+	stakingAmount: Uint64!
+	stakingNonce: Base64!
+	kernelHash: Base64
+	size: Uint64
+	fees: Uint64
+	txsCount: Uint64
+	"count is at most 100"
+	txs(start: Int = 0, count: Int = 100): [Tx!]!
+}
+
+type Tx {
+	hash: Base64!
+	version: Int!
+	size: Uint64!
+	"Index of the tx in the blockchain"
+	height: Uint64
+	blockHeight: Uint64
+	timestamp: Uint64
+	fee: Uint64!
+	block: Block
+	"Set for simple txs"
+	simple: TxSimple
+	"Payloads of the zether txs"
+	payloads: [Payload!]!
+	"Assets used by the tx. Simple txs use the native asset"
+	assets: [Asset!]!
+}
+
+type TxSimple {
+	script: Int!
+	nonce: Uint64!
+	fee: Uint64!
+	dataVersion: Int!
+	"Data of the tx when it is plain text"
+	dataPublic: Base64
+	vin: Account
+}
+
+"Payload of a zether tx"
+type Payload {
+	index: Int!
+	script: Int!
+	assetHash: Base64!
+	burnValue: Uint64!
+	dataVersion: Int!
+	"Data of the payload when it is plain text"
+	dataPublic: Base64
+	ringSize: Int!
+	"Reward of the staking reward payloads"
+	stakingReward: Uint64
+	asset: Asset
+	"Members of the ring. count is at most 100"
+	accounts(start: Int = 0, count: Int = 16): [Account!]!
+}
+
+type Asset {
+	hash: Base64!
+	version: Uint64!
+	name: String!
+	ticker: String!
+	identification: String!
+	description: String!
+	decimalSeparator: Int!
+	supply: Uint64!
+	maxSupply: Uint64!
+	canUpgrade: Boolean!
+	canMint: Boolean!
+	canBurn: Boolean!
+	updatePublicKey: Base64
+	supplyPublicKey: Base64
+}
+
+type Account {
+	publicKey: Base64!
+	registration: Registration
+	"count is at most 100"
+	balances(start: Int = 0, count: Int = 10): [Balance!]!
+}
+
+type Registration {
+	index: Uint64!
+	staked: Boolean!
+	spendPublicKey: Base64
+}
+
+"Encrypted balance of an account for an asset"
+type Balance {
+	assetHash: Base64!
+	asset: Asset
+	index: Uint64!
+	"ElGamal encrypted balance"
+	balance: Base64!
+}
+`
+
+type graphQLUint64 uint64
+
+func (graphQLUint64) ImplementsGraphQLType(name string) bool {
+	return name == "Uint64"
+}
+
+func (n *graphQLUint64) UnmarshalGraphQL(input any) error {
+	switch v := input.(type) {
+	case string:
+		value, err := strconv.ParseUint(v, 10, 64)
+		*n = graphQLUint64(value)
+		return err
+	case float64:
+		if v < 0 || v != math.Trunc(v) || v > 1<<53 {
+			return fmt.Errorf("%v is not an unsigned integer", v)
+		}
+		*n = graphQLUint64(v)
+		return nil
+	}
+	switch reflected := reflect.ValueOf(input); reflected.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if reflected.Int() >= 0 {
+			*n = graphQLUint64(reflected.Int())
+			return nil
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		*n = graphQLUint64(reflected.Uint())
+		return nil
+	}
+	return fmt.Errorf("%v is not an unsigned integer", input)
+}
+
+//graphQLBase64 is encoded in base64 by encoding/json
+type graphQLBase64 []byte
+
+func (graphQLBase64) ImplementsGraphQLType(name string) bool {
+	return name == "Base64"
+}
+
+func (b *graphQLBase64) UnmarshalGraphQL(input any) (err error) {
+	switch v := input.(type) {
+	case string:
+		*b, err = base64.StdEncoding.DecodeString(v)
+		return
+	case []byte:
+		*b = v
+		return
+	}
+	return fmt.Errorf("%v is not base64", input)
+}
+
+func optionalGraphQLBase64(data []byte) *graphQLBase64 {
+	if data == nil {
+		return nil
+	}
+	out := graphQLBase64(data)
+	return &out
+}
+
+func optionalGraphQLUint64(value uint64) *graphQLUint64 {
+	out := graphQLUint64(value)
+	return &out
+}
+
+type graphQLContextKey struct{}
+
+//graphQLContext is shared by the resolvers of a query. They run one at a time, so the complexity is not locked
+type graphQLContext struct {
+	reader     store_db_interface.StoreDBTransactionInterface
+	complexity int
+}
+
+//graphQLRead charges the objects read from the store to the complexity of the query and returns the reader of the query view
+func graphQLRead(ctx context.Context, count int) (store_db_interface.StoreDBTransactionInterface, error) {
+	query := ctx.Value(graphQLContextKey{}).(*graphQLContext)
+	if query.complexity += count; query.complexity > config.API_GRAPHQL_MAX_COMPLEXITY {
+		return nil, fmt.Errorf("The query exceeds the maximum complexity of %d", config.API_GRAPHQL_MAX_COMPLEXITY)
+	}
+	return query.reader, nil
+}
+
+type graphQLListArgs struct {
+	Start int32
+	Count int32
+}
+
+//page returns the [start, end) interval of the list
+func (args *graphQLListArgs) page(length int) (int, int, error) {
+	start, count := int(args.Start), int(args.Count)
+	if start < 0 || count < 0 || count > config.API_GRAPHQL_MAX_LIST {
+		return 0, 0, fmt.Errorf("Invalid start or count. Count must be at most %d", config.API_GRAPHQL_MAX_LIST)
+	}
+	start = generics.Min(start, length)
+	return start, generics.Min(start+count, length), nil
+}
+
+type graphQLQuery struct {
+	api *APICommon
+}
+
+type graphQLChain struct {
+	chain *APIBlockchain
+}
+
+type graphQLBlock struct {
+	hash []byte
+	blk  *block.Block
+	info *info.BlockInfo
+}
+
+type graphQLTx struct {
+	tx   *transaction.Transaction
+	info *info.TxInfo
+}
+
+type graphQLTxSimple struct {
+	tx *transaction_simple.TransactionSimple
+}
+
+type graphQLPayload struct {
+	index   int
+	payload *transaction_zether_payload.TransactionZetherPayload
+}
+
+type graphQLAsset struct {
+	ast *asset.Asset
+}
+
+type graphQLAccount struct {
+	publicKey []byte
+}
+
+type graphQLRegistration struct {
+	index          uint64
+	staked         bool
+	spendPublicKey []byte
+}
+
+type graphQLBalance struct {
+	asset []byte
+	acc   *account.Account
+}
+
+func loadGraphQLBlock(ctx context.Context, hash []byte) (*graphQLBlock, error) {
+
+	reader, err := graphQLRead(ctx, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	data := reader.Get("block_ByHash" + string(hash))
+	if data == nil {
+		return nil, nil
+	}
+
+	out := &graphQLBlock{hash, block.CreateEmptyBlock(), nil}
+	if err = out.blk.Deserialize(advanced_buffers.NewBufferReader(data)); err != nil {
+		return nil, err
+	}
+
+	if data = reader.Get("blockInfo_ByHash" + string(hash)); data != nil {
+		out.info = &info.BlockInfo{}
+		if err = msgpack.Unmarshal(data, out.info); err != nil {
+			return nil, err
+		}
+	}
+
+	return out, nil
+}
+
+func loadGraphQLBlockByHeight(ctx context.Context, height uint64) (*graphQLBlock, error) {
+	reader, err := graphQLRead(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+	hash := reader.Get("blockHash_ByHeight" + strconv.FormatUint(height, 10))
+	if hash == nil {
+		return nil, nil
+	}
+	return loadGraphQLBlock(ctx, hash)
+}
+
+func loadGraphQLTx(ctx context.Context, hash []byte) (*graphQLTx, error) {
+
+	reader, err := graphQLRead(ctx, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	data := reader.Get("tx:" + string(hash))
+	if data == nil {
+		return nil, nil
+	}
+
+	out := &graphQLTx{&transaction.Transaction{}, nil}
+	if err = out.tx.Deserialize(advanced_buffers.NewBufferReader(data)); err != nil {
+		return nil, err
+	}
+
+	if data = reader.Get("txInfo_ByHash" + string(hash)); data != nil {
+		out.info = &info.TxInfo{}
+		if err = msgpack.Unmarshal(data, out.info); err != nil {
+			return nil, err
+		}
+	}
+
+	return out, nil
+}
+
+func loadGraphQLAsset(ctx context.Context, hash []byte) (*graphQLAsset, error) {
+	if len(hash) != config_coins.ASSET_LENGTH {
+		return nil, errors.New("Invalid asset")
+	}
+	reader, err := graphQLRead(ctx, 1)
+	if err != nil {
+		return nil, err
+	}
+	ast, err := assets.NewAssets(reader).Get(string(hash))
+	if err != nil || ast == nil {
+		return nil, err
+	}
+	return &graphQLAsset{ast}, nil
+}
+
+func (query *graphQLQuery) Chain() *graphQLChain {
+	return &graphQLChain{NewAPIBlockchain(query.api.chain.GetChainData())}
+}
+
+func (query *graphQLQuery) Block(ctx context.Context, args struct {
+	Height *graphQLUint64
+	Hash   *graphQLBase64
+}) (*graphQLBlock, error) {
+	if args.Hash != nil {
+		return loadGraphQLBlock(ctx, *args.Hash)
+	}
+	if args.Height != nil {
+		return loadGraphQLBlockByHeight(ctx, uint64(*args.Height))
+	}
+	return nil, errors.New("height or hash is required")
+}
+
+func (query *graphQLQuery) Blocks(ctx context.Context, args struct {
+	Start graphQLUint64
+	Count int32
+}) ([]*graphQLBlock, error) {
+
+	if args.Count < 0 || int(args.Count) > config.API_GRAPHQL_MAX_LIST {
+		return nil, fmt.Errorf("Count must be at most %d", config.API_GRAPHQL_MAX_LIST)
+	}
+
+	out := make([]*graphQLBlock, 0, args.Count)
+	for height := uint64(args.Start); height < uint64(args.Start)+uint64(args.Count); height++ {
+		blk, err := loadGraphQLBlockByHeight(ctx, height)
+		if err != nil {
+			return nil, err
+		}
+		if blk == nil {
+			break
+		}
+		out = append(out, blk)
+	}
+	return out, nil
+}
+
+func (query *graphQLQuery) Tx(ctx context.Context, args struct {
+	Height *graphQLUint64
+	Hash   *graphQLBase64
+}) (*graphQLTx, error) {
+	if args.Hash != nil {
+		return loadGraphQLTx(ctx, *args.Hash)
+	}
+	if args.Height == nil {
+		return nil, errors.New("height or hash is required")
+	}
+	reader, err := graphQLRead(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+	hash := reader.Get("txHash_ByHeight" + strconv.FormatUint(uint64(*args.Height), 10))
+	if hash == nil {
+		return nil, nil
+	}
+	return loadGraphQLTx(ctx, hash)
+}
+
+func (query *graphQLQuery) Asset(ctx context.Context, args struct{ Hash graphQLBase64 }) (*graphQLAsset, error) {
+	return loadGraphQLAsset(ctx, args.Hash)
+}
+
+//Account returns nil for the public keys that are not registered
+func (query *graphQLQuery) Account(ctx context.Context, args struct{ PublicKey graphQLBase64 }) (*graphQLAccount, error) {
+	if len(args.PublicKey) != cryptography.PublicKeySize {
+		return nil, errors.New("Invalid public key")
+	}
+	reader, err := graphQLRead(ctx, 1)
+	if err != nil {
+		return nil, err
+	}
+	if exists, err := registrations.NewRegistrations(reader).Exists(string(args.PublicKey)); err != nil || !exists {
+		return nil, err
+	}
+	return &graphQLAccount{args.PublicKey}, nil
+}
+
+func (chain *graphQLChain) Height() graphQLUint64 { return graphQLUint64(chain.chain.Height) }
+func (chain *graphQLChain) Hash() string          { return chain.chain.Hash }
+func (chain *graphQLChain) PrevHash() string      { return chain.chain.PrevHash }
+func (chain *graphQLChain) KernelHash() string    { return chain.chain.KernelHash }
+func (chain *graphQLChain) PrevKernelHash() string {
+	return chain.chain.PrevKernelHash
+}
+func (chain *graphQLChain) Timestamp() graphQLUint64 { return graphQLUint64(chain.chain.Timestamp) }
+func (chain *graphQLChain) TransactionsCount() graphQLUint64 {
+	return graphQLUint64(chain.chain.TransactionsCount)
+}
+func (chain *graphQLChain) AccountsCount() graphQLUint64 {
+	return graphQLUint64(chain.chain.AccountsCount)
+}
+func (chain *graphQLChain) AssetsCount() graphQLUint64 { return graphQLUint64(chain.chain.AssetsCount) }
+func (chain *graphQLChain) Target() string             { return chain.chain.Target }
+func (chain *graphQLChain) Supply() graphQLUint64      { return graphQLUint64(chain.chain.Supply) }
+func (chain *graphQLChain) TotalDifficulty() string    { return chain.chain.TotalDifficulty }
+func (chain *graphQLChain) LastBlock(ctx context.Context) (*graphQLBlock, error) {
+	if chain.chain.Height == 0 {
+		return nil, nil
+	}
+	return loadGraphQLBlockByHeight(ctx, chain.chain.Height-1)
+}
+
+func (blk *graphQLBlock) Hash() graphQLBase64           { return blk.hash }
+func (blk *graphQLBlock) Height() graphQLUint64         { return graphQLUint64(blk.blk.Height) }
+func (blk *graphQLBlock) Version() graphQLUint64        { return graphQLUint64(blk.blk.Version) }
+func (blk *graphQLBlock) MerkleHash() graphQLBase64     { return blk.blk.MerkleHash }
+func (blk *graphQLBlock) PrevHash() graphQLBase64       { return blk.blk.PrevHash }
+func (blk *graphQLBlock) PrevKernelHash() graphQLBase64 { return blk.blk.PrevKernelHash }
+func (blk *graphQLBlock) Timestamp() graphQLUint64      { return graphQLUint64(blk.blk.Timestamp) }
+func (blk *graphQLBlock) StakingAmount() graphQLUint64  { return graphQLUint64(blk.blk.StakingAmount) }
+func (blk *graphQLBlock) StakingNonce() graphQLBase64   { return blk.blk.StakingNonce }
+
+func (blk *graphQLBlock) KernelHash() *graphQLBase64 {
+	if blk.info == nil {
+		return nil
+	}
+	return optionalGraphQLBase64(blk.info.KernelHash)
+}
+
+func (blk *graphQLBlock) Size() *graphQLUint64 {
+	if blk.info == nil {
+		return nil
+	}
+	return optionalGraphQLUint64(blk.info.Size)
+}
+
+func (blk *graphQLBlock) Fees() *graphQLUint64 {
+	if blk.info == nil {
+		return nil
+	}
+	return optionalGraphQLUint64(blk.info.Fees)
+}
+
+func (blk *graphQLBlock) TxsCount() *graphQLUint64 {
+	if blk.info == nil {
+		return nil
+	}
+	return optionalGraphQLUint64(blk.info.TXs)
+}
+
+func (blk *graphQLBlock) Txs(ctx context.Context, args graphQLListArgs) ([]*graphQLTx, error) {
+
+	reader, err := graphQLRead(ctx, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	var txHashes [][]byte
+	if data := reader.Get("blockTxs" + strconv.FormatUint(blk.blk.Height, 10)); data != nil {
+		if err = msgpack.Unmarshal(data, &txHashes); err != nil {
+			return nil, err
+		}
+	}
+
+	start, end, err := args.page(len(txHashes))
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]*graphQLTx, 0, end-start)
+	for _, hash := range txHashes[start:end] {
+		tx, err := loadGraphQLTx(ctx, hash)
+		if err != nil {
+			return nil, err
+		}
+		if tx != nil {
+			out = append(out, tx)
+		}
+	}
+	return out, nil
+}
+
+func (tx *graphQLTx) Hash() graphQLBase64 { return tx.tx.Bloom.Hash }
+func (tx *graphQLTx) Version() int32      { return int32(tx.tx.Version) }
+func (tx *graphQLTx) Size() graphQLUint64 { return graphQLUint64(tx.tx.Bloom.Size) }
+
+func (tx *graphQLTx) Height() *graphQLUint64 {
+	if tx.info == nil {
+		return nil
+	}
+	return optionalGraphQLUint64(tx.info.Height)
+}
+
+func (tx *graphQLTx) BlockHeight() *graphQLUint64 {
+	if tx.info == nil {
+		return nil
+	}
+	return optionalGraphQLUint64(tx.info.BlkHeight)
+}
+
+func (tx *graphQLTx) Timestamp() *graphQLUint64 {
+	if tx.info == nil {
+		return nil
+	}
+	return optionalGraphQLUint64(tx.info.Timestmap)
+}
+
+func (tx *graphQLTx) Fee() (graphQLUint64, error) {
+	fee, err := tx.tx.ComputeFee()
+	return graphQLUint64(fee), err
+}
+
+func (tx *graphQLTx) Block(ctx context.Context) (*graphQLBlock, error) {
+	if tx.info == nil {
+		return nil, nil
+	}
+	return loadGraphQLBlockByHeight(ctx, tx.info.BlkHeight)
+}
+
+func (tx *graphQLTx) Simple() *graphQLTxSimple {
+	if tx.tx.Version != transaction_type.TX_SIMPLE {
+		return nil
+	}
+	return &graphQLTxSimple{tx.tx.TransactionBaseInterface.(*transaction_simple.TransactionSimple)}
+}
+
+func (tx *graphQLTx) Payloads() []*graphQLPayload {
+	if tx.tx.Version != transaction_type.TX_ZETHER {
+		return []*graphQLPayload{}
+	}
+	payloads := tx.tx.TransactionBaseInterface.(*transaction_zether.TransactionZether).Payloads
+	out := make([]*graphQLPayload, len(payloads))
+	for i, payload := range payloads {
+		out[i] = &graphQLPayload{i, payload}
+	}
+	return out
+}
+
+func (tx *graphQLTx) Assets(ctx context.Context) ([]*graphQLAsset, error) {
+
+	var list [][]byte
+	if tx.tx.Version == transaction_type.TX_ZETHER {
+		unique := map[string]bool{}
+		for _, payload := range tx.tx.TransactionBaseInterface.(*transaction_zether.TransactionZether).Payloads {
+			if !unique[string(payload.Asset)] {
+				unique[string(payload.Asset)] = true
+				list = append(list, payload.Asset)
+			}
+		}
+	} else {
+		list = [][]byte{config_coins.NATIVE_ASSET_FULL}
+	}
+
+	out := make([]*graphQLAsset, 0, len(list))
+	for _, hash := range list {
+		ast, err := loadGraphQLAsset(ctx, hash)
+		if err != nil {
+			return nil, err
+		}
+		if ast != nil {
+			out = append(out, ast)
+		}
+	}
+	return out, nil
+}
+
+func (tx *graphQLTxSimple) Script() int32        { return int32(tx.tx.TxScript) }
+func (tx *graphQLTxSimple) Nonce() graphQLUint64 { return graphQLUint64(tx.tx.Nonce) }
+func (tx *graphQLTxSimple) Fee() graphQLUint64   { return graphQLUint64(tx.tx.Fee) }
+func (tx *graphQLTxSimple) DataVersion() int32   { return int32(tx.tx.DataVersion) }
+
+func (tx *graphQLTxSimple) DataPublic() *graphQLBase64 {
+	if tx.tx.DataVersion.String() != "plain" {
+		return nil
+	}
+	return optionalGraphQLBase64(tx.tx.Data)
+}
+
+func (tx *graphQLTxSimple) Vin() *graphQLAccount {
+	if !tx.tx.HasVin() {
+		return nil
+	}
+	return &graphQLAccount{tx.tx.Vin.PublicKey}
+}
+
+func (payload *graphQLPayload) Index() int32             { return int32(payload.index) }
+func (payload *graphQLPayload) Script() int32            { return int32(payload.payload.PayloadScript) }
+func (payload *graphQLPayload) AssetHash() graphQLBase64 { return payload.payload.Asset }
+func (payload *graphQLPayload) BurnValue() graphQLUint64 {
+	return graphQLUint64(payload.payload.BurnValue)
+}
+func (payload *graphQLPayload) DataVersion() int32 { return int32(payload.payload.DataVersion) }
+func (payload *graphQLPayload) RingSize() int32 {
+	return int32(len(payload.payload.Statement.Publickeylist))
+}
+
+func (payload *graphQLPayload) DataPublic() *graphQLBase64 {
+	if payload.payload.DataVersion.String() != "plain" {
+		return nil
+	}
+	return optionalGraphQLBase64(payload.payload.Data)
+}
+
+func (payload *graphQLPayload) StakingReward() *graphQLUint64 {
+	if extra, ok := payload.payload.Extra.(*transaction_zether_payload_extra.TransactionZetherPayloadExtraStakingReward); ok {
+		return optionalGraphQLUint64(extra.Reward)
+	}
+	return nil
+}
+
+func (payload *graphQLPayload) Asset(ctx context.Context) (*graphQLAsset, error) {
+	return loadGraphQLAsset(ctx, payload.payload.Asset)
+}
+
+func (payload *graphQLPayload) Accounts(args graphQLListArgs) ([]*graphQLAccount, error) {
+	publicKeys := payload.payload.Statement.Publickeylist
+	start, end, err := args.page(len(publicKeys))
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*graphQLAccount, end-start)
+	for i := range out {
+		out[i] = &graphQLAccount{publicKeys[start+i].EncodeCompressed()}
+	}
+	return out, nil
+}
+
+func (ast *graphQLAsset) Hash() graphQLBase64      { return ast.ast.PublicKeyHash }
+func (ast *graphQLAsset) Version() graphQLUint64   { return graphQLUint64(ast.ast.Version) }
+func (ast *graphQLAsset) Name() string             { return ast.ast.Name }
+func (ast *graphQLAsset) Ticker() string           { return ast.ast.Ticker }
+func (ast *graphQLAsset) Identification() string   { return ast.ast.Identification }
+func (ast *graphQLAsset) Description() string      { return ast.ast.Description }
+func (ast *graphQLAsset) DecimalSeparator() int32  { return int32(ast.ast.DecimalSeparator) }
+func (ast *graphQLAsset) Supply() graphQLUint64    { return graphQLUint64(ast.ast.Supply) }
+func (ast *graphQLAsset) MaxSupply() graphQLUint64 { return graphQLUint64(ast.ast.MaxSupply) }
+func (ast *graphQLAsset) CanUpgrade() bool         { return ast.ast.CanUpgrade }
+func (ast *graphQLAsset) CanMint() bool            { return ast.ast.CanMint }
+func (ast *graphQLAsset) CanBurn() bool            { return ast.ast.CanBurn }
+func (ast *graphQLAsset) UpdatePublicKey() *graphQLBase64 {
+	return optionalGraphQLBase64(ast.ast.UpdatePublicKey)
+}
+func (ast *graphQLAsset) SupplyPublicKey() *graphQLBase64 {
+	return optionalGraphQLBase64(ast.ast.SupplyPublicKey)
+}
+
+func (acc *graphQLAccount) PublicKey() graphQLBase64 { return acc.publicKey }
+
+func (acc *graphQLAccount) Registration(ctx context.Context) (*graphQLRegistration, error) {
+	reader, err := graphQLRead(ctx, 1)
+	if err != nil {
+		return nil, err
+	}
+	reg, err := registrations.NewRegistrations(reader).Get(string(acc.publicKey))
+	if err != nil || reg == nil {
+		return nil, err
+	}
+	return &graphQLRegistration{reg.Index, reg.Staked, reg.SpendPublicKey}, nil
+}
+
+//Balances pages the assets of the account, so only the requested balances are read
+func (acc *graphQLAccount) Balances(ctx context.Context, args graphQLListArgs) ([]*graphQLBalance, error) {
+
+	reader, err := graphQLRead(ctx, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	accsCollection := accounts.NewAccountsCollection(reader)
+
+	assetsList, err := accsCollection.GetAccountAssets(acc.publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	start, end, err := args.page(len(assetsList))
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]*graphQLBalance, 0, end-start)
+	for _, assetId := range assetsList[start:end] {
+
+		if _, err = graphQLRead(ctx, 1); err != nil {
+			return nil, err
+		}
+
+		accs, err := accsCollection.GetMap(assetId)
+		if err != nil {
+			return nil, err
+		}
+
+		balance, err := accs.Get(string(acc.publicKey))
+		if err != nil {
+			return nil, err
+		}
+		if balance != nil {
+			out = append(out, &graphQLBalance{assetId, balance})
+		}
+	}
+	return out, nil
+}
+
+func (reg *graphQLRegistration) Index() graphQLUint64 { return graphQLUint64(reg.index) }
+func (reg *graphQLRegistration) Staked() bool         { return reg.staked }
+func (reg *graphQLRegistration) SpendPublicKey() *graphQLBase64 {
+	return optionalGraphQLBase64(reg.spendPublicKey)
+}
+
+func (balance *graphQLBalance) AssetHash() graphQLBase64 { return balance.asset }
+func (balance *graphQLBalance) Index() graphQLUint64     { return graphQLUint64(balance.acc.Index) }
+func (balance *graphQLBalance) Balance() graphQLBase64 {
+	return balance.acc.Balance.Amount.Serialize()
+}
+
+func (balance *graphQLBalance) Asset(ctx context.Context) (*graphQLAsset, error) {
+	return loadGraphQLAsset(ctx, balance.asset)
+}
+
+//createGraphQLSchema runs the resolvers one at a time, so the reads of the store view and the complexity are not concurrent
+func (api *APICommon) createGraphQLSchema() (*graphql.Schema, error) {
+	return graphql.ParseSchema(graphQLSDL, &graphQLQuery{api},
+		graphql.UseStringDescriptions(),
+		graphql.MaxDepth(config.API_GRAPHQL_MAX_DEPTH),
+		graphql.MaxParallelism(1),
+	)
+}
@@ -0,0 +1,151 @@
+package api_common
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"pandora-pay/addresses"
+	"pandora-pay/blockchain"
+	"pandora-pay/blockchain/genesis"
+	"pandora-pay/config"
+	"pandora-pay/config/config_coins"
+	"pandora-pay/config/config_stake"
+	"pandora-pay/cryptography"
+	"pandora-pay/gui"
+	"pandora-pay/gui/gui_non_interactive"
+	"pandora-pay/helpers"
+	"pandora-pay/mempool"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_memory"
+	"pandora-pay/txs_validator"
+	"strings"
+	"testing"
+)
+
+//createGraphQLDevnet initializes an in-memory devnet chain whose genesis airdrops the returned address
+func createGraphQLDevnet(t *testing.T) (*APICommon, *addresses.Address) {
+
+	var err error
+	gui.GUI, err = gui_non_interactive.CreateGUINonInteractive()
+	assert.Nil(t, err)
+
+	config.NETWORK_SELECTED = config.DEV_NET_NETWORK_BYTE
+	config.CONSENSUS = config.CONSENSUS_TYPE_FULL
+	config.SEED_WALLET_NODES_INFO = true
+
+	for _, s := range []**store.Store{&store.StoreBlockchain, &store.StoreWallet, &store.StoreSettings, &store.StoreMempool, &store.StoreBalancesDecrypted} {
+		db, err := store_db_memory.CreateStoreDBMemory("graphql")
+		assert.Nil(t, err)
+		*s = &store.Store{Name: "graphql", Opened: true, DB: db}
+	}
+
+	addr, err := addresses.GenerateNewPrivateKey().GenerateAddress(true, nil, true, nil, 0, nil)
+	assert.Nil(t, err)
+
+	genesis.GenesisData, err = genesis.GetDefaultGenesis()
+	assert.Nil(t, err)
+	genesis.GenesisData.Hash = helpers.RandomBytes(cryptography.HashSize)
+	genesis.GenesisData.AirDrops = append(genesis.GenesisData.AirDrops, &genesis.GenesisDataAirDropType{Address: addr.EncodeAddr(), Amount: 100 * config_stake.GetRequiredStake(0)})
+
+	txsValidator, err := txs_validator.NewTxsValidator()
+	assert.Nil(t, err)
+	mp, err := mempool.CreateMempool(txsValidator)
+	assert.Nil(t, err)
+	chain, err := blockchain.CreateBlockchain(mp, txsValidator)
+	assert.Nil(t, err)
+	assert.Nil(t, chain.InitializeChain())
+
+	api := &APICommon{chain: chain}
+	api.graphQLSchema, err = api.createGraphQLSchema()
+	assert.Nil(t, err)
+
+	return api, addr
+}
+
+func graphQL(t *testing.T, api *APICommon, query string, variables map[string]any) map[string]any {
+
+	reply := &APIGraphQLReply{}
+	assert.Nil(t, api.GraphQL(nil, &APIGraphQLRequest{query, "", variables}, reply))
+
+	data, err := json.Marshal(reply)
+	assert.Nil(t, err)
+
+	out := map[string]any{}
+	assert.Nil(t, json.Unmarshal(data, &out))
+	return out
+}
+
+func TestGraphQL(t *testing.T) {
+
+	api, addr := createGraphQLDevnet(t)
+
+	result := graphQL(t, api, `{ chain { height assetsCount lastBlock { height } } block(height: 0) { hash } }`, nil)
+	assert.Nil(t, result["errors"])
+	assert.Equal(t, map[string]any{
+		"chain": map[string]any{"height": float64(api.chain.GetChainData().Height), "assetsCount": float64(1), "lastBlock": nil},
+		"block": nil,
+	}, result["data"])
+
+	result = graphQL(t, api, `query Q($hash: Base64!) { asset(hash: $hash) { name ticker canMint } }`, map[string]any{"hash": config_coins.NATIVE_ASSET_FULL_STRING_BASE64})
+	assert.Nil(t, result["errors"])
+	assert.Equal(t, map[string]any{"name": config_coins.NATIVE_ASSET_NAME, "ticker": config_coins.NATIVE_ASSET_TICKER, "canMint": false}, result["data"].(map[string]any)["asset"])
+
+	//the airdrop registers the account and joins its balance with the native asset
+	result = graphQL(t, api, `query Q($pk: Base64!) { account(publicKey: $pk) { publicKey registration { staked } balances { assetHash asset { ticker } } } }`, map[string]any{"pk": base64.StdEncoding.EncodeToString(addr.PublicKey)})
+	assert.Nil(t, result["errors"])
+	assert.Equal(t, map[string]any{
+		"publicKey":    base64.StdEncoding.EncodeToString(addr.PublicKey),
+		"registration": map[string]any{"staked": true},
+		"balances":     []any{map[string]any{"assetHash": config_coins.NATIVE_ASSET_FULL_STRING_BASE64, "asset": map[string]any{"ticker": config_coins.NATIVE_ASSET_TICKER}}},
+	}, result["data"].(map[string]any)["account"])
+
+	result = graphQL(t, api, `query Q($pk: Base64!) { account(publicKey: $pk) { publicKey } }`, map[string]any{"pk": base64.StdEncoding.EncodeToString(helpers.RandomBytes(cryptography.PublicKeySize))})
+	assert.Nil(t, result["errors"])
+	assert.Nil(t, result["data"].(map[string]any)["account"])
+
+	result = graphQL(t, api, `{ blocks(start: 0, count: 1000) { hash } }`, nil)
+	assert.NotNil(t, result["errors"])
+
+	result = graphQL(t, api, `mutation { chain { height } }`, nil)
+	assert.NotNil(t, result["errors"])
+}
+
+func TestGraphQLLimits(t *testing.T) {
+
+	api, addr := createGraphQLDevnet(t)
+
+	//every object read from the store is charged, so only two of the assets are read
+	defer func(complexity int) { config.API_GRAPHQL_MAX_COMPLEXITY = complexity }(config.API_GRAPHQL_MAX_COMPLEXITY)
+	config.API_GRAPHQL_MAX_COMPLEXITY = 2
+
+	result := graphQL(t, api, `query Q($hash: Base64!) { a: asset(hash: $hash) { ticker } b: asset(hash: $hash) { ticker } c: asset(hash: $hash) { ticker } }`, map[string]any{"hash": config_coins.NATIVE_ASSET_FULL_STRING_BASE64})
+	read := 0
+	for _, value := range result["data"].(map[string]any) {
+		if value != nil {
+			assert.Equal(t, map[string]any{"ticker": config_coins.NATIVE_ASSET_TICKER}, value)
+			read++
+		}
+	}
+	assert.Equal(t, 2, read)
+	assert.Equal(t, "The query exceeds the maximum complexity of 2", result["errors"].([]any)[0].(map[string]any)["message"])
+
+	//the balances are paged, so the account and the list of its assets are the only reads
+	result = graphQL(t, api, `query Q($pk: Base64!) { account(publicKey: $pk) { balances(start: 1) { assetHash } } }`, map[string]any{"pk": base64.StdEncoding.EncodeToString(addr.PublicKey)})
+	assert.Nil(t, result["errors"])
+	assert.Equal(t, map[string]any{"balances": []any{}}, result["data"].(map[string]any)["account"])
+
+	result = graphQL(t, api, `query Q($pk: Base64!) { account(publicKey: $pk) { balances(count: 1000) { assetHash } } }`, map[string]any{"pk": base64.StdEncoding.EncodeToString(addr.PublicKey)})
+	assert.NotNil(t, result["errors"])
+
+	result = graphQL(t, api, `{ chain { lastBlock { txs(count: 1) { block { txs(count: 1) { block { txs(count: 1) { block { txs(count: 1) { block { hash } } } } } } } } } } }`, nil)
+	assert.Nil(t, result["data"])
+	assert.Contains(t, result["errors"].([]any)[0].(map[string]any)["message"], "exceeds max depth 10")
+
+	config.API_GRAPHQL_MAX_COMPLEXITY = 2000
+	result = graphQL(t, api, "{ chain { height } }"+strings.Repeat(" ", config.API_GRAPHQL_MAX_QUERY_LENGTH), nil)
+	assert.Equal(t, "The query exceeds the maximum length of 10000", result["errors"].([]any)[0].(map[string]any)["message"])
+
+	schema := &APIGraphQLSchemaReply{}
+	assert.Nil(t, api.GetGraphQLSchema(nil, &struct{}{}, schema))
+	assert.Contains(t, schema.SDL, "type Block {")
+}
@@ -1,6 +1,7 @@
 package api_common
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"pandora-pay/blockchain/data_storage/accounts"
@@ -21,13 +22,21 @@ type APIAccountsByKeysRequest struct {
 	Asset          helpers.Base64                     `json:"asset,omitempty" msgpack:"asset,omitempty"`
 	IncludeMempool bool                               `json:"includeMempool,omitempty" msgpack:"includeMempool,omitempty"`
 	ReturnType     api_types.APIReturnType            `json:"returnType,omitempty" msgpack:"returnType,omitempty"`
+	Proof          bool                               `json:"proof,omitempty" msgpack:"proof,omitempty"`
 }
 
 type APIAccountsByKeysReply struct {
-	Acc           []*account.Account           `json:"account,omitempty" msgpack:"account,omitempty"`
-	AccSerialized [][]byte                     `json:"accountSerialized,omitempty" msgpack:"accountSerialized,omitempty"`
-	Reg           []*registration.Registration `json:"registration,omitempty" msgpack:"registration,omitempty"`
-	RegSerialized [][]byte                     `json:"registrationSerialized,omitempty" msgpack:"registrationSerialized,omitempty"`
+	Acc           []*account.Account            `json:"account,omitempty" msgpack:"account,omitempty"`
+	AccSerialized [][]byte                      `json:"accountSerialized,omitempty" msgpack:"accountSerialized,omitempty"`
+	Reg           []*registration.Registration  `json:"registration,omitempty" msgpack:"registration,omitempty"`
+	RegSerialized [][]byte                      `json:"registrationSerialized,omitempty" msgpack:"registrationSerialized,omitempty"`
+	StateRoot     []byte                        `json:"stateRoot,omitempty" msgpack:"stateRoot,omitempty"`
+	RegsStateRoot []byte                        `json:"registrationsStateRoot,omitempty" msgpack:"registrationsStateRoot,omitempty"`
+	AssetProof    *accounts.AssetsStateProof    `json:"assetProof,omitempty" msgpack:"assetProof,omitempty"`
+	ChainHeight   uint64                        `json:"chainHeight,omitempty" msgpack:"chainHeight,omitempty"`
+	ChainHash     []byte                        `json:"chainHash,omitempty" msgpack:"chainHash,omitempty"`
+	Proofs        []*accounts.AccountStateProof `json:"proofs,omitempty" msgpack:"proofs,omitempty"`
+	RegProofs     []*accounts.AccountStateProof `json:"registrationProofs,omitempty" msgpack:"registrationProofs,omitempty"`
 }
 
 func (api *APICommon) GetAccountsByKeys(r *http.Request, args *APIAccountsByKeysRequest, reply *APIAccountsByKeysReply) (err error) {
@@ -45,6 +54,10 @@ func (api *APICommon) GetAccountsByKeys(r *http.Request, args *APIAccountsByKeys
 		return fmt.Errorf("Too many indexes to process: limit %d, found %d", 512*2, len(publicKeys))
 	}
 
+	if args.Proof {
+		return api.getProvenAccountsByKeys(publicKeys, args, reply)
+	}
+
 	if err = store.StoreBlockchain.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
 
 		accsCollection := accounts.NewAccountsCollection(reader)
@@ -109,3 +122,45 @@ func (api *APICommon) GetAccountsByKeys(r *http.Request, args *APIAccountsByKeys
 	}
 	return
 }
+
+//getProvenAccountsByKeys returns the accounts and the registrations of the state committed by the last block together with their proofs
+func (api *APICommon) getProvenAccountsByKeys(publicKeys [][]byte, args *APIAccountsByKeysRequest, reply *APIAccountsByKeysReply) (err error) {
+
+	if args.IncludeMempool {
+		return errors.New("Mempool balances can not be proven")
+	}
+	if args.ReturnType != api_types.RETURN_SERIALIZED {
+		return errors.New("Proofs are returned only with the serialized accounts")
+	}
+
+	stateRoot, err := api.getStateRoot()
+	if err != nil {
+		return
+	}
+
+	state, assetProof, err := stateRoot.GetAssetProof(args.Asset)
+	if err != nil {
+		return
+	}
+
+	reply.StateRoot = state.Root
+	reply.RegsStateRoot = stateRoot.Registrations.Root
+	reply.AssetProof = assetProof
+	reply.ChainHeight = stateRoot.ChainHeight
+	reply.ChainHash = stateRoot.ChainHash
+	reply.AccSerialized = make([][]byte, len(publicKeys))
+	reply.RegSerialized = make([][]byte, len(publicKeys))
+	reply.Proofs = make([]*accounts.AccountStateProof, len(publicKeys))
+	reply.RegProofs = make([]*accounts.AccountStateProof, len(publicKeys))
+
+	for i, publicKey := range publicKeys {
+		if reply.AccSerialized[i], reply.Proofs[i], err = state.GetProof(publicKey); err != nil {
+			return
+		}
+		if reply.RegSerialized[i], reply.RegProofs[i], err = stateRoot.Registrations.GetProof(publicKey); err != nil {
+			return
+		}
+	}
+
+	return
+}
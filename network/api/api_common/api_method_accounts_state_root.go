@@ -0,0 +1,59 @@
+package api_common
+
+import (
+	"errors"
+	"net/http"
+	"pandora-pay/blockchain/data_storage"
+	"pandora-pay/blockchain/data_storage/accounts"
+	"pandora-pay/config"
+	"pandora-pay/helpers"
+)
+
+type APIAccountsStateRootRequest struct {
+	Asset helpers.Base64 `json:"asset" msgpack:"asset"`
+}
+
+type APIAccountsStateRootReply struct {
+	StateRoot   []byte                     `json:"stateRoot" msgpack:"stateRoot"`
+	Root        []byte                     `json:"root" msgpack:"root"`
+	Count       uint64                     `json:"count" msgpack:"count"`
+	AssetProof  *accounts.AssetsStateProof `json:"assetProof" msgpack:"assetProof"`
+	ChainHeight uint64                     `json:"chainHeight" msgpack:"chainHeight"`
+	ChainHash   []byte                     `json:"chainHash" msgpack:"chainHash"`
+}
+
+//getStateRoot returns the state root committed by the last block. The accounts states are computed by the chain only for config.STATE_ROOT_ASSETS, never on request
+func (api *APICommon) getStateRoot() (*data_storage.StateRoot, error) {
+
+	if !config.SEED_WALLET_NODES_INFO {
+		return nil, errors.New("Node is not serving state proofs")
+	}
+
+	stateRoot := api.chain.StateRoot.Load()
+	if stateRoot == nil {
+		return nil, errors.New("Last block doesn't have a state root yet")
+	}
+
+	return stateRoot, nil
+}
+
+func (api *APICommon) GetAccountsStateRoot(r *http.Request, args *APIAccountsStateRootRequest, reply *APIAccountsStateRootReply) error {
+
+	stateRoot, err := api.getStateRoot()
+	if err != nil {
+		return err
+	}
+
+	state, proof, err := stateRoot.GetAssetProof(args.Asset)
+	if err != nil {
+		return err
+	}
+
+	reply.StateRoot = stateRoot.Root
+	reply.Root = state.Root
+	reply.Count = uint64(len(state.Keys))
+	reply.AssetProof = proof
+	reply.ChainHeight = stateRoot.ChainHeight
+	reply.ChainHash = stateRoot.ChainHash
+	return nil
+}
@@ -0,0 +1,43 @@
+package api_common
+
+import (
+	"net/http"
+	"pandora-pay/config/config_auth"
+)
+
+type APIAuthTokenIssueRequest struct {
+	Roles     []config_auth.Role `json:"roles" msgpack:"roles"`
+	ExpiresAt int64              `json:"expiresAt,omitempty" msgpack:"expiresAt,omitempty"`
+}
+
+type APIAuthTokenIssueReply struct {
+	Token string                `json:"token" msgpack:"token"` //returned only once
+	Info  *config_auth.APIToken `json:"info" msgpack:"info"`
+}
+
+type APIAuthTokenRevokeRequest struct {
+	Id string `json:"id" msgpack:"id"`
+}
+
+type APIAuthTokenRevokeReply struct {
+	Status bool `json:"status" msgpack:"status"`
+}
+
+type APIAuthTokensReply struct {
+	Tokens []*config_auth.APIToken `json:"tokens" msgpack:"tokens"`
+}
+
+func (api *APICommon) AuthTokenIssue(r *http.Request, args *APIAuthTokenIssueRequest, reply *APIAuthTokenIssueReply, principal *config_auth.Principal) (err error) {
+	reply.Token, reply.Info, err = config_auth.IssueToken(principal, args.Roles, args.ExpiresAt)
+	return
+}
+
+func (api *APICommon) AuthTokenRevoke(r *http.Request, args *APIAuthTokenRevokeRequest, reply *APIAuthTokenRevokeReply, principal *config_auth.Principal) (err error) {
+	reply.Status, err = config_auth.RevokeToken(args.Id)
+	return
+}
+
+func (api *APICommon) GetAuthTokens(r *http.Request, args *struct{}, reply *APIAuthTokensReply, principal *config_auth.Principal) (err error) {
+	reply.Tokens = config_auth.GetTokens()
+	return
+}
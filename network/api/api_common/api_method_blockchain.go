@@ -1,7 +1,9 @@
 package api_common
 
 import (
+	"encoding/base64"
 	"net/http"
+	"pandora-pay/blockchain"
 )
 
 type APIBlockchain struct {
@@ -19,6 +21,23 @@ type APIBlockchain struct {
 	TotalDifficulty   string `json:"totalDifficulty" msgpack:"totalDifficulty"`
 }
 
+func NewAPIBlockchain(chainData *blockchain.BlockchainData) *APIBlockchain {
+	return &APIBlockchain{
+		chainData.Height,
+		base64.StdEncoding.EncodeToString(chainData.Hash),
+		base64.StdEncoding.EncodeToString(chainData.PrevHash),
+		base64.StdEncoding.EncodeToString(chainData.KernelHash),
+		base64.StdEncoding.EncodeToString(chainData.PrevKernelHash),
+		chainData.Timestamp,
+		chainData.TransactionsCount,
+		chainData.AccountsCount,
+		chainData.AssetsCount,
+		chainData.Target.String(),
+		chainData.Supply,
+		chainData.BigTotalDifficulty.String(),
+	}
+}
+
 func (api *APICommon) GetBlockchain(r *http.Request, args *struct{}, reply *APIBlockchain) error {
 	x := api.localChain.Load()
 	*reply = *x
@@ -0,0 +1,72 @@
+package api_common
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"pandora-pay/config"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_interface"
+)
+
+type APIGraphQLRequest struct {
+	Query         string         `json:"query" msgpack:"query"`
+	OperationName string         `json:"operationName,omitempty" msgpack:"operationName,omitempty"`
+	Variables     map[string]any `json:"variables,omitempty" msgpack:"variables,omitempty"`
+}
+
+type APIGraphQLLocation struct {
+	Line   int `json:"line" msgpack:"line"`
+	Column int `json:"column" msgpack:"column"`
+}
+
+type APIGraphQLError struct {
+	Message   string                `json:"message" msgpack:"message"`
+	Locations []*APIGraphQLLocation `json:"locations,omitempty" msgpack:"locations,omitempty"`
+	Path      []any                 `json:"path,omitempty" msgpack:"path,omitempty"`
+}
+
+//APIGraphQLReply has the data encoded in json, as it keeps the fields in the order of the query
+type APIGraphQLReply struct {
+	Data   json.RawMessage    `json:"data,omitempty" msgpack:"data,omitempty"`
+	Errors []*APIGraphQLError `json:"errors,omitempty" msgpack:"errors,omitempty"`
+}
+
+type APIGraphQLSchemaReply struct {
+	SDL string `json:"sdl" msgpack:"sdl"`
+}
+
+//GraphQL executes the query using a single view, so the joined data is consistent. The errors of the query are returned in the reply
+func (api *APICommon) GraphQL(r *http.Request, args *APIGraphQLRequest, reply *APIGraphQLReply) error {
+
+	if len(args.Query) > config.API_GRAPHQL_MAX_QUERY_LENGTH {
+		reply.Errors = []*APIGraphQLError{{Message: fmt.Sprintf("The query exceeds the maximum length of %d", config.API_GRAPHQL_MAX_QUERY_LENGTH)}}
+		return nil
+	}
+
+	ctx := context.Background()
+	if r != nil {
+		ctx = r.Context()
+	}
+
+	return store.StoreBlockchain.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) error {
+
+		response := api.graphQLSchema.Exec(context.WithValue(ctx, graphQLContextKey{}, &graphQLContext{reader, 0}), args.Query, args.OperationName, args.Variables)
+
+		reply.Data = response.Data
+		reply.Errors = make([]*APIGraphQLError, len(response.Errors))
+		for i, err := range response.Errors {
+			reply.Errors[i] = &APIGraphQLError{err.Message, make([]*APIGraphQLLocation, len(err.Locations)), err.Path}
+			for j, location := range err.Locations {
+				reply.Errors[i].Locations[j] = &APIGraphQLLocation{location.Line, location.Column}
+			}
+		}
+		return nil
+	})
+}
+
+func (api *APICommon) GetGraphQLSchema(r *http.Request, args *struct{}, reply *APIGraphQLSchemaReply) error {
+	reply.SDL = graphQLSDL
+	return nil
+}
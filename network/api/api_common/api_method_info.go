@@ -3,13 +3,16 @@ package api_common
 import (
 	"net/http"
 	"pandora-pay/config"
+	"pandora-pay/config/config_forks"
 )
 
 type APIInfoReply struct {
-	Name       string `json:"name" msgpack:"name"`
-	Version    string `json:"version" msgpack:"version"`
-	Network    uint64 `json:"network" msgpack:"network"`
-	CPUThreads int    `json:"CPUThreads" msgpack:"CPUThreads"`
+	Name             string               `json:"name" msgpack:"name"`
+	Version          string               `json:"version" msgpack:"version"`
+	Network          uint64               `json:"network" msgpack:"network"`
+	CPUThreads       int                  `json:"CPUThreads" msgpack:"CPUThreads"`
+	ActiveUpgrade    string               `json:"activeUpgrade" msgpack:"activeUpgrade"`
+	UpcomingUpgrades []*config_forks.Fork `json:"upcomingUpgrades" msgpack:"upcomingUpgrades"`
 }
 
 func (api *APICommon) GetInfo(r *http.Request, args *struct{}, reply *APIInfoReply) error {
@@ -17,5 +20,10 @@ func (api *APICommon) GetInfo(r *http.Request, args *struct{}, reply *APIInfoRep
 	reply.Version = config.VERSION_STRING
 	reply.Network = config.NETWORK_SELECTED
 	reply.CPUThreads = config.CPU_THREADS
+
+	//the next block is included at the current chain height
+	height := api.chain.GetChainData().Height
+	reply.ActiveUpgrade = config_forks.GetActiveFork(height).Name
+	reply.UpcomingUpgrades = config_forks.GetUpcomingForks(height)
 	return nil
 }
@@ -1,11 +1,16 @@
 package api_common
 
 import (
+	"context"
+	"errors"
+	"github.com/vmihailenco/msgpack/v5"
 	"math/rand"
 	"net/http"
 	"pandora-pay/config"
 	"pandora-pay/helpers/generics"
+	"pandora-pay/network/known_nodes"
 	"pandora-pay/network/known_nodes/known_node"
+	"pandora-pay/network/websocks/connection"
 	"pandora-pay/store/min_max_heap"
 	"sync/atomic"
 	"time"
@@ -20,6 +25,14 @@ type APINetworkNodesReply struct {
 	Nodes []*APINetworkNode `json:"nodes" msgpack:"nodes"`
 }
 
+type APINetworkNodesPushRequest struct {
+	Nodes []string `json:"nodes" msgpack:"nodes"`
+}
+
+type APINetworkNodesPushReply struct {
+	Added int `json:"added" msgpack:"added"`
+}
+
 func (api *APICommon) GetList(reply *APINetworkNodesReply) (err error) {
 
 	now := time.Now()
@@ -114,3 +127,37 @@ func (api *APICommon) GetList(reply *APINetworkNodesReply) (err error) {
 func (api *APICommon) GetNetworkNodes(r *http.Request, args *struct{}, reply *APINetworkNodesReply) error {
 	return api.GetList(reply)
 }
+
+//NetworkNodesPush receives the url advertised by a full node. Only the url of the sender is accepted: it must be the url of its handshake and resolve to the address of the connection
+func (api *APICommon) NetworkNodesPush(conn *connection.AdvancedConnection, values []byte) (interface{}, error) {
+
+	if conn.Handshake == nil || conn.Handshake.Consensus != config.CONSENSUS_TYPE_FULL {
+		return nil, errors.New("Only full nodes can advertise nodes")
+	}
+
+	request := &APINetworkNodesPushRequest{}
+	if err := msgpack.Unmarshal(values, request); err != nil {
+		return nil, err
+	}
+	if len(request.Nodes) > config.NETWORK_PEER_EXCHANGE_MAX_NODES {
+		return nil, errors.New("Too many nodes")
+	}
+
+	reply := &APINetworkNodesPushReply{}
+	for _, url := range request.Nodes {
+		if url == "" || url != conn.Handshake.URL {
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), config.NETWORK_PEER_EXCHANGE_RESOLVE_TIMEOUT)
+		isPeerURL := known_nodes.IsPeerURL(ctx, url, conn.RemoteAddr)
+		cancel()
+
+		if isPeerURL {
+			reply.Added = api.knownNodes.AddKnownNodesFromPeer(known_nodes.GetPeerHost(conn.RemoteAddr), []string{url})
+		}
+		break
+	}
+
+	return reply, nil
+}
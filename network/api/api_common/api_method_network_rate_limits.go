@@ -0,0 +1,11 @@
+package api_common
+
+import (
+	"net/http"
+	"pandora-pay/network/rate_limiter"
+)
+
+func (api *APICommon) GetNetworkRateLimits(r *http.Request, args *struct{}, reply *rate_limiter.RateLimiterMetrics) error {
+	*reply = *api.RateLimiter.GetMetrics()
+	return nil
+}
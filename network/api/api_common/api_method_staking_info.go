@@ -11,9 +11,10 @@ type APIStakingInfoRequest struct {
 }
 
 type APIStakingInfoReply struct {
-	BlockReward        uint64 `json:"blockReward" msgpack:"blockReward"`
-	RequiredStake      uint64 `json:"requiredStake" msgpack:"requiredStake"`
-	PendingStakeWindow uint64 `json:"pendingStakeWindow" msgpack:"pendingStakeWindow"`
+	BlockReward          uint64 `json:"blockReward" msgpack:"blockReward"`
+	RequiredStake        uint64 `json:"requiredStake" msgpack:"requiredStake"`
+	PendingStakeWindow   uint64 `json:"pendingStakeWindow" msgpack:"pendingStakeWindow"`
+	PendingUnstakeWindow uint64 `json:"pendingUnstakeWindow" msgpack:"pendingUnstakeWindow"`
 }
 
 func (api *APICommon) GetStakingInfo(r *http.Request, args *APIStakingInfoRequest, reply *APIStakingInfoReply) error {
@@ -21,6 +22,7 @@ func (api *APICommon) GetStakingInfo(r *http.Request, args *APIStakingInfoReques
 	reply.BlockReward = config_reward.GetRewardAt(args.Height)
 	reply.RequiredStake = config_stake.GetRequiredStake(args.Height)
 	reply.PendingStakeWindow = config_stake.GetPendingStakeWindow(args.Height)
+	reply.PendingUnstakeWindow = config_stake.GetPendingUnstakeWindow(args.Height)
 
 	return nil
 }
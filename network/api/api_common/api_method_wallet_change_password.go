@@ -0,0 +1,26 @@
+package api_common
+
+import (
+	"net/http"
+	"pandora-pay/config/config_auth"
+)
+
+type APIWalletChangePasswordRequest struct {
+	OldPassword string `json:"oldPassword" msgpack:"oldPassword"`
+	NewPassword string `json:"newPassword" msgpack:"newPassword"`
+	Difficulty  int    `json:"difficulty" msgpack:"difficulty"`
+}
+
+type APIWalletChangePasswordReply struct {
+	Status bool `json:"status" msgpack:"status"`
+}
+
+func (api *APICommon) WalletChangePassword(r *http.Request, args *APIWalletChangePasswordRequest, reply *APIWalletChangePasswordReply, principal *config_auth.Principal) (err error) {
+
+	if err = api.wallet.Encryption.ChangePassword(args.OldPassword, args.NewPassword, args.Difficulty); err != nil {
+		return
+	}
+
+	reply.Status = true
+	return
+}
@@ -1,8 +1,8 @@
 package api_common
 
 import (
-	"errors"
 	"net/http"
+	"pandora-pay/config/config_auth"
 	"pandora-pay/helpers/generics"
 	"pandora-pay/wallet/wallet_address"
 )
@@ -17,11 +17,7 @@ type APIWalletCreateAddressReply struct {
 	Address *wallet_address.WalletAddress `json:"address" msgpack:"address"`
 }
 
-func (api *APICommon) GetWalletCreateAddress(r *http.Request, args *APIWalletCreateAddressRequest, reply *APIWalletCreateAddressReply, authenticated bool) error {
-	if !authenticated {
-		return errors.New("Invalid User or Password")
-	}
-
+func (api *APICommon) GetWalletCreateAddress(r *http.Request, args *APIWalletCreateAddressRequest, reply *APIWalletCreateAddressReply, principal *config_auth.Principal) error {
 	addr, err := api.wallet.AddNewAddress(true, args.Name, args.Staked, args.SpendRequired, true)
 	if err != nil {
 		return err
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"net/http"
 	"pandora-pay/blockchain/transactions/transaction"
+	"pandora-pay/config/config_auth"
 	"pandora-pay/helpers"
 	"pandora-pay/helpers/advanced_buffers"
 	"pandora-pay/network/api/api_common/api_types"
@@ -23,11 +24,7 @@ type APIWalletDecryptTxReply struct {
 	Confirmations uint64              `json:"confirmations" msgpack:"confirmations"`
 }
 
-func (api *APICommon) GetWalletDecryptTx(r *http.Request, args *APIWalletDecryptTxRequest, reply *APIWalletDecryptTxReply, authenticated bool) (err error) {
-
-	if !authenticated {
-		return errors.New("Invalid User or Password")
-	}
+func (api *APICommon) GetWalletDecryptTx(r *http.Request, args *APIWalletDecryptTxRequest, reply *APIWalletDecryptTxReply, principal *config_auth.Principal) (err error) {
 
 	publicKey, err := args.GetPublicKey(false)
 	if err != nil {
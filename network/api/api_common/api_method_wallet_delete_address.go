@@ -1,8 +1,8 @@
 package api_common
 
 import (
-	"errors"
 	"net/http"
+	"pandora-pay/config/config_auth"
 	"pandora-pay/network/api/api_common/api_types"
 )
 
@@ -14,11 +14,7 @@ type APIWalletDeleteAddressReply struct {
 	Status bool `json:"status" msgpack:"status"`
 }
 
-func (api *APICommon) GetWalletDeleteAddress(r *http.Request, args *APIWalletDeleteAddressRequest, reply *APIWalletDeleteAddressReply, authenticated bool) error {
-	if !authenticated {
-		return errors.New("Invalid User or Password")
-	}
-
+func (api *APICommon) GetWalletDeleteAddress(r *http.Request, args *APIWalletDeleteAddressRequest, reply *APIWalletDeleteAddressReply, principal *config_auth.Principal) error {
 	publicKey, err := args.GetPublicKey(true)
 	if err != nil {
 		return err
@@ -0,0 +1,39 @@
+package api_common
+
+import (
+	"errors"
+	"net/http"
+	"pandora-pay/config/config_auth"
+	"pandora-pay/network/api/api_common/api_types"
+	"pandora-pay/wallet"
+)
+
+type APIWalletForgingStatsRequest struct {
+	api_types.APIAccountBaseRequest
+}
+
+type APIWalletForgingStatsReply struct {
+	*wallet.WalletForgingStats
+	MissedOpportunities uint64  `json:"missedOpportunities" msgpack:"missedOpportunities"`
+	ExpectedRate        float64 `json:"expectedRate" msgpack:"expectedRate"`
+	ActualRate          float64 `json:"actualRate" msgpack:"actualRate"`
+}
+
+func (api *APICommon) GetWalletForgingStats(r *http.Request, args *APIWalletForgingStatsRequest, reply *APIWalletForgingStatsReply, principal *config_auth.Principal) (err error) {
+
+	publicKey, err := args.GetPublicKey(true)
+	if err != nil {
+		return
+	}
+
+	if api.wallet.GetWalletAddressByPublicKey(publicKey, true) == nil {
+		return errors.New("Address doesn't exist in your wallet")
+	}
+
+	reply.WalletForgingStats = api.wallet.GetForgingStats(publicKey, true)
+	reply.MissedOpportunities = reply.GetMissedOpportunities()
+	reply.ExpectedRate = reply.GetExpectedRate()
+	reply.ActualRate = reply.GetActualRate()
+
+	return
+}
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"pandora-pay/addresses"
 	"pandora-pay/blockchain/data_storage"
+	"pandora-pay/config/config_auth"
 	"pandora-pay/helpers"
 	"pandora-pay/network/api/api_common/api_types"
 	"pandora-pay/store"
@@ -22,11 +23,7 @@ type APIWalletGenerateAddressReply struct {
 	Address string `json:"address" msgpack:"address"`
 }
 
-func (api *APICommon) GetWalletGenerateAddress(r *http.Request, args *APIWalletGenerateAddressRequest, reply *APIWalletGenerateAddressReply, authenticated bool) (err error) {
-
-	if !authenticated {
-		return errors.New("Invalid User or Password")
-	}
+func (api *APICommon) GetWalletGenerateAddress(r *http.Request, args *APIWalletGenerateAddressRequest, reply *APIWalletGenerateAddressReply, principal *config_auth.Principal) (err error) {
 
 	publicKey, err := args.GetPublicKey(true)
 	if err != nil {
@@ -1,8 +1,8 @@
 package api_common
 
 import (
-	"errors"
 	"net/http"
+	"pandora-pay/config/config_auth"
 	"pandora-pay/helpers/generics"
 	"pandora-pay/wallet"
 	"pandora-pay/wallet/wallet_address"
@@ -14,11 +14,7 @@ type APIWalletGetAccountsReply struct {
 	Addresses []*wallet_address.WalletAddress `json:"addresses" msgpack:"addresses"`
 }
 
-func (api *APICommon) GetWalletAddresses(r *http.Request, args *struct{}, reply *APIWalletGetAccountsReply, authenticated bool) (err error) {
-
-	if !authenticated {
-		return errors.New("Invalid User or Password")
-	}
+func (api *APICommon) GetWalletAddresses(r *http.Request, args *struct{}, reply *APIWalletGetAccountsReply, principal *config_auth.Principal) (err error) {
 
 	api.wallet.Lock.RLock()
 	defer api.wallet.Lock.RUnlock()
@@ -26,11 +22,22 @@ func (api *APICommon) GetWalletAddresses(r *http.Request, args *struct{}, reply
 	reply.Version = api.wallet.Version
 	reply.Encrypted = api.wallet.Encryption.Encrypted
 
+	//only admins can export the keys
+	withKeys := principal.HasRole(config_auth.ROLE_ADMIN)
+
 	reply.Addresses = make([]*wallet_address.WalletAddress, len(api.wallet.Addresses))
 	for i, addr := range api.wallet.Addresses {
 		if reply.Addresses[i], err = generics.Clone[*wallet_address.WalletAddress](addr, new(wallet_address.WalletAddress)); err != nil {
 			return
 		}
+		if !withKeys {
+			reply.Addresses[i].SecretKey = nil
+			reply.Addresses[i].PrivateKey = nil
+			reply.Addresses[i].SpendPrivateKey = nil
+			if reply.Addresses[i].SharedStaked != nil {
+				reply.Addresses[i].SharedStaked.PrivateKey = nil
+			}
+		}
 	}
 
 	return
@@ -0,0 +1,48 @@
+package api_common
+
+import (
+	"github.com/stretchr/testify/assert"
+	"pandora-pay/addresses"
+	"pandora-pay/config/config_auth"
+	"pandora-pay/helpers"
+	"pandora-pay/wallet"
+	"pandora-pay/wallet/wallet_address"
+	"pandora-pay/wallet/wallet_address/shared_staked"
+	"testing"
+)
+
+func TestGetWalletAddressesKeys(t *testing.T) {
+
+	privateKey := addresses.GenerateNewPrivateKey()
+
+	api := &APICommon{wallet: &wallet.Wallet{
+		Encryption: &wallet.WalletEncryption{},
+		Addresses: []*wallet_address.WalletAddress{{
+			Name:            "addr",
+			SecretKey:       helpers.RandomBytes(32),
+			PrivateKey:      privateKey,
+			SpendPrivateKey: addresses.GenerateNewPrivateKey(),
+			PublicKey:       privateKey.GeneratePublicKey(),
+			IsSharedStaked:  true,
+			SharedStaked:    &shared_staked.WalletAddressSharedStaked{PrivateKey: privateKey, PublicKey: privateKey.GeneratePublicKey()},
+		}},
+	}}
+
+	reply := &APIWalletGetAccountsReply{}
+	assert.Nil(t, api.GetWalletAddresses(nil, &struct{}{}, reply, &config_auth.Principal{Username: "reader", Roles: []config_auth.Role{config_auth.ROLE_READ_ONLY}}))
+	assert.Len(t, reply.Addresses, 1)
+
+	addr := reply.Addresses[0]
+	assert.Equal(t, "addr", addr.Name)
+	assert.Equal(t, privateKey.GeneratePublicKey(), addr.PublicKey)
+	assert.Nil(t, addr.SecretKey, "a read-only principal can't export the keys")
+	assert.Nil(t, addr.PrivateKey)
+	assert.Nil(t, addr.SpendPrivateKey)
+	assert.Nil(t, addr.SharedStaked.PrivateKey)
+	assert.NotNil(t, api.wallet.Addresses[0].PrivateKey, "the wallet is not altered")
+
+	reply = &APIWalletGetAccountsReply{}
+	assert.Nil(t, api.GetWalletAddresses(nil, &struct{}{}, reply, &config_auth.Principal{Username: "admin", Roles: []config_auth.Role{config_auth.ROLE_ADMIN}}))
+	assert.Equal(t, privateKey.Key, reply.Addresses[0].PrivateKey.Key)
+	assert.NotNil(t, reply.Addresses[0].SecretKey)
+}
@@ -8,6 +8,7 @@ import (
 	"pandora-pay/blockchain/data_storage/accounts"
 	"pandora-pay/blockchain/data_storage/accounts/account"
 	"pandora-pay/blockchain/data_storage/plain_accounts/plain_account"
+	"pandora-pay/config/config_auth"
 	"pandora-pay/network/api/api_common/api_types"
 	"pandora-pay/store"
 	"pandora-pay/store/store_db/store_db_interface"
@@ -34,11 +35,7 @@ type APIWalletGetBalanceDataReply struct {
 	Asset   []byte `json:"asset" msgpack:"asset"`
 }
 
-func (api *APICommon) GetWalletBalances(r *http.Request, args *APIWalletGetBalanceRequest, reply *APIWalletGetBalancesReply, authenticated bool) (err error) {
-
-	if !authenticated {
-		return errors.New("Invalid User or Password")
-	}
+func (api *APICommon) GetWalletBalances(r *http.Request, args *APIWalletGetBalanceRequest, reply *APIWalletGetBalancesReply, principal *config_auth.Principal) (err error) {
 
 	publicKeys := make([][]byte, len(args.List))
 	for i, it := range args.List {
@@ -0,0 +1,53 @@
+package api_common
+
+import (
+	"encoding/binary"
+	"errors"
+	"net/http"
+	"pandora-pay/blockchain/transactions/transaction/transaction_data"
+	"pandora-pay/config/config_auth"
+	"pandora-pay/helpers"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_interface"
+	"pandora-pay/wallet"
+)
+
+type APIWalletPaymentsRequest struct {
+	PaymentID helpers.Base64 `json:"paymentId" msgpack:"paymentId"`
+}
+
+type APIWalletPaymentsReply struct {
+	Payments []*APIWalletPayment `json:"payments" msgpack:"payments"`
+}
+
+type APIWalletPayment struct {
+	*wallet.WalletPayment
+	Confirmations uint64 `json:"confirmations" msgpack:"confirmations"`
+}
+
+func (api *APICommon) GetWalletPayments(r *http.Request, args *APIWalletPaymentsRequest, reply *APIWalletPaymentsReply, principal *config_auth.Principal) (err error) {
+
+	if len(args.PaymentID) != transaction_data.TX_DATA_MESSAGE_PAYMENT_ID_LENGTH {
+		return errors.New("Invalid PaymentID. It must be an 8 byte")
+	}
+
+	var chainHeight uint64
+	if err = store.StoreBlockchain.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+		chainHeight, _ = binary.Uvarint(reader.Get("chainHeight"))
+		return
+	}); err != nil {
+		return
+	}
+
+	payments := api.wallet.GetPayments(args.PaymentID, true)
+
+	reply.Payments = make([]*APIWalletPayment, len(payments))
+	for i, payment := range payments {
+		reply.Payments[i] = &APIWalletPayment{WalletPayment: payment}
+		if chainHeight > payment.BlockHeight {
+			reply.Payments[i].Confirmations = chainHeight - payment.BlockHeight
+		}
+	}
+
+	return
+}
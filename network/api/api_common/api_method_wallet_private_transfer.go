@@ -2,9 +2,9 @@ package api_common
 
 import (
 	"context"
-	"errors"
 	"net/http"
 	"pandora-pay/blockchain/transactions/transaction"
+	"pandora-pay/config/config_auth"
 	"pandora-pay/txs_builder"
 )
 
@@ -18,11 +18,7 @@ type APIWalletPrivateTransferReply struct {
 	Tx     *transaction.Transaction `json:"tx" msgpack:"tx"`
 }
 
-func (api *APICommon) WalletPrivateTransfer(r *http.Request, args *APIWalletPrivateTransferRequest, reply *APIWalletPrivateTransferReply, authenticated bool) (err error) {
-
-	if !authenticated {
-		return errors.New("Invalid User or Password")
-	}
+func (api *APICommon) WalletPrivateTransfer(r *http.Request, args *APIWalletPrivateTransferRequest, reply *APIWalletPrivateTransferReply, principal *config_auth.Principal) (err error) {
 
 	if reply.Tx, err = api.txsBuilder.CreateZetherTx(args.Data, nil, args.Propagate, true, true, false, context.Background(), func(string) {}); err != nil {
 		return
@@ -2,11 +2,11 @@ package api_types
 
 import (
 	"errors"
-	"net/url"
 	"pandora-pay/addresses"
 	"pandora-pay/config/config_auth"
 	"pandora-pay/cryptography"
 	"pandora-pay/helpers"
+	"strings"
 )
 
 type SubscriptionType uint8
@@ -18,6 +18,11 @@ const (
 	SUBSCRIPTION_ASSET
 	SUBSCRIPTION_REGISTRATION
 	SUBSCRIPTION_TRANSACTION
+	SUBSCRIPTION_WALLET_PAYMENT
+	SUBSCRIPTION_DELEGATE
+	SUBSCRIPTION_REORG
+	SUBSCRIPTION_MEMPOOL       //all the txs inserted or removed from the mempool. The key is empty
+	SUBSCRIPTION_MEMPOOL_ASSET //mempool txs that use an asset. The key is the asset
 )
 
 type APIReturnType uint8
@@ -65,26 +70,23 @@ type APIUnsubscriptionRequest struct {
 }
 
 type APIAuthenticated[T any] struct {
-	User string `json:"user" msgpack:"user"`
-	Pass string `json:"pass" msgpack:"pass"`
-	Data *T     `json:"req" msgpack:"req"`
+	User  string `json:"user" msgpack:"user"`
+	Pass  string `json:"pass" msgpack:"pass"`
+	Token string `json:"token,omitempty" msgpack:"token,omitempty"`
+	Data  *T     `json:"req" msgpack:"req"`
 }
 
-func CheckAuthenticated(args url.Values) bool {
-
-	user := config_auth.CONFIG_AUTH_USERS_MAP[args.Get("user")]
-	if user == nil {
-		return false
+//GetBearerToken returns the token from an "Authorization: Bearer token" header
+func GetBearerToken(header string) string {
+	if len(header) > 7 && strings.EqualFold(header[:7], "Bearer ") {
+		return strings.TrimSpace(header[7:])
 	}
-
-	return user.Password == args.Get("pass")
+	return ""
 }
 
-func (authenticated *APIAuthenticated[T]) CheckAuthenticated() bool {
-	user := config_auth.CONFIG_AUTH_USERS_MAP[authenticated.User]
-	if user == nil {
-		return false
+func (authenticated *APIAuthenticated[T]) GetPrincipal() *config_auth.Principal {
+	if authenticated.Token != "" {
+		return config_auth.AuthenticateToken(authenticated.Token)
 	}
-
-	return user.Password == authenticated.Pass
+	return config_auth.Authenticate(authenticated.User, authenticated.Pass)
 }
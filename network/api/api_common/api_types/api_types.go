@@ -1,5 +1,7 @@
 package api_types
 
+import "pandora-pay/blockchain/info"
+
 type APISubscriptionNotification struct {
 	SubscriptionType SubscriptionType `json:"type,omitempty" msgpack:"type,omitempty"`
 	Key              []byte           `json:"key,omitempty" msgpack:"key,omitempty"`
@@ -54,7 +56,36 @@ type APISubscriptionNotificationTxExtraMempool struct {
 	Included bool `json:"included,omitempty" msgpack:"included,omitempty"`
 }
 
+type APISubscriptionNotificationWalletPaymentExtra struct {
+	TxHash         []byte `json:"txHash" msgpack:"txHash"`
+	PayloadIndex   int    `json:"payloadIndex" msgpack:"payloadIndex"`
+	PublicKey      []byte `json:"publicKey" msgpack:"publicKey"`
+	Amount         uint64 `json:"amount" msgpack:"amount"`
+	Asset          []byte `json:"asset" msgpack:"asset"`
+	BlockHeight    uint64 `json:"blockHeight" msgpack:"blockHeight"`
+	BlockTimestamp uint64 `json:"blockTimestamp" msgpack:"blockTimestamp"`
+}
+
+type APISubscriptionNotificationDelegateRevokedExtra struct {
+	Reason      byte   `json:"reason" msgpack:"reason"`
+	BlockHeight uint64 `json:"blockHeight" msgpack:"blockHeight"`
+}
+
+type APISubscriptionNotificationReorgExtra struct {
+	CommonAncestorHeight uint64   `json:"commonAncestorHeight" msgpack:"commonAncestorHeight"`
+	RemovedBlocks        [][]byte `json:"removedBlocks" msgpack:"removedBlocks"`
+	AddedBlocks          [][]byte `json:"addedBlocks" msgpack:"addedBlocks"`
+	OrphanedTxs          [][]byte `json:"orphanedTxs" msgpack:"orphanedTxs"`
+	BlockHeight          uint64   `json:"blockHeight" msgpack:"blockHeight"`
+}
+
 type APISubscriptionNotificationTxExtra struct {
 	Blockchain *APISubscriptionNotificationTxExtraBlockchain `json:"blockchain,omitempty" msgpack:"blockchain,omitempty"`
 	Mempool    *APISubscriptionNotificationTxExtraMempool    `json:"mempool,omitempty" msgpack:"mempool,omitempty"`
 }
+
+type APISubscriptionNotificationMempoolExtra struct {
+	Inserted  bool            `json:"inserted,omitempty" msgpack:"inserted,omitempty"`
+	Included  bool            `json:"included,omitempty" msgpack:"included,omitempty"`
+	TxPreview *info.TxPreview `json:"txPreview" msgpack:"txPreview"`
+}
@@ -0,0 +1,51 @@
+package api_webhooks
+
+import (
+	"net/http"
+	"pandora-pay/config/config_auth"
+	"pandora-pay/helpers"
+	"pandora-pay/network/api/api_common/api_types"
+)
+
+type APIWebhooksReply struct {
+	Webhooks []*Webhook `json:"webhooks" msgpack:"webhooks"`
+}
+
+type APIWebhookSubscribeRequest struct {
+	URL        string                     `json:"url" msgpack:"url"`
+	Secret     string                     `json:"secret,omitempty" msgpack:"secret,omitempty"` //generated when it is missing
+	Type       api_types.SubscriptionType `json:"type,omitempty" msgpack:"type,omitempty"`
+	Key        helpers.Base64             `json:"key,omitempty" msgpack:"key,omitempty"`
+	ReturnType api_types.APIReturnType    `json:"returnType,omitempty" msgpack:"returnType,omitempty"`
+}
+
+type APIWebhookSubscribeReply struct {
+	Webhook *Webhook `json:"webhook" msgpack:"webhook"`
+	Secret  string   `json:"secret" msgpack:"secret"` //returned only once
+}
+
+type APIWebhookUnsubscribeRequest struct {
+	Id string `json:"id" msgpack:"id"`
+}
+
+type APIWebhookUnsubscribeReply struct {
+	Result bool `json:"result" msgpack:"result"`
+}
+
+func (webhooks *Webhooks) GetWebhooks(r *http.Request, args *struct{}, reply *APIWebhooksReply, principal *config_auth.Principal) (err error) {
+	reply.Webhooks = webhooks.getWebhooks(principal)
+	return
+}
+
+func (webhooks *Webhooks) WebhookSubscribe(r *http.Request, args *APIWebhookSubscribeRequest, reply *APIWebhookSubscribeReply, principal *config_auth.Principal) (err error) {
+	reply.Webhook, reply.Secret, err = webhooks.subscribe(principal, args)
+	return
+}
+
+func (webhooks *Webhooks) WebhookUnsubscribe(r *http.Request, args *APIWebhookUnsubscribeRequest, reply *APIWebhookUnsubscribeReply, principal *config_auth.Principal) (err error) {
+	if err = webhooks.unsubscribe(principal, args.Id); err != nil {
+		return
+	}
+	reply.Result = true
+	return
+}
@@ -0,0 +1,301 @@
+package api_webhooks
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"pandora-pay/config/config_auth"
+	"pandora-pay/helpers"
+	"pandora-pay/network/api/api_common/api_types"
+	"pandora-pay/network/websocks/connection"
+	"pandora-pay/network/websocks/connection/advanced_connection_types"
+	"pandora-pay/recovery"
+	"sort"
+	"sync"
+	"syscall"
+	"time"
+)
+
+const (
+	WEBHOOKS_MAX_PER_USER        = 100
+	WEBHOOK_MAX_PENDING          = 1000 //the oldest deliveries are dropped when the endpoint doesn't keep up
+	WEBHOOK_MAX_ATTEMPTS         = 10
+	WEBHOOK_TIMEOUT              = 10 * time.Second
+	WEBHOOK_RETRY_BACKOFF        = 5 * time.Second
+	WEBHOOK_RETRY_BACKOFF_MAX    = time.Hour
+	WEBHOOK_DELIVERIES_INTERVAL  = time.Second
+	WEBHOOK_SIGNATURE_HEADER     = "X-Pandora-Signature"
+	WEBHOOK_ID_HEADER            = "X-Pandora-Webhook-Id"
+	WEBHOOK_DELIVERY_ID_HEADER   = "X-Pandora-Delivery-Id"
+	WEBHOOK_SECRET_DEFAULT_BYTES = 32
+)
+
+//Webhook is a subscription whose notifications are POSTed to an URL. Pending are the notifications not delivered yet. They are stored under their own keys, from FirstDeliveryId to NextDeliveryId
+type Webhook struct {
+	Id              string                     `json:"id" msgpack:"id"`
+	Username        string                     `json:"user" msgpack:"user"`
+	URL             string                     `json:"url" msgpack:"url"`
+	Secret          string                     `json:"-" msgpack:"secret"`
+	Type            api_types.SubscriptionType `json:"type" msgpack:"type"`
+	Key             helpers.Base64             `json:"key" msgpack:"key"`
+	ReturnType      api_types.APIReturnType    `json:"returnType" msgpack:"returnType"`
+	CreatedAt       int64                      `json:"createdAt" msgpack:"createdAt"`
+	Delivered       uint64                     `json:"delivered" msgpack:"delivered"`
+	Failed          uint64                     `json:"failed" msgpack:"failed"` //dropped after WEBHOOK_MAX_ATTEMPTS or because the queue was full
+	LastAttemptAt   int64                      `json:"lastAttemptAt,omitempty" msgpack:"lastAttemptAt,omitempty"`
+	LastDeliveryAt  int64                      `json:"lastDeliveryAt,omitempty" msgpack:"lastDeliveryAt,omitempty"`
+	LastError       string                     `json:"lastError,omitempty" msgpack:"lastError,omitempty"`
+	NextDeliveryId  uint64                     `json:"-" msgpack:"nextDeliveryId"`
+	FirstDeliveryId uint64                     `json:"-" msgpack:"firstDeliveryId"`
+	Pending         []*WebhookDelivery         `json:"-" msgpack:"-"`
+	PendingCount    int                        `json:"pending" msgpack:"-"`
+	webhooks        *Webhooks
+	uuid            advanced_connection_types.UUID
+	delivering      bool
+	removed         bool
+	changed         map[uint64]*WebhookDelivery //deliveries not stored yet. nil for the deliveries to be deleted
+	dirty           bool
+	lock            *sync.Mutex
+}
+
+type WebhookDelivery struct {
+	Id            uint64                                 `json:"id" msgpack:"id"`
+	Notification  *api_types.APISubscriptionNotification `json:"notification" msgpack:"notification"`
+	Attempts      int                                    `json:"attempts" msgpack:"attempts"`
+	CreatedAt     int64                                  `json:"createdAt" msgpack:"createdAt"`
+	NextAttemptAt int64                                  `json:"nextAttemptAt" msgpack:"nextAttemptAt"`
+}
+
+type Webhooks struct {
+	list                 map[string]*Webhook
+	newSubscriptionCn    chan<- *connection.SubscriptionNotification
+	removeSubscriptionCn chan<- *connection.SubscriptionNotification
+	client               *http.Client
+	wakeCn               chan struct{}
+	lock                 *sync.Mutex
+}
+
+func (webhook *Webhook) subscription() *connection.SubscriptionNotification {
+	return &connection.SubscriptionNotification{
+		Subscription: &connection.Subscription{Type: webhook.Type, Key: webhook.Key, ReturnType: webhook.ReturnType},
+		Webhook:      webhook,
+		UUID:         webhook.uuid,
+	}
+}
+
+//snapshot copies the webhook without the pending deliveries to be returned by the API
+func (webhook *Webhook) snapshot() *Webhook {
+	webhook.lock.Lock()
+	defer webhook.lock.Unlock()
+
+	clone := *webhook
+	clone.PendingCount = len(webhook.Pending)
+	clone.Pending = nil
+	return &clone
+}
+
+func (webhook *Webhook) canBeAccessedBy(principal *config_auth.Principal) bool {
+	return principal.HasRole(config_auth.ROLE_ADMIN) || principal.Username == webhook.Username
+}
+
+func validateWebhookType(subscriptionType api_types.SubscriptionType) error {
+	switch subscriptionType {
+	case api_types.SUBSCRIPTION_ACCOUNT, api_types.SUBSCRIPTION_ACCOUNT_TRANSACTIONS, api_types.SUBSCRIPTION_ASSET, api_types.SUBSCRIPTION_TRANSACTION:
+		return nil
+	default:
+		return errors.New("Webhooks support only accounts, account transactions, assets and transactions subscriptions")
+	}
+}
+
+var (
+	errWebhookAddress  = errors.New("Webhook URL must not resolve to a loopback, link-local or private address")
+	sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+)
+
+//isWebhookIPAllowed rejects the addresses of the node itself and of its local networks
+func isWebhookIPAllowed(ip net.IP) bool {
+	return !(ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || sharedAddressSpace.Contains(ip))
+}
+
+//validateWebhookURL resolves the host. The addresses are checked again when the deliveries are sent, as the DNS records can change
+func validateWebhookURL(str string) error {
+	u, err := url.Parse(str)
+	if err != nil {
+		return err
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return errors.New("Webhook URL must be an absolute http or https URL")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), WEBHOOK_TIMEOUT)
+	defer cancel()
+
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, u.Hostname())
+	if err != nil {
+		return err
+	}
+	for _, addr := range addrs {
+		if !isWebhookIPAllowed(addr.IP) {
+			return errWebhookAddress
+		}
+	}
+	return nil
+}
+
+//webhookDialControl checks the address that is dialed, after the resolution and for every redirect
+func webhookDialControl(network, address string, conn syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip == nil || !isWebhookIPAllowed(ip) {
+		return errWebhookAddress
+	}
+	return nil
+}
+
+//newWebhooksClient doesn't use the proxy of the environment, so the dialed addresses are the ones of the webhooks
+func newWebhooksClient() *http.Client {
+	return &http.Client{
+		Timeout: WEBHOOK_TIMEOUT,
+		Transport: &http.Transport{
+			DialContext:         (&net.Dialer{Timeout: WEBHOOK_TIMEOUT, Control: webhookDialControl}).DialContext,
+			TLSHandshakeTimeout: WEBHOOK_TIMEOUT,
+		},
+	}
+}
+
+//Start subscribes the stored webhooks and starts the deliveries. The channels are the ones of the websockets subscriptions
+func (webhooks *Webhooks) Start(newSubscriptionCn, removeSubscriptionCn chan<- *connection.SubscriptionNotification) {
+	recovery.SafeGo(func() {
+
+		webhooks.lock.Lock()
+		webhooks.newSubscriptionCn = newSubscriptionCn
+		webhooks.removeSubscriptionCn = removeSubscriptionCn
+		for _, webhook := range webhooks.list {
+			webhooks.newSubscriptionCn <- webhook.subscription()
+		}
+		webhooks.lock.Unlock()
+
+		webhooks.processDeliveries()
+	})
+}
+
+func (webhooks *Webhooks) subscribe(principal *config_auth.Principal, args *APIWebhookSubscribeRequest) (*Webhook, string, error) {
+
+	if err := validateWebhookType(args.Type); err != nil {
+		return nil, "", err
+	}
+	if err := connection.CheckSubscriptionLength(args.Key, args.Type); err != nil {
+		return nil, "", err
+	}
+	if err := validateWebhookURL(args.URL); err != nil {
+		return nil, "", err
+	}
+
+	secret := args.Secret
+	if secret == "" {
+		secret = hex.EncodeToString(helpers.RandomBytes(WEBHOOK_SECRET_DEFAULT_BYTES))
+	}
+
+	webhooks.lock.Lock()
+	defer webhooks.lock.Unlock()
+
+	if webhooks.newSubscriptionCn == nil {
+		return nil, "", errors.New("Webhooks are not started yet")
+	}
+
+	count := 0
+	for _, webhook := range webhooks.list {
+		if webhook.Username == principal.Username {
+			count++
+		}
+	}
+	if count >= WEBHOOKS_MAX_PER_USER {
+		return nil, "", errors.New("Too many webhooks")
+	}
+
+	webhook := &Webhook{
+		Id:              hex.EncodeToString(helpers.RandomBytes(16)),
+		Username:        principal.Username,
+		URL:             args.URL,
+		Secret:          secret,
+		Type:            args.Type,
+		Key:             args.Key,
+		ReturnType:      args.ReturnType,
+		CreatedAt:       time.Now().Unix(),
+		FirstDeliveryId: 1,
+		webhooks:        webhooks,
+		uuid:            connection.NewUUID(),
+		changed:         map[uint64]*WebhookDelivery{},
+		lock:            &sync.Mutex{},
+	}
+
+	if err := webhooks.addWebhook(webhook); err != nil {
+		return nil, "", err
+	}
+
+	webhooks.list[webhook.Id] = webhook
+	webhooks.newSubscriptionCn <- webhook.subscription()
+
+	return webhook.snapshot(), secret, nil
+}
+
+func (webhooks *Webhooks) unsubscribe(principal *config_auth.Principal, id string) error {
+
+	webhooks.lock.Lock()
+	defer webhooks.lock.Unlock()
+
+	webhook := webhooks.list[id]
+	if webhook == nil || !webhook.canBeAccessedBy(principal) {
+		return errors.New("Webhook was not found")
+	}
+
+	webhook.lock.Lock()
+	webhook.removed = true
+	firstDeliveryId, nextDeliveryId := webhook.FirstDeliveryId, webhook.NextDeliveryId
+	webhook.lock.Unlock()
+
+	delete(webhooks.list, id)
+	webhooks.removeSubscriptionCn <- webhook.subscription()
+
+	return webhooks.removeWebhook(id, firstDeliveryId, nextDeliveryId)
+}
+
+func (webhooks *Webhooks) getWebhooks(principal *config_auth.Principal) []*Webhook {
+
+	webhooks.lock.Lock()
+	defer webhooks.lock.Unlock()
+
+	list := make([]*Webhook, 0, len(webhooks.list))
+	for _, webhook := range webhooks.list {
+		if webhook.canBeAccessedBy(principal) {
+			list = append(list, webhook.snapshot())
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].CreatedAt < list[j].CreatedAt
+	})
+	return list
+}
+
+func NewWebhooks() (*Webhooks, error) {
+
+	webhooks := &Webhooks{
+		map[string]*Webhook{},
+		nil,
+		nil,
+		newWebhooksClient(),
+		make(chan struct{}, 1),
+		&sync.Mutex{},
+	}
+
+	if err := webhooks.loadWebhooks(); err != nil {
+		return nil, err
+	}
+
+	return webhooks, nil
+}
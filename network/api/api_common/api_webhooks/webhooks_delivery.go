@@ -0,0 +1,196 @@
+package api_webhooks
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"pandora-pay/gui"
+	"pandora-pay/network/api/api_common/api_types"
+	"pandora-pay/recovery"
+	"strconv"
+	"time"
+)
+
+//WebhookNotification is the body POSTed to the URL of the webhook
+type WebhookNotification struct {
+	WebhookId    string                                 `json:"webhookId"`
+	DeliveryId   uint64                                 `json:"deliveryId"`
+	Timestamp    int64                                  `json:"timestamp"`
+	Attempt      int                                    `json:"attempt"`
+	Notification *api_types.APISubscriptionNotification `json:"notification"`
+}
+
+//SignWebhookBody returns the value of the signature header. It is the HMAC-SHA256 of the body keyed by the secret
+func SignWebhookBody(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+//getRetryBackoff doubles the delay after each failed attempt
+func getRetryBackoff(attempts int) time.Duration {
+	backoff := WEBHOOK_RETRY_BACKOFF
+	for i := 1; i < attempts && backoff < WEBHOOK_RETRY_BACKOFF_MAX; i++ {
+		backoff *= 2
+	}
+	if backoff > WEBHOOK_RETRY_BACKOFF_MAX {
+		backoff = WEBHOOK_RETRY_BACKOFF_MAX
+	}
+	return backoff
+}
+
+//Notify queues the notification. It is called by the websockets subscriptions and it must not block, so the delivery is stored later by processDeliveries
+func (webhook *Webhook) Notify(notification *api_types.APISubscriptionNotification) {
+
+	webhook.lock.Lock()
+	defer webhook.lock.Unlock()
+
+	if webhook.removed {
+		return
+	}
+
+	now := time.Now().Unix()
+
+	webhook.NextDeliveryId += 1
+	delivery := &WebhookDelivery{webhook.NextDeliveryId, notification, 0, now, now}
+	webhook.Pending = append(webhook.Pending, delivery)
+	webhook.changed[delivery.Id] = delivery
+	if len(webhook.Pending) > WEBHOOK_MAX_PENDING {
+		webhook.changed[webhook.Pending[0].Id] = nil
+		webhook.Pending = webhook.Pending[1:]
+		webhook.Failed += 1
+		webhook.LastError = "Queue is full"
+	}
+	webhook.dirty = true
+
+	webhook.webhooks.wake()
+}
+
+func (webhooks *Webhooks) wake() {
+	select {
+	case webhooks.wakeCn <- struct{}{}:
+	default:
+	}
+}
+
+//post sends a delivery. Any status different than 2xx is a failure
+func (webhooks *Webhooks) post(webhookURL, secret string, notification *WebhookNotification) error {
+
+	body, err := json.Marshal(notification)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WEBHOOK_SIGNATURE_HEADER, SignWebhookBody(secret, body))
+	req.Header.Set(WEBHOOK_ID_HEADER, notification.WebhookId)
+	req.Header.Set(WEBHOOK_DELIVERY_ID_HEADER, strconv.FormatUint(notification.DeliveryId, 10))
+
+	resp, err := webhooks.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(ioutil.Discard, io.LimitReader(resp.Body, 4096))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("Webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+//deliver sends the oldest pending delivery of the webhook. The deliveries of a webhook are sent in order
+func (webhooks *Webhooks) deliver(webhook *Webhook) {
+
+	webhook.lock.Lock()
+	delivery := webhook.Pending[0]
+	notification := &WebhookNotification{webhook.Id, delivery.Id, time.Now().Unix(), delivery.Attempts + 1, delivery.Notification}
+	webhookURL, secret := webhook.URL, webhook.Secret
+	webhook.lock.Unlock()
+
+	err := webhooks.post(webhookURL, secret, notification)
+
+	webhook.lock.Lock()
+	defer webhook.lock.Unlock()
+
+	webhook.delivering = false
+	if webhook.removed {
+		return
+	}
+
+	now := time.Now()
+	delivery.Attempts += 1
+	webhook.LastAttemptAt = now.Unix()
+
+	if err == nil {
+		webhook.Delivered += 1
+		webhook.LastDeliveryAt = now.Unix()
+		webhook.LastError = ""
+		webhook.removePending(delivery)
+	} else {
+		webhook.LastError = err.Error()
+		if delivery.Attempts >= WEBHOOK_MAX_ATTEMPTS {
+			webhook.Failed += 1
+			webhook.removePending(delivery)
+		} else {
+			delivery.NextAttemptAt = now.Add(getRetryBackoff(delivery.Attempts)).Unix()
+			if len(webhook.Pending) > 0 && webhook.Pending[0] == delivery { //it was not dropped because the queue was full
+				webhook.changed[delivery.Id] = delivery
+			}
+		}
+	}
+	webhook.dirty = true
+
+	webhooks.wake()
+}
+
+//removePending removes the delivery unless it was already dropped because the queue was full
+func (webhook *Webhook) removePending(delivery *WebhookDelivery) {
+	if len(webhook.Pending) > 0 && webhook.Pending[0] == delivery {
+		webhook.Pending = webhook.Pending[1:]
+		webhook.changed[delivery.Id] = nil
+	}
+}
+
+func (webhooks *Webhooks) processDeliveries() {
+
+	ticker := time.NewTicker(WEBHOOK_DELIVERIES_INTERVAL)
+	defer ticker.Stop()
+
+	for {
+
+		select {
+		case <-ticker.C:
+		case <-webhooks.wakeCn:
+		}
+
+		now := time.Now().Unix()
+
+		webhooks.lock.Lock()
+		if err := webhooks.saveChanges(); err != nil {
+			gui.GUI.Error("Error storing webhooks", err)
+		}
+		for _, webhook := range webhooks.list {
+			webhook.lock.Lock()
+			if !webhook.delivering && len(webhook.Pending) > 0 && webhook.Pending[0].NextAttemptAt <= now {
+				webhook.delivering = true
+				webhookCopy := webhook
+				recovery.SafeGo(func() {
+					webhooks.deliver(webhookCopy)
+				})
+			}
+			webhook.lock.Unlock()
+		}
+		webhooks.lock.Unlock()
+	}
+}
@@ -0,0 +1,182 @@
+package api_webhooks
+
+import (
+	"github.com/vmihailenco/msgpack/v5"
+	"golang.org/x/exp/slices"
+	"pandora-pay/network/websocks/connection"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_interface"
+	"strconv"
+	"sync"
+)
+
+//the ids of the webhooks are kept in a list as the store can't iterate the keys
+const webhooksListKey = "webhooks"
+
+func getWebhookKey(id string) string {
+	return "webhook:" + id
+}
+
+func getWebhookDeliveryKey(id string, deliveryId uint64) string {
+	return "webhook:" + id + ":" + strconv.FormatUint(deliveryId, 10)
+}
+
+func readWebhooksList(reader store_db_interface.StoreDBTransactionInterface) (list []string, err error) {
+	if data := reader.Get(webhooksListKey); data != nil {
+		err = msgpack.Unmarshal(data, &list)
+	}
+	return
+}
+
+func writeWebhooksList(writer store_db_interface.StoreDBTransactionInterface, list []string) error {
+	data, err := msgpack.Marshal(list)
+	if err != nil {
+		return err
+	}
+	writer.Put(webhooksListKey, data)
+	return nil
+}
+
+func (webhooks *Webhooks) loadWebhooks() error {
+	return store.StoreSettings.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) error {
+
+		list, err := readWebhooksList(reader)
+		if err != nil {
+			return err
+		}
+
+		for _, id := range list {
+			data := reader.Get(getWebhookKey(id))
+			if data == nil {
+				continue
+			}
+
+			webhook := &Webhook{}
+			if err = msgpack.Unmarshal(data, webhook); err != nil {
+				return err
+			}
+			webhook.webhooks = webhooks
+			webhook.uuid = connection.NewUUID()
+			webhook.changed = map[uint64]*WebhookDelivery{}
+			webhook.lock = &sync.Mutex{}
+
+			for deliveryId := webhook.FirstDeliveryId; deliveryId <= webhook.NextDeliveryId; deliveryId++ {
+				if data = reader.Get(getWebhookDeliveryKey(id, deliveryId)); data == nil {
+					continue
+				}
+				delivery := &WebhookDelivery{}
+				if err = msgpack.Unmarshal(data, delivery); err != nil {
+					return err
+				}
+				webhook.Pending = append(webhook.Pending, delivery)
+			}
+
+			webhooks.list[webhook.Id] = webhook
+		}
+
+		return nil
+	})
+}
+
+//addWebhook stores a new webhook
+func (webhooks *Webhooks) addWebhook(webhook *Webhook) error {
+
+	data, err := msgpack.Marshal(webhook)
+	if err != nil {
+		return err
+	}
+
+	return store.StoreSettings.DB.Update(func(writer store_db_interface.StoreDBTransactionInterface) error {
+
+		list, err := readWebhooksList(writer)
+		if err != nil {
+			return err
+		}
+		if err = writeWebhooksList(writer, append(list, webhook.Id)); err != nil {
+			return err
+		}
+
+		writer.Put(getWebhookKey(webhook.Id), data)
+		return nil
+	})
+}
+
+//saveChanges stores the counters and the deliveries that changed since the last call, in a single transaction. It must be called with the lock of the webhooks
+func (webhooks *Webhooks) saveChanges() error {
+
+	changes := make(map[string][]byte)
+	var deleted []string
+
+	for _, webhook := range webhooks.list {
+
+		webhook.lock.Lock()
+		if webhook.dirty {
+
+			if len(webhook.Pending) > 0 {
+				webhook.FirstDeliveryId = webhook.Pending[0].Id
+			} else {
+				webhook.FirstDeliveryId = webhook.NextDeliveryId + 1
+			}
+
+			data, err := msgpack.Marshal(webhook)
+			if err != nil {
+				webhook.lock.Unlock()
+				return err
+			}
+			changes[getWebhookKey(webhook.Id)] = data
+
+			for deliveryId, delivery := range webhook.changed {
+				key := getWebhookDeliveryKey(webhook.Id, deliveryId)
+				if delivery == nil {
+					deleted = append(deleted, key)
+					continue
+				}
+				if data, err = msgpack.Marshal(delivery); err != nil {
+					webhook.lock.Unlock()
+					return err
+				}
+				changes[key] = data
+			}
+
+			webhook.changed = map[uint64]*WebhookDelivery{}
+			webhook.dirty = false
+		}
+		webhook.lock.Unlock()
+	}
+
+	if len(changes) == 0 && len(deleted) == 0 {
+		return nil
+	}
+
+	return store.StoreSettings.DB.Update(func(writer store_db_interface.StoreDBTransactionInterface) error {
+		for key, data := range changes {
+			writer.Put(key, data)
+		}
+		for _, key := range deleted {
+			writer.Delete(key)
+		}
+		return nil
+	})
+}
+
+//removeWebhook deletes the webhook and the deliveries that were stored
+func (webhooks *Webhooks) removeWebhook(id string, firstDeliveryId, nextDeliveryId uint64) error {
+	return store.StoreSettings.DB.Update(func(writer store_db_interface.StoreDBTransactionInterface) error {
+
+		list, err := readWebhooksList(writer)
+		if err != nil {
+			return err
+		}
+		if index := slices.Index(list, id); index >= 0 {
+			if err = writeWebhooksList(writer, slices.Delete(list, index, index+1)); err != nil {
+				return err
+			}
+		}
+
+		for deliveryId := firstDeliveryId; deliveryId <= nextDeliveryId; deliveryId++ {
+			writer.Delete(getWebhookDeliveryKey(id, deliveryId))
+		}
+		writer.Delete(getWebhookKey(id))
+		return nil
+	})
+}
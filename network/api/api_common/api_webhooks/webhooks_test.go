@@ -0,0 +1,113 @@
+package api_webhooks
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"pandora-pay/network/api/api_common/api_types"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_interface"
+	"pandora-pay/store/store_db/store_db_memory"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestGetRetryBackoff(t *testing.T) {
+	assert.Equal(t, WEBHOOK_RETRY_BACKOFF, getRetryBackoff(1))
+	assert.Equal(t, 2*WEBHOOK_RETRY_BACKOFF, getRetryBackoff(2))
+	assert.Equal(t, 8*WEBHOOK_RETRY_BACKOFF, getRetryBackoff(4))
+	assert.Equal(t, WEBHOOK_RETRY_BACKOFF_MAX, getRetryBackoff(100))
+}
+
+func TestValidateWebhookURL(t *testing.T) {
+	assert.Nil(t, validateWebhookURL("https://93.184.216.34/hook"))
+	assert.Nil(t, validateWebhookURL("http://[2606:2800:220:1::1]:8080"))
+	assert.NotNil(t, validateWebhookURL("ftp://93.184.216.34"))
+	assert.NotNil(t, validateWebhookURL("/hook"))
+
+	for _, str := range []string{"http://127.0.0.1:8080", "http://localhost", "http://[::1]", "http://10.0.0.1", "http://192.168.1.1", "http://169.254.169.254/latest", "http://[fe80::1]", "http://[fd00::1]", "http://0.0.0.0", "http://100.64.0.1", "http://[::ffff:127.0.0.1]"} {
+		assert.ErrorIs(t, validateWebhookURL(str), errWebhookAddress, str)
+	}
+}
+
+func TestWebhookDialControl(t *testing.T) {
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	webhooks := &Webhooks{client: newWebhooksClient()}
+	notification := &WebhookNotification{WebhookId: "id", DeliveryId: 1, Attempt: 1}
+
+	assert.ErrorIs(t, webhooks.post(server.URL, "secret", notification), errWebhookAddress, "the address is checked when the delivery is sent")
+}
+
+func TestWebhookDeliveriesStore(t *testing.T) {
+
+	db, err := store_db_memory.CreateStoreDBMemory("webhooks")
+	assert.Nil(t, err)
+	store.StoreSettings = &store.Store{Name: "webhooks", Opened: true, DB: db}
+
+	webhooks := &Webhooks{list: map[string]*Webhook{}, wakeCn: make(chan struct{}, 1), lock: &sync.Mutex{}}
+	webhook := &Webhook{Id: "id", Username: "admin", FirstDeliveryId: 1, webhooks: webhooks, changed: map[uint64]*WebhookDelivery{}, lock: &sync.Mutex{}}
+	assert.Nil(t, webhooks.addWebhook(webhook))
+	webhooks.list[webhook.Id] = webhook
+
+	for i := 0; i < WEBHOOK_MAX_PENDING+2; i++ {
+		webhook.Notify(&api_types.APISubscriptionNotification{SubscriptionType: api_types.SUBSCRIPTION_TRANSACTION, Key: []byte{byte(i)}})
+	}
+	webhook.removePending(webhook.Pending[0])
+	assert.Nil(t, webhooks.saveChanges())
+	assert.False(t, webhook.dirty)
+	assert.Equal(t, uint64(4), webhook.FirstDeliveryId)
+
+	loaded := &Webhooks{list: map[string]*Webhook{}}
+	assert.Nil(t, loaded.loadWebhooks())
+	assert.Len(t, loaded.list["id"].Pending, WEBHOOK_MAX_PENDING-1)
+	assert.Equal(t, uint64(4), loaded.list["id"].Pending[0].Id)
+	assert.Equal(t, uint64(2), loaded.list["id"].Failed)
+
+	assert.Nil(t, webhooks.removeWebhook("id", webhook.FirstDeliveryId, webhook.NextDeliveryId))
+	assert.Nil(t, db.View(func(reader store_db_interface.StoreDBTransactionInterface) error {
+		assert.Nil(t, reader.Get(getWebhookKey("id")))
+		assert.Nil(t, reader.Get(getWebhookDeliveryKey("id", webhook.NextDeliveryId)))
+		return nil
+	}))
+}
+
+func TestWebhookPost(t *testing.T) {
+
+	var received *WebhookNotification
+	var signature string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		signature = r.Header.Get(WEBHOOK_SIGNATURE_HEADER)
+		if signature != SignWebhookBody("secret", body) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		received = &WebhookNotification{}
+		_ = json.Unmarshal(body, received)
+		if received.Attempt < 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	webhooks := &Webhooks{client: &http.Client{Timeout: time.Second}}
+
+	notification := &WebhookNotification{"id", 1, time.Now().Unix(), 1, &api_types.APISubscriptionNotification{SubscriptionType: api_types.SUBSCRIPTION_TRANSACTION, Key: []byte{1, 2, 3}}}
+
+	assert.NotNil(t, webhooks.post(server.URL, "secret", notification))
+	assert.NotEmpty(t, signature)
+
+	notification.Attempt = 2
+	assert.Nil(t, webhooks.post(server.URL, "secret", notification))
+	assert.Equal(t, uint64(1), received.DeliveryId)
+	assert.Equal(t, []byte{1, 2, 3}, received.Notification.Key)
+
+	assert.NotNil(t, webhooks.post(server.URL, "wrong", notification))
+}
@@ -2,81 +2,148 @@ package api_http
 
 import (
 	"encoding/json"
-	"io"
 	"net/http"
 	"net/url"
 	"pandora-pay/blockchain"
 	"pandora-pay/blockchain/blockchain_sync"
 	"pandora-pay/blockchain/info"
 	"pandora-pay/config"
+	"pandora-pay/config/config_auth"
+	"pandora-pay/config/config_nodes"
 	"pandora-pay/helpers/urldecoder"
 	"pandora-pay/network/api/api_common"
 	"pandora-pay/network/api/api_common/api_delegator_node"
 	"pandora-pay/network/api/api_common/api_faucet"
 	"pandora-pay/network/api/api_common/api_types"
+	"pandora-pay/network/api/api_common/api_webhooks"
+	"pandora-pay/network/rate_limiter"
+	"reflect"
+	"runtime"
+	"strings"
 )
 
+//APIRoute is a registered route. The request and reply types are kept to generate the OpenAPI specification
+type APIRoute struct {
+	Method        string
+	Request       reflect.Type
+	Reply         reflect.Type
+	Authenticated bool
+	Role          config_auth.Role //required by the authenticated routes. Empty when any principal is accepted
+	Handler       string           //name of the api_common.APICommon method, empty for the other handlers
+	call          func(decode func(args any) error, principal *config_auth.Principal) (interface{}, error)
+	get           func(values url.Values, principal *config_auth.Principal) (interface{}, error)
+	post          func(req *http.Request) (interface{}, error)
+}
+
 type API struct {
-	GetMap    map[string]func(values url.Values) (interface{}, error)
-	PostMap   map[string]func(values io.ReadCloser) (interface{}, error)
+	GetMap    map[string]func(values url.Values, principal *config_auth.Principal) (interface{}, error)
+	PostMap   map[string]func(req *http.Request) (interface{}, error)
+	Routes    map[string]*APIRoute
+	openAPI   *OpenAPI
 	chain     *blockchain.Blockchain
 	apiCommon *api_common.APICommon
 	apiStore  *api_common.APIStore
 }
 
-func handleAuthenticated[T any, B any](callback func(r *http.Request, args *T, reply *B, authenticated bool) error) func(values url.Values) (interface{}, error) {
-	return func(values url.Values) (interface{}, error) {
+//Call runs the route with the arguments filled by decode. It is used by the other transports to share the route table
+func (route *APIRoute) Call(decode func(args any) error, principal *config_auth.Principal) (interface{}, error) {
+	return route.call(decode, principal)
+}
 
-		authenticated := api_types.CheckAuthenticated(values)
-		values.Del("user")
-		values.Del("pass")
+//handlerName returns the name of an api_common.APICommon method value
+func handlerName(callback any) string {
+	name := runtime.FuncForPC(reflect.ValueOf(callback).Pointer()).Name()
+	if !strings.Contains(name, "api_common.(*APICommon).") {
+		return ""
+	}
+	return strings.TrimSuffix(name[strings.LastIndex(name, ".")+1:], "-fm")
+}
+
+func newRoute[T any, B any](method string, authenticated bool, role config_auth.Role, handler string, callback func(r *http.Request, args *T, reply *B, principal *config_auth.Principal) error) *APIRoute {
+	route := &APIRoute{method, reflect.TypeOf((*T)(nil)).Elem(), reflect.TypeOf((*B)(nil)).Elem(), authenticated, role, handler, nil, nil, nil}
+	route.call = func(decode func(args any) error, principal *config_auth.Principal) (interface{}, error) {
+		if role != "" {
+			if err := principal.Authorize(role); err != nil {
+				return nil, err
+			}
+		}
 
 		args := new(T)
-		if err := urldecoder.Decoder.Decode(args, values); err != nil {
+		if err := decode(args); err != nil {
 			return nil, err
 		}
 
 		reply := new(B)
-		return reply, callback(nil, args, reply, authenticated)
+		return reply, callback(nil, args, reply, principal)
 	}
+	return route
 }
 
-func handle[T any, B any](callback func(r *http.Request, args *T, reply *B) error) func(values url.Values) (interface{}, error) {
-	return func(values url.Values) (interface{}, error) {
-		args := new(T)
-		if err := urldecoder.Decoder.Decode(args, values); err != nil {
-			return nil, err
-		}
+func handleAuthenticated[T any, B any](role config_auth.Role, callback func(r *http.Request, args *T, reply *B, principal *config_auth.Principal) error) *APIRoute {
+	route := newRoute[T, B](http.MethodGet, true, role, handlerName(callback), callback)
+	route.get = func(values url.Values, principal *config_auth.Principal) (interface{}, error) {
 
-		reply := new(B)
-		return reply, callback(nil, args, reply)
+		values.Del("user")
+		values.Del("pass")
+		values.Del("token")
+
+		return route.call(func(args any) error {
+			return urldecoder.Decoder.Decode(args, values)
+		}, principal)
+	}
+	return route
+}
+
+func handle[T any, B any](callback func(r *http.Request, args *T, reply *B) error) *APIRoute {
+	route := newRoute[T, B](http.MethodGet, false, "", handlerName(callback), func(r *http.Request, args *T, reply *B, principal *config_auth.Principal) error {
+		return callback(r, args, reply)
+	})
+	route.get = func(values url.Values, principal *config_auth.Principal) (interface{}, error) {
+		return route.call(func(args any) error {
+			return urldecoder.Decoder.Decode(args, values)
+		}, principal)
 	}
+	return route
 }
 
-func handlePOSTAuthenticated[T any, B any](callback func(r *http.Request, args *T, reply *B, authenticated bool) error) func(values io.ReadCloser) (interface{}, error) {
-	return func(values io.ReadCloser) (interface{}, error) {
+//handlePOSTAuthenticated reads the credentials from the body, so the login is rate limited here before the password is verified
+func handlePOSTAuthenticated[T any, B any](rateLimiter *rate_limiter.RateLimiter, role config_auth.Role, callback func(r *http.Request, args *T, reply *B, principal *config_auth.Principal) error) *APIRoute {
+	route := newRoute[T, B](http.MethodPost, true, role, handlerName(callback), callback)
+	route.post = func(req *http.Request) (interface{}, error) {
 
 		authenticated := new(api_types.APIAuthenticated[T])
-		if err := json.NewDecoder(values).Decode(authenticated); err != nil {
+		if err := json.NewDecoder(req.Body).Decode(authenticated); err != nil {
 			return nil, err
 		}
+		if authenticated.Token == "" {
+			authenticated.Token = api_types.GetBearerToken(req.Header.Get("Authorization"))
+		}
+		if authenticated.Token == "" && authenticated.User != "" {
+			if err := rateLimiter.AllowLogin(req.RemoteAddr, authenticated.User, authenticated.Pass); err != nil {
+				return nil, err
+			}
+		}
 
-		reply := new(B)
-		return reply, callback(nil, authenticated.Data, reply, authenticated.CheckAuthenticated())
+		return route.call(func(args any) error {
+			if authenticated.Data != nil {
+				*args.(*T) = *authenticated.Data
+			}
+			return nil
+		}, authenticated.GetPrincipal())
 	}
+	return route
 }
 
-func handlePOST[T any, B any](callback func(r *http.Request, args *T, reply *B) error) func(values io.ReadCloser) (interface{}, error) {
-	return func(values io.ReadCloser) (interface{}, error) {
-		args := new(T)
-
-		if err := json.NewDecoder(values).Decode(args); err != nil {
-			return nil, err
-		}
-
-		reply := new(B)
-		return reply, callback(nil, args, reply)
+func handlePOST[T any, B any](callback func(r *http.Request, args *T, reply *B) error) *APIRoute {
+	route := newRoute[T, B](http.MethodPost, false, "", handlerName(callback), func(r *http.Request, args *T, reply *B, principal *config_auth.Principal) error {
+		return callback(r, args, reply)
+	})
+	route.post = func(req *http.Request) (interface{}, error) {
+		return route.call(func(args any) error {
+			return json.NewDecoder(req.Body).Decode(args)
+		}, nil)
 	}
+	return route
 }
 
 func NewAPI(apiStore *api_common.APIStore, apiCommon *api_common.APICommon, chain *blockchain.Blockchain) *API {
@@ -87,7 +154,7 @@ func NewAPI(apiStore *api_common.APIStore, apiCommon *api_common.APICommon, chai
 		apiCommon: apiCommon,
 	}
 
-	api.GetMap = map[string]func(values url.Values) (interface{}, error){
+	api.Routes = map[string]*APIRoute{
 		"ping":                    handle[struct{}, api_common.APIPingReply](api.apiCommon.GetPing),
 		"":                        handle[struct{}, api_common.APIInfoReply](api.apiCommon.GetInfo),
 		"chain":                   handle[struct{}, api_common.APIBlockchain](api.apiCommon.GetBlockchain),
@@ -109,6 +176,7 @@ func NewAPI(apiStore *api_common.APIStore, apiCommon *api_common.APICommon, chai
 		"accounts/count":          handle[api_common.APIAccountsCountRequest, api_common.APIAccountsCountReply](api.apiCommon.GetAccountsCount),
 		"accounts/keys-by-index":  handle[api_common.APIAccountsKeysByIndexRequest, api_common.APIAccountsKeysByIndexReply](api.apiCommon.GetAccountsKeysByIndex),
 		"accounts/by-keys":        handle[api_common.APIAccountsByKeysRequest, api_common.APIAccountsByKeysReply](api.apiCommon.GetAccountsByKeys),
+		"accounts/state-root":     handle[api_common.APIAccountsStateRootRequest, api_common.APIAccountsStateRootReply](api.apiCommon.GetAccountsStateRoot),
 		"asset":                   handle[api_common.APIAssetRequest, api_common.APIAssetReply](api.apiCommon.GetAsset),
 		"asset/exists":            handle[api_common.APIAssetExistsRequest, api_common.APIAssetExistsReply](api.apiCommon.GetAssetExists),
 		"asset/fee-liquidity":     handle[api_common.APIAssetFeeLiquidityFeeRequest, api_common.APIAssetFeeLiquidityFeeReply](api.apiCommon.GetAssetFeeLiquidity),
@@ -116,38 +184,71 @@ func NewAPI(apiStore *api_common.APIStore, apiCommon *api_common.APICommon, chai
 		"mempool/tx-exists":       handle[api_common.APIMempoolExistsRequest, api_common.APIMempoolExistsReply](api.apiCommon.GetMempoolExists),
 		"mempool/new-tx":          handle[api_common.APIMempoolNewTxRequest, api_common.APIMempoolNewTxReply](api.apiCommon.MempoolNewTx),
 		"network/nodes":           handle[struct{}, api_common.APINetworkNodesReply](api.apiCommon.GetNetworkNodes),
-		"wallet/get-addresses":    handleAuthenticated[struct{}, api_common.APIWalletGetAccountsReply](api.apiCommon.GetWalletAddresses),
-		"wallet/generate-address": handleAuthenticated[api_common.APIWalletGenerateAddressRequest, api_common.APIWalletGenerateAddressReply](api.apiCommon.GetWalletGenerateAddress),
-		"wallet/create-address":   handleAuthenticated[api_common.APIWalletCreateAddressRequest, api_common.APIWalletCreateAddressReply](api.apiCommon.GetWalletCreateAddress),
-		"wallet/delete-address":   handleAuthenticated[api_common.APIWalletDeleteAddressRequest, api_common.APIWalletDeleteAddressReply](api.apiCommon.GetWalletDeleteAddress),
-		"wallet/get-balances":     handleAuthenticated[api_common.APIWalletGetBalanceRequest, api_common.APIWalletGetBalancesReply](api.apiCommon.GetWalletBalances),
-		"wallet/decrypt-tx":       handleAuthenticated[api_common.APIWalletDecryptTxRequest, api_common.APIWalletDecryptTxReply](api.apiCommon.GetWalletDecryptTx),
-	}
-
-	api.PostMap = map[string]func(values io.ReadCloser) (interface{}, error){
-		"wallet/private-transfer": handlePOSTAuthenticated[api_common.APIWalletPrivateTransferRequest, api_common.APIWalletPrivateTransferReply](api.apiCommon.WalletPrivateTransfer),
+		"network/rate-limits":     handle[struct{}, rate_limiter.RateLimiterMetrics](api.apiCommon.GetNetworkRateLimits),
+		"graphql":                 handlePOST[api_common.APIGraphQLRequest, api_common.APIGraphQLReply](api.apiCommon.GraphQL),
+		"graphql/schema":          handle[struct{}, api_common.APIGraphQLSchemaReply](api.apiCommon.GetGraphQLSchema),
+		"wallet/get-addresses":    handleAuthenticated[struct{}, api_common.APIWalletGetAccountsReply](config_auth.ROLE_READ_ONLY, api.apiCommon.GetWalletAddresses),
+		"wallet/generate-address": handleAuthenticated[api_common.APIWalletGenerateAddressRequest, api_common.APIWalletGenerateAddressReply](config_auth.ROLE_WALLET, api.apiCommon.GetWalletGenerateAddress),
+		"wallet/create-address":   handleAuthenticated[api_common.APIWalletCreateAddressRequest, api_common.APIWalletCreateAddressReply](config_auth.ROLE_WALLET, api.apiCommon.GetWalletCreateAddress),
+		"wallet/delete-address":   handleAuthenticated[api_common.APIWalletDeleteAddressRequest, api_common.APIWalletDeleteAddressReply](config_auth.ROLE_WALLET, api.apiCommon.GetWalletDeleteAddress),
+		"wallet/get-balances":     handleAuthenticated[api_common.APIWalletGetBalanceRequest, api_common.APIWalletGetBalancesReply](config_auth.ROLE_READ_ONLY, api.apiCommon.GetWalletBalances),
+		"wallet/decrypt-tx":       handleAuthenticated[api_common.APIWalletDecryptTxRequest, api_common.APIWalletDecryptTxReply](config_auth.ROLE_READ_ONLY, api.apiCommon.GetWalletDecryptTx),
+		"wallet/payments":         handleAuthenticated[api_common.APIWalletPaymentsRequest, api_common.APIWalletPaymentsReply](config_auth.ROLE_READ_ONLY, api.apiCommon.GetWalletPayments),
+		"wallet/forging-stats":    handleAuthenticated[api_common.APIWalletForgingStatsRequest, api_common.APIWalletForgingStatsReply](config_auth.ROLE_READ_ONLY, api.apiCommon.GetWalletForgingStats),
+		"auth/tokens":             handleAuthenticated[struct{}, api_common.APIAuthTokensReply](config_auth.ROLE_ADMIN, api.apiCommon.GetAuthTokens),
+		"auth/token-issue":        handleAuthenticated[api_common.APIAuthTokenIssueRequest, api_common.APIAuthTokenIssueReply](config_auth.ROLE_ADMIN, api.apiCommon.AuthTokenIssue),
+		"auth/token-revoke":       handleAuthenticated[api_common.APIAuthTokenRevokeRequest, api_common.APIAuthTokenRevokeReply](config_auth.ROLE_ADMIN, api.apiCommon.AuthTokenRevoke),
+		"wallet/private-transfer": handlePOSTAuthenticated[api_common.APIWalletPrivateTransferRequest, api_common.APIWalletPrivateTransferReply](api.apiCommon.RateLimiter, config_auth.ROLE_WALLET, api.apiCommon.WalletPrivateTransfer),
+		"wallet/change-password":  handlePOSTAuthenticated[api_common.APIWalletChangePasswordRequest, api_common.APIWalletChangePasswordReply](api.apiCommon.RateLimiter, config_auth.ROLE_ADMIN, api.apiCommon.WalletChangePassword),
 	}
 
 	if config.SEED_WALLET_NODES_INFO {
-		api.GetMap["asset-info"] = handle[api_common.APIAssetInfoRequest, info.AssetInfo](api.apiCommon.GetAssetInfo)
-		api.GetMap["block-info"] = handle[api_common.APIBlockInfoRequest, info.BlockInfo](api.apiCommon.GetBlockInfo)
-		api.GetMap["tx-info"] = handle[api_common.APITransactionInfoRequest, info.TxInfo](api.apiCommon.GetTxInfo)
-		api.GetMap["tx-preview"] = handle[api_common.APITransactionPreviewRequest, api_common.APITransactionPreviewReply](api.apiCommon.GetTxPreview)
-		api.GetMap["account/txs"] = handle[api_common.APIAccountTxsRequest, api_common.APIAccountTxsReply](api.apiCommon.GetAccountTxs)
-		api.GetMap["account/mempool"] = handle[api_common.APIAccountMempoolRequest, api_common.APIAccountMempoolReply](api.apiCommon.GetAccountMempool)
-		api.GetMap["account/mempool-nonce"] = handle[api_common.APIAccountMempoolNonceRequest, api_common.APIAccountMempoolNonceReply](api.apiCommon.GetAccountMempoolNonce)
+		api.Routes["asset-info"] = handle[api_common.APIAssetInfoRequest, info.AssetInfo](api.apiCommon.GetAssetInfo)
+		api.Routes["block-info"] = handle[api_common.APIBlockInfoRequest, info.BlockInfo](api.apiCommon.GetBlockInfo)
+		api.Routes["tx-info"] = handle[api_common.APITransactionInfoRequest, info.TxInfo](api.apiCommon.GetTxInfo)
+		api.Routes["tx-preview"] = handle[api_common.APITransactionPreviewRequest, api_common.APITransactionPreviewReply](api.apiCommon.GetTxPreview)
+		api.Routes["account/txs"] = handle[api_common.APIAccountTxsRequest, api_common.APIAccountTxsReply](api.apiCommon.GetAccountTxs)
+		api.Routes["account/mempool"] = handle[api_common.APIAccountMempoolRequest, api_common.APIAccountMempoolReply](api.apiCommon.GetAccountMempool)
+		api.Routes["account/mempool-nonce"] = handle[api_common.APIAccountMempoolNonceRequest, api_common.APIAccountMempoolNonceReply](api.apiCommon.GetAccountMempoolNonce)
 	}
 
 	if api.apiCommon.Faucet != nil {
-		api.GetMap["faucet/info"] = handle[struct{}, api_faucet.APIFaucetInfo](api.apiCommon.Faucet.GetFaucetInfo)
+		api.Routes["faucet/info"] = handle[struct{}, api_faucet.APIFaucetInfo](api.apiCommon.Faucet.GetFaucetInfo)
 		if config.FAUCET_TESTNET_ENABLED {
-			api.GetMap["faucet/coins"] = handle[api_faucet.APIFaucetCoinsRequest, api_faucet.APIFaucetCoinsReply](api.apiCommon.Faucet.GetFaucetCoins)
+			api.Routes["faucet/coins"] = handle[api_faucet.APIFaucetCoinsRequest, api_faucet.APIFaucetCoinsReply](api.apiCommon.Faucet.GetFaucetCoins)
 		}
 	}
 
 	if api.apiCommon.DelegatorNode != nil {
-		api.GetMap["delegator-node/info"] = handle[struct{}, api_delegator_node.ApiDelegatorNodeInfoReply](api.apiCommon.DelegatorNode.GetDelegatorNodeInfo)
-		api.GetMap["delegator-node/notify"] = handleAuthenticated[api_delegator_node.ApiDelegatorNodeNotifyRequest, api_delegator_node.ApiDelegatorNodeNotifyReply](api.apiCommon.DelegatorNode.DelegatorNotify)
+		delegatorRole := config_auth.Role("")
+		if config_nodes.DELEGATOR_REQUIRE_AUTH {
+			delegatorRole = config_auth.ROLE_DELEGATOR
+		}
+		api.Routes["delegator-node/info"] = handle[struct{}, api_delegator_node.ApiDelegatorNodeInfoReply](api.apiCommon.DelegatorNode.GetDelegatorNodeInfo)
+		api.Routes["delegator-node/notify"] = handleAuthenticated[api_delegator_node.ApiDelegatorNodeNotifyRequest, api_delegator_node.ApiDelegatorNodeNotifyReply](delegatorRole, api.apiCommon.DelegatorNode.DelegatorNotify)
+		api.Routes["delegator/delegate-stats"] = handle[api_delegator_node.ApiDelegatorNodeDelegateStatsRequest, api_delegator_node.ApiDelegatorNodeDelegateStatsReply](api.apiCommon.DelegatorNode.GetDelegateStats)
+		api.Routes["delegator/revoke"] = handleAuthenticated[api_delegator_node.ApiDelegatorNodeRevokeRequest, api_delegator_node.ApiDelegatorNodeRevokeReply](delegatorRole, api.apiCommon.DelegatorNode.DelegatorRevoke)
+	}
+
+	if api.apiCommon.Webhooks != nil {
+		api.Routes["webhooks"] = handleAuthenticated[struct{}, api_webhooks.APIWebhooksReply](config_auth.ROLE_READ_ONLY, api.apiCommon.Webhooks.GetWebhooks)
+		api.Routes["webhook/subscribe"] = handleAuthenticated[api_webhooks.APIWebhookSubscribeRequest, api_webhooks.APIWebhookSubscribeReply](config_auth.ROLE_ADMIN, api.apiCommon.Webhooks.WebhookSubscribe)
+		api.Routes["webhook/unsubscribe"] = handleAuthenticated[api_webhooks.APIWebhookUnsubscribeRequest, api_webhooks.APIWebhookUnsubscribeReply](config_auth.ROLE_READ_ONLY, api.apiCommon.Webhooks.WebhookUnsubscribe)
+	}
+
+	api.GetMap = map[string]func(values url.Values, principal *config_auth.Principal) (interface{}, error){}
+	api.PostMap = map[string]func(req *http.Request) (interface{}, error){}
+	for name, route := range api.Routes {
+		if route.Method == http.MethodPost {
+			api.PostMap[name] = route.post
+		} else {
+			api.GetMap[name] = route.get
+		}
+	}
+
+	api.openAPI = api.generateOpenAPI()
+	api.GetMap["openapi.json"] = func(values url.Values, principal *config_auth.Principal) (interface{}, error) {
+		return api.openAPI, nil
 	}
 
 	return &api
@@ -0,0 +1,289 @@
+package api_http
+
+import (
+	"encoding/json"
+	"net/http"
+	"pandora-pay/config"
+	"pandora-pay/config/config_auth"
+	"pandora-pay/helpers"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+const OPENAPI_VERSION = "3.0.3"
+
+type OpenAPISchema struct {
+	Ref                  string                    `json:"$ref,omitempty"`
+	Type                 string                    `json:"type,omitempty"`
+	Format               string                    `json:"format,omitempty"`
+	Properties           map[string]*OpenAPISchema `json:"properties,omitempty"`
+	Required             []string                  `json:"required,omitempty"`
+	Items                *OpenAPISchema            `json:"items,omitempty"`
+	AdditionalProperties *OpenAPISchema            `json:"additionalProperties,omitempty"`
+}
+
+type OpenAPIParameter struct {
+	Name     string         `json:"name"`
+	In       string         `json:"in"`
+	Required bool           `json:"required,omitempty"`
+	Schema   *OpenAPISchema `json:"schema"`
+}
+
+type OpenAPIMediaType struct {
+	Schema *OpenAPISchema `json:"schema"`
+}
+
+type OpenAPIRequestBody struct {
+	Required bool                         `json:"required"`
+	Content  map[string]*OpenAPIMediaType `json:"content"`
+}
+
+type OpenAPIResponse struct {
+	Description string                       `json:"description"`
+	Content     map[string]*OpenAPIMediaType `json:"content,omitempty"`
+}
+
+type OpenAPIOperation struct {
+	Summary      string                      `json:"summary"`
+	OperationId  string                      `json:"operationId"`
+	Tags         []string                    `json:"tags"`
+	Parameters   []*OpenAPIParameter         `json:"parameters,omitempty"`
+	RequestBody  *OpenAPIRequestBody         `json:"requestBody,omitempty"`
+	Responses    map[string]*OpenAPIResponse `json:"responses"`
+	Security     []map[string][]string       `json:"security,omitempty"`
+	RequiredRole config_auth.Role            `json:"x-required-role,omitempty"`
+}
+
+type OpenAPISecurityScheme struct {
+	Type   string `json:"type"`
+	Scheme string `json:"scheme"`
+}
+
+type OpenAPIComponents struct {
+	Schemas         map[string]*OpenAPISchema         `json:"schemas"`
+	SecuritySchemes map[string]*OpenAPISecurityScheme `json:"securitySchemes"`
+}
+
+type OpenAPIInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type OpenAPI struct {
+	OpenAPI    string                                  `json:"openapi"`
+	Info       *OpenAPIInfo                            `json:"info"`
+	Paths      map[string]map[string]*OpenAPIOperation `json:"paths"`
+	Components *OpenAPIComponents                      `json:"components"`
+}
+
+var (
+	base64Type    = reflect.TypeOf(helpers.Base64{})
+	marshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+//openAPISchemas collects the named structs as components to avoid repeating them and to stop on recursive types
+type openAPISchemas map[string]*OpenAPISchema
+
+func schemaName(t reflect.Type) string {
+	name := path.Base(t.PkgPath()) + "." + t.Name()
+	return strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '.' || r == '-' || r == '_' {
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+func (schemas openAPISchemas) schemaOf(t reflect.Type) *OpenAPISchema {
+
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t == base64Type {
+		return &OpenAPISchema{Type: "string", Format: "byte"}
+	}
+	//types with a custom encoding can't be described by their fields
+	if t.Kind() == reflect.Struct && (t.Implements(marshalerType) || reflect.PtrTo(t).Implements(marshalerType)) {
+		return &OpenAPISchema{}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &OpenAPISchema{Type: "boolean"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16:
+		return &OpenAPISchema{Type: "integer", Format: "int32"}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+		return &OpenAPISchema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &OpenAPISchema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &OpenAPISchema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &OpenAPISchema{Type: "string"}
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &OpenAPISchema{Type: "string", Format: "byte"}
+		}
+		return &OpenAPISchema{Type: "array", Items: schemas.schemaOf(t.Elem())}
+	case reflect.Array:
+		return &OpenAPISchema{Type: "array", Items: schemas.schemaOf(t.Elem())}
+	case reflect.Map:
+		return &OpenAPISchema{Type: "object", AdditionalProperties: schemas.schemaOf(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return schemas.structSchema(t)
+		}
+		name := schemaName(t)
+		if schemas[name] == nil {
+			schemas[name] = &OpenAPISchema{}
+			*schemas[name] = *schemas.structSchema(t)
+		}
+		return &OpenAPISchema{Ref: "#/components/schemas/" + name}
+	default:
+		return &OpenAPISchema{}
+	}
+}
+
+func (schemas openAPISchemas) structSchema(t reflect.Type) *OpenAPISchema {
+	schema := &OpenAPISchema{Type: "object", Properties: map[string]*OpenAPISchema{}}
+	schemas.addFields(schema, t)
+	sort.Strings(schema.Required)
+	return schema
+}
+
+//addFields follows the encoding/json rules: json tags, omitempty and embedded structs
+func (schemas openAPISchemas) addFields(schema *OpenAPISchema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		name, options, _ := strings.Cut(tag, ",")
+
+		fieldType := field.Type
+		for fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+
+		if field.Anonymous && name == "" && fieldType.Kind() == reflect.Struct {
+			schemas.addFields(schema, fieldType)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+		schema.Properties[name] = schemas.schemaOf(field.Type)
+		if !strings.Contains(options, "omitempty") && field.Type.Kind() != reflect.Ptr {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+}
+
+//queryParameters describes the arguments of a GET route. The arguments are decoded from the query string
+func (schemas openAPISchemas) queryParameters(t reflect.Type) []*OpenAPIParameter {
+
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	schema := schemas.structSchema(t)
+
+	names := make([]string, 0, len(schema.Properties))
+	for name := range schema.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	params := make([]*OpenAPIParameter, len(names))
+	for i, name := range names {
+		params[i] = &OpenAPIParameter{name, "query", false, schema.Properties[name]}
+	}
+	return params
+}
+
+func (api *API) generateOpenAPI() *OpenAPI {
+
+	schemas := openAPISchemas{}
+
+	doc := &OpenAPI{
+		OPENAPI_VERSION,
+		&OpenAPIInfo{config.NAME, config.VERSION_STRING},
+		map[string]map[string]*OpenAPIOperation{},
+		&OpenAPIComponents{
+			schemas,
+			map[string]*OpenAPISecurityScheme{
+				"bearer": {"http", "bearer"},
+			},
+		},
+	}
+
+	for name, route := range api.Routes {
+
+		spec := apiRoutesSpecs[name]
+		if spec == nil {
+			spec = &apiRouteSpec{}
+		}
+
+		tag, _, _ := strings.Cut(name, "/")
+		if tag == "" {
+			tag = "info"
+		}
+
+		operation := &OpenAPIOperation{
+			Summary:     spec.Summary,
+			OperationId: route.Method + "/" + name,
+			Tags:        []string{tag},
+			Responses: map[string]*OpenAPIResponse{
+				"200": {"OK", map[string]*OpenAPIMediaType{"application/json": {schemas.schemaOf(route.Reply)}}},
+				"429": {"Rate limit exceeded", nil},
+			},
+		}
+
+		if route.Method == http.MethodPost {
+			body := schemas.schemaOf(route.Request)
+			if route.Authenticated { //the arguments are wrapped in api_types.APIAuthenticated
+				body = &OpenAPISchema{
+					Type: "object",
+					Properties: map[string]*OpenAPISchema{
+						"user":  {Type: "string"},
+						"pass":  {Type: "string"},
+						"token": {Type: "string"},
+						"req":   body,
+					},
+					Required: []string{"req"},
+				}
+			}
+			operation.RequestBody = &OpenAPIRequestBody{true, map[string]*OpenAPIMediaType{"application/json": {body}}}
+			operation.Responses["400"] = &OpenAPIResponse{"Error", nil}
+		} else {
+			operation.Parameters = schemas.queryParameters(route.Request)
+			if route.Authenticated {
+				operation.Parameters = append(operation.Parameters,
+					&OpenAPIParameter{"user", "query", false, &OpenAPISchema{Type: "string"}},
+					&OpenAPIParameter{"pass", "query", false, &OpenAPISchema{Type: "string"}},
+					&OpenAPIParameter{"token", "query", false, &OpenAPISchema{Type: "string"}},
+				)
+			}
+			operation.Responses["500"] = &OpenAPIResponse{"Error", nil}
+		}
+
+		if route.Authenticated {
+			operation.Security = []map[string][]string{{"bearer": {}}}
+			operation.RequiredRole = route.Role
+		}
+
+		doc.Paths["/"+name] = map[string]*OpenAPIOperation{strings.ToLower(route.Method): operation}
+	}
+
+	return doc
+}
@@ -0,0 +1,71 @@
+package api_http
+
+//apiRouteSpec documents a route. The role of the authenticated routes is read from the route
+type apiRouteSpec struct {
+	Summary string
+}
+
+//apiRoutesSpecs must have an entry for every route registered in NewAPI
+var apiRoutesSpecs = map[string]*apiRouteSpec{
+	"ping":                     {"Ping/Pong"},
+	"":                         {"Node Info"},
+	"chain":                    {"Blockchain summary"},
+	"blockchain":               {"Blockchain summary. Alias for chain"},
+	"blockchain/staking-info":  {"Staking amount required at a height"},
+	"blockchain/genesis-info":  {"Genesis block information"},
+	"blockchain/supply":        {"Supply of the native asset"},
+	"blockchain/supply-only":   {"Supply of the native asset as a number"},
+	"sync":                     {"Sync Info"},
+	"block-hash":               {"Block hash from height"},
+	"block/exists":             {"Existence of a Block"},
+	"block":                    {"Block with Txs hashes only"},
+	"block-complete":           {"Block with Txs"},
+	"tx-hash":                  {"Tx hash from height"},
+	"tx":                       {"Transaction"},
+	"tx/exists":                {"Existence of a Transaction"},
+	"tx-raw":                   {"Transaction serialized"},
+	"account":                  {"Account"},
+	"accounts/count":           {"Number of accounts for an asset"},
+	"accounts/keys-by-index":   {"Accounts Keys for an asset specified by a list of indexes"},
+	"accounts/by-keys":         {"Accounts for an asset specified by a list of Accounts Keys"},
+	"accounts/state-root":      {"State root committed by the last block"},
+	"asset":                    {"Asset"},
+	"asset/exists":             {"Existence of an Asset"},
+	"asset/fee-liquidity":      {"Asset Fee Liquidity"},
+	"mempool":                  {"List of Tx Hashes that are in the mempool"},
+	"mempool/tx-exists":        {"Existence of a Tx Hash in the mempool"},
+	"mempool/new-tx":           {"Validate, Include and Broadcast Tx"},
+	"network/nodes":            {"List of peers (50% of most active nodes, 50% of random nodes)"},
+	"network/rate-limits":      {"Rate limiter metrics: tracked clients, allowed and over quota requests"},
+	"graphql":                  {"Read-only GraphQL query over the blocks, txs, assets and accounts"},
+	"graphql/schema":           {"GraphQL schema"},
+	"asset-info":               {"Shorter version of an Asset"},
+	"block-info":               {"Shorter version of a Block"},
+	"tx-info":                  {"Shorter version of a Tx"},
+	"tx-preview":               {"Preview of a Tx"},
+	"account/txs":              {"Account transactions"},
+	"account/mempool":          {"Account pending transactions in mempool"},
+	"account/mempool-nonce":    {"Account new nonce from the mempool"},
+	"faucet/info":              {"Faucet information (hcaptcha)"},
+	"faucet/coins":             {"Get Faucet coins"},
+	"delegator-node/info":      {"Delegator Info"},
+	"delegator-node/notify":    {"Notify the delegator node of a new delegated stake"},
+	"delegator/delegate-stats": {"Blocks forged, rewards and delegator fees of a delegated stake"},
+	"delegator/revoke":         {"Stop staking a delegated stake"},
+	"wallet/get-addresses":     {"Get all wallet accounts"},
+	"wallet/generate-address":  {"Generate an address with an optional payment ID and amount"},
+	"wallet/create-address":    {"Create a new empty address"},
+	"wallet/delete-address":    {"Delete an address from the wallet"},
+	"wallet/get-balances":      {"Get the balances (decrypted) of the requested wallet addresses"},
+	"wallet/decrypt-tx":        {"Decrypt a transaction using wallet"},
+	"wallet/payments":          {"Incoming payments indexed by payment ID"},
+	"wallet/forging-stats":     {"Forging stats of a wallet address"},
+	"auth/tokens":              {"List of API tokens"},
+	"auth/token-issue":         {"Issue an API token with roles and an optional expiration"},
+	"auth/token-revoke":        {"Revoke an API token by id"},
+	"webhooks":                 {"List of webhooks with their delivery state"},
+	"webhook/subscribe":        {"Subscribe a webhook for accounts, account transactions, assets or transactions"},
+	"webhook/unsubscribe":      {"Remove a webhook"},
+	"wallet/private-transfer":  {"Create a private Transfer"},
+	"wallet/change-password":   {"Change the wallet password"},
+}
@@ -0,0 +1,107 @@
+package api_http
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"pandora-pay/config"
+	"pandora-pay/config/config_auth"
+	"pandora-pay/config/config_nodes"
+	"pandora-pay/network/api/api_common"
+	"pandora-pay/network/api/api_common/api_delegator_node"
+	"pandora-pay/network/api/api_common/api_faucet"
+	"pandora-pay/network/api/api_common/api_webhooks"
+	"testing"
+)
+
+//createTestAPI registers all the routes, including the optional ones
+func createTestAPI() *API {
+	config.SEED_WALLET_NODES_INFO = true
+	config.FAUCET_TESTNET_ENABLED = true
+	config_nodes.DELEGATOR_REQUIRE_AUTH = true
+	defer func() {
+		config.SEED_WALLET_NODES_INFO = false
+		config.FAUCET_TESTNET_ENABLED = false
+		config_nodes.DELEGATOR_REQUIRE_AUTH = false
+	}()
+
+	return NewAPI(nil, &api_common.APICommon{Faucet: &api_faucet.Faucet{}, DelegatorNode: &api_delegator_node.DelegatorNode{}, Webhooks: &api_webhooks.Webhooks{}}, nil)
+}
+
+func TestOpenAPIRoutesSpecs(t *testing.T) {
+
+	api := createTestAPI()
+
+	for name, route := range api.Routes {
+		spec := apiRoutesSpecs[name]
+		if !assert.NotNil(t, spec, "route %s is missing in apiRoutesSpecs", name) {
+			continue
+		}
+		assert.NotEmpty(t, spec.Summary, "route %s is missing the summary", name)
+		if route.Authenticated {
+			assert.Nil(t, route.Role.Validate(), "authenticated route %s is missing the role", name)
+		} else {
+			assert.Empty(t, route.Role, "route %s is not authenticated", name)
+		}
+	}
+
+	for name := range apiRoutesSpecs {
+		assert.NotNil(t, api.Routes[name], "spec %s has no route", name)
+	}
+}
+
+func TestRouteAuthorize(t *testing.T) {
+
+	api := createTestAPI()
+	route := api.Routes["auth/tokens"]
+
+	decoded := false
+	decode := func(args any) error {
+		decoded = true
+		return nil
+	}
+
+	_, err := route.Call(decode, nil)
+	assert.ErrorIs(t, err, config_auth.ErrInvalidCredentials)
+
+	_, err = route.Call(decode, &config_auth.Principal{Username: "reader", Roles: []config_auth.Role{config_auth.ROLE_READ_ONLY}})
+	assert.ErrorIs(t, err, config_auth.ErrPermissionDenied)
+	assert.False(t, decoded, "the role is checked before the arguments are decoded")
+}
+
+func TestOpenAPIGenerate(t *testing.T) {
+
+	api := createTestAPI()
+
+	data, err := json.Marshal(api.openAPI)
+	assert.Nil(t, err)
+
+	doc := map[string]any{}
+	assert.Nil(t, json.Unmarshal(data, &doc))
+	assert.Equal(t, OPENAPI_VERSION, doc["openapi"])
+
+	paths := doc["paths"].(map[string]any)
+	assert.Equal(t, len(api.Routes), len(paths))
+
+	block := paths["/block"].(map[string]any)["get"].(map[string]any)
+	params := map[string]bool{}
+	for _, param := range block["parameters"].([]any) {
+		params[param.(map[string]any)["name"].(string)] = true
+	}
+	assert.True(t, params["height"])
+	assert.True(t, params["hash"])
+
+	transfer := paths["/wallet/private-transfer"].(map[string]any)["post"].(map[string]any)
+	assert.Equal(t, string(config_auth.ROLE_WALLET), transfer["x-required-role"])
+	assert.NotNil(t, transfer["security"])
+	assert.NotNil(t, transfer["requestBody"])
+
+	schemas := doc["components"].(map[string]any)["schemas"].(map[string]any)
+	assert.NotNil(t, schemas["api_common.APIBlockReply"])
+
+	for _, path := range paths {
+		for _, operation := range path.(map[string]any) {
+			responses := operation.(map[string]any)["responses"].(map[string]any)
+			assert.NotNil(t, responses["200"])
+		}
+	}
+}
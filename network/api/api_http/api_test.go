@@ -0,0 +1,44 @@
+package api_http
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"pandora-pay/config"
+	"pandora-pay/config/config_auth"
+	"pandora-pay/network/rate_limiter"
+	"strings"
+	"testing"
+)
+
+func TestPOSTAuthenticatedLogin(t *testing.T) {
+
+	hash, err := config_auth.HashPassword("secret")
+	assert.NoError(t, err)
+
+	oldUsers := config_auth.CONFIG_AUTH_USERS_MAP
+	oldExpensive, oldBurst := config.API_RATE_LIMIT_EXPENSIVE, config.API_RATE_LIMIT_BURST
+	defer func() {
+		config_auth.CONFIG_AUTH_USERS_MAP = oldUsers
+		config.API_RATE_LIMIT_EXPENSIVE, config.API_RATE_LIMIT_BURST = oldExpensive, oldBurst
+	}()
+
+	config_auth.CONFIG_AUTH_USERS_MAP = map[string]*config_auth.ConfigAuth{"user": {Username: "user", PasswordHash: hash, Roles: []config_auth.Role{config_auth.ROLE_WALLET}}}
+	config.API_RATE_LIMIT_EXPENSIVE = 1
+	config.API_RATE_LIMIT_BURST = 1
+
+	route := handlePOSTAuthenticated[struct{}, struct{}](rate_limiter.NewRateLimiter(), config_auth.ROLE_WALLET, func(r *http.Request, args *struct{}, reply *struct{}, principal *config_auth.Principal) error {
+		return nil
+	})
+
+	post := func(body string) error {
+		req := httptest.NewRequest(http.MethodPost, "/wallet/private-transfer", strings.NewReader(body))
+		req.RemoteAddr = "10.0.0.1:5000"
+		_, err := route.post(req)
+		return err
+	}
+
+	assert.ErrorIs(t, post(`{"user":"user","pass":"wrong"}`), config_auth.ErrInvalidCredentials)
+	assert.ErrorIs(t, post(`{"user":"user","pass":"secret"}`), rate_limiter.ErrRateLimited, "the password is not verified after the login budget is spent")
+	assert.ErrorIs(t, post(`{"token":"invalid"}`), config_auth.ErrInvalidCredentials, "the tokens are not verified with argon2")
+}
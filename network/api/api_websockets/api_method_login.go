@@ -9,10 +9,12 @@ import (
 type APILogin struct {
 	Username string `json:"user" msgpack:"user"`
 	Password string `json:"pass" msgpack:"pass"`
+	Token    string `json:"token,omitempty" msgpack:"token,omitempty"`
 }
 
 type APILoginReply struct {
-	Status bool `json:"status" msgpack:"status"`
+	Status bool               `json:"status" msgpack:"status"`
+	Roles  []config_auth.Role `json:"roles,omitempty" msgpack:"roles,omitempty"`
 }
 
 func (api *APIWebsockets) login(conn *connection.AdvancedConnection, values []byte) (interface{}, error) {
@@ -22,13 +24,23 @@ func (api *APIWebsockets) login(conn *connection.AdvancedConnection, values []by
 	}
 	reply := &APILoginReply{}
 
-	user := config_auth.CONFIG_AUTH_USERS_MAP[args.Username]
-	if user == nil || user.Password != args.Password {
+	var principal *config_auth.Principal
+	if args.Token != "" {
+		principal = config_auth.AuthenticateToken(args.Token)
+	} else {
+		if err := api.apiCommon.RateLimiter.AllowLogin(conn.RemoteAddr, args.Username, args.Password); err != nil {
+			return nil, err
+		}
+		principal = config_auth.Authenticate(args.Username, args.Password)
+	}
+
+	if principal == nil {
 		return reply, nil
 	}
 
-	conn.Authenticated.Set()
+	conn.Principal.Store(principal)
 	reply.Status = true
+	reply.Roles = principal.Roles
 
 	return reply, nil
 }
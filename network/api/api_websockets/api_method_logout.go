@@ -12,11 +12,11 @@ func (api *APIWebsockets) logout(conn *connection.AdvancedConnection, values []b
 
 	reply := &APILogoutReply{}
 
-	if conn.Authenticated.IsNotSet() {
+	if conn.Principal.Load() == nil {
 		return reply, nil
 	}
 
-	conn.Authenticated.UnSet()
+	conn.Principal.Store(nil)
 	reply.Status = true
 
 	return reply, nil
@@ -13,7 +13,9 @@ import (
 	"pandora-pay/network/api/api_common/api_delegator_node"
 	"pandora-pay/network/api/api_common/api_faucet"
 	"pandora-pay/network/api/api_common/api_types"
+	"pandora-pay/network/api/api_http"
 	"pandora-pay/network/api/api_websockets/consensus"
+	"pandora-pay/network/rate_limiter"
 	"pandora-pay/network/websocks/connection"
 	"pandora-pay/settings"
 	"pandora-pay/txs_validator"
@@ -30,15 +32,12 @@ type APIWebsockets struct {
 	SubscriptionNotifications *multicast.MulticastChannel[*api_types.APISubscriptionNotification]
 }
 
-func handleAuthenticated[T any, B any](callback func(r *http.Request, args *T, reply *B, authenticated bool) error) func(conn *connection.AdvancedConnection, values []byte) (interface{}, error) {
+//handleRoute serves a route of the HTTP API. The route checks the role of the principal
+func handleRoute(route *api_http.APIRoute) func(conn *connection.AdvancedConnection, values []byte) (interface{}, error) {
 	return func(conn *connection.AdvancedConnection, values []byte) (interface{}, error) {
-		args := new(T)
-		if err := msgpack.Unmarshal(values, args); err != nil {
-			return nil, err
-		}
-
-		reply := new(B)
-		return reply, callback(nil, args, reply, conn.Authenticated.IsSet())
+		return route.Call(func(args any) error {
+			return msgpack.Unmarshal(values, args)
+		}, conn.Principal.Load())
 	}
 }
 
@@ -54,7 +53,20 @@ func handle[T any, B any](callback func(r *http.Request, args *T, reply *B) erro
 	}
 }
 
-func NewWebsocketsAPI(apiStore *api_common.APIStore, apiCommon *api_common.APICommon, chain *blockchain.Blockchain, settings *settings.Settings, mempool *mempool.Mempool, txsValidator *txs_validator.TxsValidator) *APIWebsockets {
+//rateLimited consumes the budget of the client before calling the route. The known nodes this node connected to are exempted from the cheap budget as they are used to sync. The consensus of the handshake is declared by the client, so it can't be trusted
+func (api *APIWebsockets) rateLimited(route string, callback func(conn *connection.AdvancedConnection, values []byte) (interface{}, error)) func(conn *connection.AdvancedConnection, values []byte) (interface{}, error) {
+	class := rate_limiter.GetClass(route)
+	return func(conn *connection.AdvancedConnection, values []byte) (interface{}, error) {
+		if class != rate_limiter.RATE_LIMIT_CHEAP || conn.ConnectionType || conn.KnownNode == nil {
+			if err := api.apiCommon.RateLimiter.Allow(rate_limiter.GetClientKey(conn.RemoteAddr, conn.GetUsername()), class); err != nil {
+				return nil, err
+			}
+		}
+		return callback(conn, values)
+	}
+}
+
+func NewWebsocketsAPI(apiStore *api_common.APIStore, apiCommon *api_common.APICommon, chain *blockchain.Blockchain, settings *settings.Settings, mempool *mempool.Mempool, txsValidator *txs_validator.TxsValidator, routes map[string]*api_http.APIRoute) *APIWebsockets {
 
 	api := &APIWebsockets{
 		nil,
@@ -89,6 +101,7 @@ func NewWebsocketsAPI(apiStore *api_common.APIStore, apiCommon *api_common.APICo
 		"accounts/count":          handle[api_common.APIAccountsCountRequest, api_common.APIAccountsCountReply](api.apiCommon.GetAccountsCount),
 		"accounts/keys-by-index":  handle[api_common.APIAccountsKeysByIndexRequest, api_common.APIAccountsKeysByIndexReply](api.apiCommon.GetAccountsKeysByIndex),
 		"accounts/by-keys":        handle[api_common.APIAccountsByKeysRequest, api_common.APIAccountsByKeysReply](api.apiCommon.GetAccountsByKeys),
+		"accounts/state-root":     handle[api_common.APIAccountsStateRootRequest, api_common.APIAccountsStateRootReply](api.apiCommon.GetAccountsStateRoot),
 		"asset":                   handle[api_common.APIAssetRequest, api_common.APIAssetReply](api.apiCommon.GetAsset),
 		"asset/exists":            handle[api_common.APIAssetRequest, api_common.APIAssetReply](api.apiCommon.GetAsset),
 		"asset/fee-liquidity":     handle[api_common.APIAssetFeeLiquidityFeeRequest, api_common.APIAssetFeeLiquidityFeeReply](api.apiCommon.GetAssetFeeLiquidity),
@@ -96,13 +109,9 @@ func NewWebsocketsAPI(apiStore *api_common.APIStore, apiCommon *api_common.APICo
 		"mempool/tx-exists":       handle[api_common.APIMempoolExistsRequest, api_common.APIMempoolExistsReply](api.apiCommon.GetMempoolExists),
 		"mempool/new-tx":          handle[api_common.APIMempoolNewTxRequest, api_common.APIMempoolNewTxReply](api.apiCommon.MempoolNewTx),
 		"network/nodes":           handle[struct{}, api_common.APINetworkNodesReply](api.apiCommon.GetNetworkNodes),
-		"wallet/get-addresses":    handleAuthenticated[struct{}, api_common.APIWalletGetAccountsReply](api.apiCommon.GetWalletAddresses),
-		"wallet/generate-address": handleAuthenticated[api_common.APIWalletGenerateAddressRequest, api_common.APIWalletGenerateAddressReply](api.apiCommon.GetWalletGenerateAddress),
-		"wallet/create-address":   handleAuthenticated[api_common.APIWalletCreateAddressRequest, api_common.APIWalletCreateAddressReply](api.apiCommon.GetWalletCreateAddress),
-		"wallet/delete-address":   handleAuthenticated[api_common.APIWalletDeleteAddressRequest, api_common.APIWalletDeleteAddressReply](api.apiCommon.GetWalletDeleteAddress),
-		"wallet/get-balances":     handleAuthenticated[api_common.APIWalletGetBalanceRequest, api_common.APIWalletGetBalancesReply](api.apiCommon.GetWalletBalances),
-		"wallet/decrypt-tx":       handleAuthenticated[api_common.APIWalletDecryptTxRequest, api_common.APIWalletDecryptTxReply](api.apiCommon.GetWalletDecryptTx),
-		"wallet/private-transfer": handleAuthenticated[api_common.APIWalletPrivateTransferRequest, api_common.APIWalletPrivateTransferReply](api.apiCommon.WalletPrivateTransfer),
+		"network/rate-limits":     handle[struct{}, rate_limiter.RateLimiterMetrics](api.apiCommon.GetNetworkRateLimits),
+		"graphql":                 handle[api_common.APIGraphQLRequest, api_common.APIGraphQLReply](api.apiCommon.GraphQL),
+		"graphql/schema":          handle[struct{}, api_common.APIGraphQLSchemaReply](api.apiCommon.GetGraphQLSchema),
 		//below are ONLY websockets API
 		"block-miss-txs":    handle[consensus.APIBlockCompleteMissingTxsRequest, consensus.APIBlockCompleteMissingTxsReply](api.Consensus.GetBlockCompleteMissingTxs),
 		"handshake":         api.handshake,
@@ -129,6 +138,10 @@ func NewWebsocketsAPI(apiStore *api_common.APIStore, apiCommon *api_common.APICo
 		api.GetMap["sub/notify"] = api.subscribedNotificationReceived
 	}
 
+	if config.CONSENSUS == config.CONSENSUS_TYPE_FULL {
+		api.GetMap["network/nodes-push"] = api.apiCommon.NetworkNodesPush
+	}
+
 	if api.apiCommon.Faucet != nil {
 		api.GetMap["faucet/info"] = handle[struct{}, api_faucet.APIFaucetInfo](api.apiCommon.Faucet.GetFaucetInfo)
 		if config.FAUCET_TESTNET_ENABLED {
@@ -138,7 +151,18 @@ func NewWebsocketsAPI(apiStore *api_common.APIStore, apiCommon *api_common.APICo
 
 	if api.apiCommon.DelegatorNode != nil {
 		api.GetMap["delegator-node/info"] = handle[struct{}, api_delegator_node.ApiDelegatorNodeInfoReply](api.apiCommon.DelegatorNode.GetDelegatorNodeInfo)
-		api.GetMap["delegator-node/notify"] = handleAuthenticated[api_delegator_node.ApiDelegatorNodeNotifyRequest, api_delegator_node.ApiDelegatorNodeNotifyReply](api.apiCommon.DelegatorNode.DelegatorNotify)
+		api.GetMap["delegator/delegate-stats"] = handle[api_delegator_node.ApiDelegatorNodeDelegateStatsRequest, api_delegator_node.ApiDelegatorNodeDelegateStatsReply](api.apiCommon.DelegatorNode.GetDelegateStats)
+	}
+
+	//the authenticated routes are declared once, by the HTTP API
+	for name, route := range routes {
+		if route.Authenticated {
+			api.GetMap[name] = handleRoute(route)
+		}
+	}
+
+	for route, callback := range api.GetMap {
+		api.GetMap[route] = api.rateLimited(route, callback)
 	}
 
 	return api
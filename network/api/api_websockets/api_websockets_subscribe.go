@@ -2,6 +2,7 @@ package api_websockets
 
 import (
 	"github.com/vmihailenco/msgpack/v5"
+	"pandora-pay/config/config_auth"
 	"pandora-pay/network/api/api_common/api_types"
 	"pandora-pay/network/websocks/connection"
 )
@@ -13,6 +14,12 @@ func (api *APIWebsockets) subscribe(conn *connection.AdvancedConnection, values
 		return nil, err
 	}
 
+	if request.Type == api_types.SUBSCRIPTION_WALLET_PAYMENT {
+		if err := conn.Principal.Load().Authorize(config_auth.ROLE_READ_ONLY); err != nil {
+			return nil, err
+		}
+	}
+
 	return nil, conn.Subscriptions.AddSubscription(request.Type, request.Key, request.ReturnType)
 }
 
@@ -0,0 +1,48 @@
+package api_websockets
+
+import (
+	"github.com/stretchr/testify/assert"
+	"pandora-pay/config"
+	"pandora-pay/config/config_auth"
+	"pandora-pay/helpers/generics"
+	"pandora-pay/network/api/api_common"
+	"pandora-pay/network/known_nodes/known_node"
+	"pandora-pay/network/rate_limiter"
+	"pandora-pay/network/websocks/connection"
+	"testing"
+)
+
+func TestRateLimitedExemption(t *testing.T) {
+
+	rate, burst := config.API_RATE_LIMIT_CHEAP, config.API_RATE_LIMIT_BURST
+	config.API_RATE_LIMIT_CHEAP, config.API_RATE_LIMIT_BURST = 1, 1
+	defer func() {
+		config.API_RATE_LIMIT_CHEAP, config.API_RATE_LIMIT_BURST = rate, burst
+	}()
+
+	api := &APIWebsockets{apiCommon: &api_common.APICommon{RateLimiter: rate_limiter.NewRateLimiter()}}
+	route := api.rateLimited("ping", func(conn *connection.AdvancedConnection, values []byte) (interface{}, error) {
+		return nil, nil
+	})
+
+	incoming := &connection.AdvancedConnection{
+		Principal:      &generics.Value[*config_auth.Principal]{},
+		Handshake:      &connection.ConnectionHandshake{Consensus: config.CONSENSUS_TYPE_FULL},
+		RemoteAddr:     "10.0.0.1:5000",
+		ConnectionType: true,
+	}
+	_, err := route(incoming, nil)
+	assert.Nil(t, err)
+	_, err = route(incoming, nil)
+	assert.ErrorIs(t, err, rate_limiter.ErrRateLimited, "a client declaring a full consensus is limited")
+
+	outgoing := &connection.AdvancedConnection{
+		Principal:  &generics.Value[*config_auth.Principal]{},
+		KnownNode:  &known_node.KnownNodeScored{KnownNode: known_node.KnownNode{URL: "ws://10.0.0.2:5000/ws"}},
+		RemoteAddr: "ws://10.0.0.2:5000/ws",
+	}
+	for i := 0; i < 3; i++ {
+		_, err = route(outgoing, nil)
+		assert.Nil(t, err, "the known nodes this node connected to are not limited")
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"pandora-pay/blockchain/blocks/block_complete"
 	"pandora-pay/blockchain/transactions/transaction"
 	"pandora-pay/config"
+	"pandora-pay/config/config_checkpoints"
 	"pandora-pay/config/globals"
 	"pandora-pay/cryptography"
 	"pandora-pay/gui"
@@ -167,11 +168,19 @@ func (thread *ConsensusProcessForksThread) downloadFork(fork *Fork) bool {
 			continue
 		}
 
+		if config_checkpoints.VerifyBlockHash(start-1, hash) != nil { //the peer is following a chain that contradicts the checkpoints
+			return false
+		}
+
 		chainHash, err := thread.chain.OpenLoadBlockHash(start - 1)
 		if err == nil && bytes.Equal(hash, chainHash) {
 			break
 		}
 
+		if config_checkpoints.ValidateReorg(chainData.Height, start-1) != nil {
+			return false
+		}
+
 		blkComplete, err := thread.downloadBlockComplete(conn, fork, start-1)
 		if err != nil {
 			fork.errors += 1
@@ -225,6 +234,10 @@ func (thread *ConsensusProcessForksThread) downloadRemainingBlocks(fork *Fork) b
 			continue
 		}
 
+		if config_checkpoints.VerifyBlockHash(fork.Current, blkComplete.Bloom.Hash) != nil {
+			return false
+		}
+
 		fork.Blocks.Push(blkComplete)
 		fork.Current += 1
 
@@ -0,0 +1,62 @@
+package known_node
+
+import (
+	"errors"
+	"net"
+	"net/url"
+	"pandora-pay/config"
+	"strconv"
+	"strings"
+)
+
+const KNOWN_NODE_URL_MAX_LENGTH = 256
+
+//ValidateURL checks that the url is a public websocket address of a node and returns it normalized. Local addresses are accepted only on devnet
+func ValidateURL(urlStr string) (string, error) {
+
+	if len(urlStr) == 0 || len(urlStr) > KNOWN_NODE_URL_MAX_LENGTH {
+		return "", errors.New("Invalid url length")
+	}
+
+	u, err := url.Parse(urlStr)
+	if err != nil {
+		return "", err
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	if u.Scheme != "ws" && u.Scheme != "wss" {
+		return "", errors.New("Scheme must be ws or wss")
+	}
+	if u.Opaque != "" || u.User != nil || u.RawQuery != "" || u.Fragment != "" || u.Path != "/ws" {
+		return "", errors.New("Url must be scheme://host:port/ws")
+	}
+
+	host := strings.ToLower(u.Hostname())
+	if host == "" {
+		return "", errors.New("Host is missing")
+	}
+
+	if port := u.Port(); port != "" {
+		number, err := strconv.Atoi(port)
+		if err != nil || number <= 0 || number > 65535 {
+			return "", errors.New("Invalid port")
+		}
+		u.Host = net.JoinHostPort(host, strconv.Itoa(number))
+	} else {
+		u.Host = host
+		if strings.Contains(host, ":") {
+			u.Host = "[" + host + "]"
+		}
+	}
+
+	if config.NETWORK_SELECTED != config.DEV_NET_NETWORK_BYTE {
+		if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+			return "", errors.New("Local addresses are not accepted")
+		}
+		if ip := net.ParseIP(host); ip != nil && (ip.IsLoopback() || ip.IsUnspecified() || ip.IsPrivate() || ip.IsMulticast() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast()) {
+			return "", errors.New("Local addresses are not accepted")
+		}
+	}
+
+	return u.String(), nil
+}
@@ -1,27 +1,42 @@
 package known_nodes
 
 import (
+	"context"
 	"errors"
 	"math/rand"
+	"net"
+	"net/url"
 	"pandora-pay/config"
 	"pandora-pay/helpers/generics"
 	"pandora-pay/network/banned_nodes"
 	"pandora-pay/network/connected_nodes"
 	"pandora-pay/network/known_nodes/known_node"
+	"pandora-pay/recovery"
 	"pandora-pay/store/min_max_heap"
+	"strings"
 	"sync"
 	"sync/atomic"
+	"time"
 )
 
+//knownNodesBudget counts the new nodes added by a peer in the current window
+type knownNodesBudget struct {
+	windowStart time.Time
+	added       int
+	sync.Mutex
+}
+
 type KnownNodes struct {
 	connectedNodes                *connected_nodes.ConnectedNodes
 	bannedNodes                   *banned_nodes.BannedNodes
 	knownMap                      *generics.Map[string, *known_node.KnownNodeScored]
 	knownList                     []*known_node.KnownNodeScored //contains all known peers
+	knownHosts                    map[string]int                //number of known peers of every host. Uses knownListMutex
 	knownListMutex                sync.RWMutex
 	knownNotConnectedMaxHeap      *min_max_heap.HeapMemory //contains known peers that we are not connected
 	knownNotConnectedMaxHeapMutex sync.RWMutex
 	knownCount                    int32 //atomic required
+	peersBudgets                  *generics.Map[string, *knownNodesBudget]
 }
 
 func (self *KnownNodes) GetList() []*known_node.KnownNodeScored {
@@ -97,12 +112,20 @@ func (self *KnownNodes) MarkKnownNodeDisconnected(knownNode *known_node.KnownNod
 	self.knownNotConnectedMaxHeap.Update(float64(atomic.LoadInt32(&knownNode.Score)), []byte(knownNode.URL))
 }
 
+//AddKnownNode adds a node to the list. The urls of the nodes that are not seeds are validated and normalized
 func (self *KnownNodes) AddKnownNode(url string, isSeed bool) (*known_node.KnownNodeScored, error) {
 
 	if url == "" {
 		return nil, errors.New("url is empty")
 	}
 
+	var err error
+	if !isSeed {
+		if url, err = known_node.ValidateURL(url); err != nil {
+			return nil, err
+		}
+	}
+
 	if atomic.LoadInt32(&self.knownCount) > config.NETWORK_KNOWN_NODES_LIMIT {
 		return nil, errors.New("Too many nodes already in the list")
 	}
@@ -123,8 +146,17 @@ func (self *KnownNodes) AddKnownNode(url string, isSeed bool) (*known_node.Known
 		return nil, errors.New("Already exists")
 	}
 
+	//a single host can't fill the list using different ports
+	host := getKnownNodeHost(url)
+
 	self.knownListMutex.Lock()
+	if !isSeed && self.knownHosts[host] >= config.NETWORK_KNOWN_NODES_PER_HOST {
+		self.knownListMutex.Unlock()
+		self.knownMap.Delete(url)
+		return nil, errors.New("Too many nodes of the same host")
+	}
 	self.knownList = append(self.knownList, knownNode)
+	self.knownHosts[host] += 1
 	self.knownListMutex.Unlock()
 
 	atomic.AddInt32(&self.knownCount, +1)
@@ -152,6 +184,11 @@ func (self *KnownNodes) RemoveKnownNode(knownNode *known_node.KnownNodeScored) {
 			if knownNode2 == knownNode {
 				self.knownList[i] = self.knownList[len(self.knownList)-1]
 				self.knownList = self.knownList[:len(self.knownList)-1]
+				if host := getKnownNodeHost(knownNode.URL); self.knownHosts[host] <= 1 {
+					delete(self.knownHosts, host)
+				} else {
+					self.knownHosts[host] -= 1
+				}
 				atomic.AddInt32(&self.knownCount, -1)
 				return
 			}
@@ -160,6 +197,98 @@ func (self *KnownNodes) RemoveKnownNode(knownNode *known_node.KnownNodeScored) {
 
 }
 
+//AddKnownNodesFromPeer adds the nodes received from a peer. Every peer IP can add only NETWORK_PEER_EXCHANGE_NEW_NODES_BUDGET new nodes every window to prevent a peer from poisoning the list
+func (self *KnownNodes) AddKnownNodesFromPeer(peer string, urls []string) (added int) {
+
+	budget, _ := self.peersBudgets.LoadOrStore(getPeerBudgetKey(peer), &knownNodesBudget{windowStart: time.Now()})
+
+	budget.Lock()
+	defer budget.Unlock()
+
+	if now := time.Now(); now.Sub(budget.windowStart) >= config.NETWORK_PEER_EXCHANGE_BUDGET_WINDOW {
+		budget.windowStart = now
+		budget.added = 0
+	}
+
+	for _, url := range urls {
+		if budget.added >= config.NETWORK_PEER_EXCHANGE_NEW_NODES_BUDGET {
+			return
+		}
+		if _, err := self.AddKnownNode(url, false); err == nil {
+			budget.added += 1
+			added += 1
+		}
+	}
+
+	return
+}
+
+//removeExpiredBudgets deletes the budgets of the previous windows. They will be recreated empty
+func (self *KnownNodes) removeExpiredBudgets() {
+	for {
+		time.Sleep(config.NETWORK_PEER_EXCHANGE_BUDGET_WINDOW)
+
+		now := time.Now()
+		self.peersBudgets.Range(func(peer string, budget *knownNodesBudget) bool {
+			budget.Lock()
+			expired := now.Sub(budget.windowStart) >= config.NETWORK_PEER_EXCHANGE_BUDGET_WINDOW
+			budget.Unlock()
+			if expired {
+				self.peersBudgets.Delete(peer)
+			}
+			return true
+		})
+	}
+}
+
+//GetPeerHost returns the host of a connection. Outgoing connections use the url of the node, incoming connections the remote address
+func GetPeerHost(remoteAddr string) string {
+	if strings.Contains(remoteAddr, "://") {
+		return getKnownNodeHost(remoteAddr)
+	}
+	if host, _, err := net.SplitHostPort(remoteAddr); err == nil {
+		return host
+	}
+	return remoteAddr
+}
+
+//getPeerBudgetKey returns the /64 network of the IPv6 peers, as a single host can use all the addresses of its network
+func getPeerBudgetKey(peer string) string {
+	if ip := net.ParseIP(peer); ip != nil && ip.To4() == nil {
+		return ip.Mask(net.CIDRMask(64, 128)).String() + "/64"
+	}
+	return peer
+}
+
+//IsPeerURL returns true when the host of the url resolves to an address of the peer
+func IsPeerURL(ctx context.Context, urlStr, remoteAddr string) bool {
+
+	urlAddrs, err := net.DefaultResolver.LookupIPAddr(ctx, getKnownNodeHost(urlStr))
+	if err != nil {
+		return false
+	}
+	peerAddrs, err := net.DefaultResolver.LookupIPAddr(ctx, GetPeerHost(remoteAddr))
+	if err != nil {
+		return false
+	}
+
+	for _, urlAddr := range urlAddrs {
+		for _, peerAddr := range peerAddrs {
+			if urlAddr.IP.Equal(peerAddr.IP) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func getKnownNodeHost(urlStr string) string {
+	if u, err := url.Parse(urlStr); err == nil {
+		return u.Hostname()
+	}
+	return urlStr
+}
+
 func NewKnownNodes(connectedNodes *connected_nodes.ConnectedNodes, bannedNodes *banned_nodes.BannedNodes) (knownNodes *KnownNodes) {
 
 	knownNodes = &KnownNodes{
@@ -167,11 +296,15 @@ func NewKnownNodes(connectedNodes *connected_nodes.ConnectedNodes, bannedNodes *
 		bannedNodes,
 		&generics.Map[string, *known_node.KnownNodeScored]{},
 		make([]*known_node.KnownNodeScored, 0),
+		make(map[string]int),
 		sync.RWMutex{},
 		min_max_heap.NewMaxMemoryHeap(),
 		sync.RWMutex{},
 		0,
+		&generics.Map[string, *knownNodesBudget]{},
 	}
 
+	recovery.SafeGo(knownNodes.removeExpiredBudgets)
+
 	return
 }
@@ -0,0 +1,112 @@
+package known_nodes
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"pandora-pay/config"
+	"pandora-pay/network/banned_nodes"
+	"pandora-pay/network/connected_nodes"
+	"pandora-pay/network/known_nodes/known_node"
+	"strconv"
+	"testing"
+)
+
+func TestValidateURL(t *testing.T) {
+
+	config.NETWORK_SELECTED = config.MAIN_NET_NETWORK_BYTE
+
+	for input, output := range map[string]string{
+		"ws://Node.Example.com:16000/ws": "ws://node.example.com:16000/ws",
+		"WSS://node.example.com/ws":      "wss://node.example.com/ws",
+		"ws://8.8.8.8:5230/ws":           "ws://8.8.8.8:5230/ws",
+		"ws://[2001:4860::8888]:5230/ws": "ws://[2001:4860::8888]:5230/ws",
+	} {
+		url, err := known_node.ValidateURL(input)
+		assert.Nil(t, err, input)
+		assert.Equal(t, output, url)
+	}
+
+	for _, input := range []string{
+		"",
+		"http://node.example.com/ws",
+		"ws://node.example.com:16000",
+		"ws://node.example.com:16000/ws?a=b",
+		"ws://user:pass@node.example.com/ws",
+		"ws://node.example.com:0/ws",
+		"ws://node.example.com:70000/ws",
+		"ws://127.0.0.1:5230/ws",
+		"ws://localhost:5230/ws",
+		"ws://192.168.1.2:5230/ws",
+		"ws://[::1]:5230/ws",
+		"ws://0.0.0.0:5230/ws",
+	} {
+		_, err := known_node.ValidateURL(input)
+		assert.NotNil(t, err, input)
+	}
+
+	config.NETWORK_SELECTED = config.DEV_NET_NETWORK_BYTE
+	defer func() { config.NETWORK_SELECTED = config.MAIN_NET_NETWORK_BYTE }()
+
+	_, err := known_node.ValidateURL("ws://127.0.0.1:5230/ws")
+	assert.Nil(t, err, "devnet accepts local nodes")
+}
+
+func TestAddKnownNodesFromPeer(t *testing.T) {
+
+	config.NETWORK_SELECTED = config.MAIN_NET_NETWORK_BYTE
+	knownNodes := NewKnownNodes(connected_nodes.NewConnectedNodes(), banned_nodes.NewBannedNodes())
+
+	//seeds are not validated
+	_, err := knownNodes.AddKnownNode("ws://127.0.0.1:5230/ws", true)
+	assert.Nil(t, err)
+
+	_, err = knownNodes.AddKnownNode("ws://NODE.example.com:16000/ws", false)
+	assert.Nil(t, err)
+	_, err = knownNodes.AddKnownNode("ws://node.example.com:16000/ws", false)
+	assert.NotNil(t, err, "urls are normalized")
+
+	//a single host can't register many ports
+	urls := make([]string, 0)
+	for i := 0; i < config.NETWORK_KNOWN_NODES_PER_HOST+5; i++ {
+		urls = append(urls, "ws://8.8.8.8:"+strconv.Itoa(10000+i)+"/ws")
+	}
+	assert.Equal(t, config.NETWORK_KNOWN_NODES_PER_HOST, knownNodes.AddKnownNodesFromPeer("1.1.1.1", urls))
+
+	//removing a node frees its slot
+	knownNodes.RemoveKnownNode(knownNodes.GetList()[2])
+	_, err = knownNodes.AddKnownNode(urls[len(urls)-1], false)
+	assert.Nil(t, err)
+
+	//invalid urls don't consume the budget of the peer
+	assert.Equal(t, 0, knownNodes.AddKnownNodesFromPeer("2.2.2.2", []string{"ws://10.0.0.1:5230/ws", "invalid"}))
+
+	urls = make([]string, 0)
+	for i := 0; i < config.NETWORK_PEER_EXCHANGE_NEW_NODES_BUDGET+10; i++ {
+		urls = append(urls, "wss://node"+strconv.Itoa(i)+".example.com/ws")
+	}
+	assert.Equal(t, config.NETWORK_PEER_EXCHANGE_NEW_NODES_BUDGET, knownNodes.AddKnownNodesFromPeer("2.2.2.2", urls))
+	assert.Equal(t, 0, knownNodes.AddKnownNodesFromPeer("2.2.2.2", urls[config.NETWORK_PEER_EXCHANGE_NEW_NODES_BUDGET:]), "the budget of the window is consumed")
+	assert.Equal(t, 10, knownNodes.AddKnownNodesFromPeer("3.3.3.3", urls), "every peer has its own budget")
+
+	urls = make([]string, 0)
+	for i := 0; i < config.NETWORK_PEER_EXCHANGE_NEW_NODES_BUDGET+10; i++ {
+		urls = append(urls, "wss://ipv6-"+strconv.Itoa(i)+".example.com/ws")
+	}
+	assert.Equal(t, config.NETWORK_PEER_EXCHANGE_NEW_NODES_BUDGET, knownNodes.AddKnownNodesFromPeer("2001:db8:1:1::1", urls))
+	assert.Equal(t, 0, knownNodes.AddKnownNodesFromPeer("2001:db8:1:1:ffff::2", urls[config.NETWORK_PEER_EXCHANGE_NEW_NODES_BUDGET:]), "the IPv6 peers of a /64 share the budget")
+	assert.Equal(t, 10, knownNodes.AddKnownNodesFromPeer("2001:db8:1:2::1", urls[config.NETWORK_PEER_EXCHANGE_NEW_NODES_BUDGET:]))
+
+	assert.Equal(t, "8.8.8.8", GetPeerHost("8.8.8.8:4000"))
+	assert.Equal(t, "node.example.com", GetPeerHost("ws://node.example.com:16000/ws"))
+}
+
+func TestIsPeerURL(t *testing.T) {
+
+	ctx := context.Background()
+
+	assert.True(t, IsPeerURL(ctx, "ws://8.8.8.8:5230/ws", "8.8.8.8:4000"))
+	assert.True(t, IsPeerURL(ctx, "ws://[2001:4860::8888]:5230/ws", "[2001:4860::8888]:4000"))
+	assert.True(t, IsPeerURL(ctx, "ws://8.8.8.8:5230/ws", "ws://8.8.8.8:5230/ws"), "the peers connected by the node use the url")
+	assert.False(t, IsPeerURL(ctx, "ws://8.8.8.8:5230/ws", "1.1.1.1:4000"), "urls of other hosts are rejected")
+	assert.False(t, IsPeerURL(ctx, "ws://8.8.8.8:5230/ws", ""))
+}
@@ -0,0 +1,72 @@
+package known_nodes_sync
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/url"
+	"pandora-pay/config"
+	"strings"
+)
+
+//Resolver resolves the DNS seeds. It is implemented by net.Resolver
+type Resolver interface {
+	LookupHost(ctx context.Context, host string) ([]string, error)
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+}
+
+//ResolveDNSSeed returns the urls of the nodes of a host:port DNS seed. TXT records must be urls of nodes, A and AAAA records are the IPs of nodes listening on the port of the seed
+func ResolveDNSSeed(ctx context.Context, resolver Resolver, seed string) ([]string, error) {
+
+	host, port, err := net.SplitHostPort(seed)
+	if err != nil {
+		return nil, err
+	}
+	if host == "" || port == "" {
+		return nil, errors.New("DNS seed must be host:port")
+	}
+
+	var urls []string
+
+	//a seed may publish only TXT or only A records
+	txts, errTxt := resolver.LookupTXT(ctx, host)
+	for _, txt := range txts {
+		if txt = strings.TrimSpace(txt); strings.HasPrefix(txt, "ws://") || strings.HasPrefix(txt, "wss://") {
+			urls = append(urls, txt)
+		}
+	}
+
+	ips, errHost := resolver.LookupHost(ctx, host)
+	for _, ip := range ips {
+		if net.ParseIP(ip) != nil {
+			urls = append(urls, (&url.URL{Scheme: "ws", Host: net.JoinHostPort(ip, port), Path: "/ws"}).String())
+		}
+	}
+
+	if errTxt != nil && errHost != nil {
+		return nil, errHost
+	}
+	return urls, nil
+}
+
+//ResolveDNSSeeds adds the nodes of the DNS seeds of the network to the known nodes. They are not marked as seeds, so they are validated and removed when they misbehave
+func (self *KnownNodesSync) ResolveDNSSeeds() (added int) {
+
+	for _, seed := range config.NETWORK_SELECTED_DNS_SEEDS {
+
+		ctx, cancel := context.WithTimeout(context.Background(), config.NETWORK_DNS_SEEDS_TIMEOUT)
+		urls, err := ResolveDNSSeed(ctx, self.resolver, seed)
+		cancel()
+		if err != nil {
+			continue
+		}
+
+		for _, url := range urls {
+			if _, err = self.knownNodes.AddKnownNode(url, false); err == nil {
+				added += 1
+			}
+		}
+	}
+
+	return
+}
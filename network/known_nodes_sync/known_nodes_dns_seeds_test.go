@@ -0,0 +1,67 @@
+package known_nodes_sync
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"pandora-pay/config"
+	"pandora-pay/network/banned_nodes"
+	"pandora-pay/network/connected_nodes"
+	"pandora-pay/network/known_nodes"
+	"testing"
+)
+
+type testResolver struct {
+	hosts map[string][]string
+	txts  map[string][]string
+}
+
+func (resolver *testResolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	if ips, ok := resolver.hosts[host]; ok {
+		return ips, nil
+	}
+	return nil, errors.New("no such host")
+}
+
+func (resolver *testResolver) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	if txts, ok := resolver.txts[name]; ok {
+		return txts, nil
+	}
+	return nil, errors.New("no such host")
+}
+
+func TestResolveDNSSeeds(t *testing.T) {
+
+	resolver := &testResolver{
+		map[string][]string{
+			"seed.example.com":  {"8.8.8.8", "2001:4860::8888", "10.0.0.1"},
+			"seed2.example.com": {"9.9.9.9"},
+		},
+		map[string][]string{
+			"seed.example.com": {"wss://node.example.com/ws", "v=spf1 -all"},
+		},
+	}
+
+	urls, err := ResolveDNSSeed(context.Background(), resolver, "seed.example.com:16000")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"wss://node.example.com/ws", "ws://8.8.8.8:16000/ws", "ws://[2001:4860::8888]:16000/ws", "ws://10.0.0.1:16000/ws"}, urls)
+
+	_, err = ResolveDNSSeed(context.Background(), resolver, "missing.example.com:16000")
+	assert.NotNil(t, err)
+
+	_, err = ResolveDNSSeed(context.Background(), resolver, "seed.example.com")
+	assert.NotNil(t, err, "the port is required")
+
+	config.NETWORK_SELECTED = config.MAIN_NET_NETWORK_BYTE
+	config.NETWORK_SELECTED_DNS_SEEDS = []string{"seed.example.com:16000", "missing.example.com:16000", "seed2.example.com:16001"}
+	defer func() { config.NETWORK_SELECTED_DNS_SEEDS = config.MAIN_NET_DNS_SEEDS }()
+
+	knownNodes := known_nodes.NewKnownNodes(connected_nodes.NewConnectedNodes(), banned_nodes.NewBannedNodes())
+	sync := &KnownNodesSync{nil, knownNodes, resolver}
+
+	//the private IP is rejected by the validation
+	assert.Equal(t, 4, sync.ResolveDNSSeeds())
+	for _, knownNode := range knownNodes.GetList() {
+		assert.False(t, knownNode.IsSeed)
+	}
+}
@@ -1,15 +1,19 @@
 package known_nodes_sync
 
 import (
+	"net"
+	"pandora-pay/config"
 	"pandora-pay/network/api/api_common"
 	"pandora-pay/network/known_nodes"
 	"pandora-pay/network/websocks"
 	"pandora-pay/network/websocks/connection"
+	"pandora-pay/network/websocks/connection/advanced_connection_types"
 )
 
 type KnownNodesSync struct {
 	websockets *websocks.Websockets
 	knownNodes *known_nodes.KnownNodes
+	resolver   Resolver
 }
 
 func (self *KnownNodesSync) DownloadNetworkNodes(conn *connection.AdvancedConnection) error {
@@ -19,16 +23,43 @@ func (self *KnownNodesSync) DownloadNetworkNodes(conn *connection.AdvancedConnec
 		return err
 	}
 
-	for _, node := range data.Nodes {
-		self.knownNodes.AddKnownNode(node.URL, false)
+	urls := make([]string, len(data.Nodes))
+	for i, node := range data.Nodes {
+		if node != nil {
+			urls[i] = node.URL
+		}
 	}
+	self.knownNodes.AddKnownNodesFromPeer(known_nodes.GetPeerHost(conn.RemoteAddr), urls)
 
 	return nil
 }
 
+//GetPushNetworkNodes returns the url advertised to the peers. It is the url of the node from the handshake, as the peers don't accept other urls
+func (self *KnownNodesSync) GetPushNetworkNodes() *api_common.APINetworkNodesPushRequest {
+
+	request := &api_common.APINetworkNodesPushRequest{Nodes: []string{}}
+	if config.NETWORK_WEBSOCKET_ADDRESS_URL_STRING != "" {
+		request.Nodes = append(request.Nodes, config.NETWORK_WEBSOCKET_ADDRESS_URL_STRING)
+	}
+
+	return request
+}
+
+//PushNetworkNodes advertises the node to the connected full nodes
+func (self *KnownNodesSync) PushNetworkNodes() {
+
+	request := self.GetPushNetworkNodes()
+	if len(request.Nodes) == 0 {
+		return
+	}
+
+	self.websockets.BroadcastJSON([]byte("network/nodes-push"), request, map[config.ConsensusType]bool{config.CONSENSUS_TYPE_FULL: true}, advanced_connection_types.UUID_ALL, 0)
+}
+
 func NewNodesKnownSync(websockets *websocks.Websockets, knownNodes *known_nodes.KnownNodes) *KnownNodesSync {
 	return &KnownNodesSync{
 		websockets: websockets,
 		knownNodes: knownNodes,
+		resolver:   net.DefaultResolver,
 	}
 }
@@ -0,0 +1,193 @@
+package light_wallet
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"pandora-pay/blockchain"
+	"pandora-pay/blockchain/blocks/block"
+	"pandora-pay/blockchain/data_storage/accounts/account"
+	"pandora-pay/blockchain/data_storage/registrations/registration"
+	"pandora-pay/config/config_coins"
+	"pandora-pay/helpers/advanced_buffers"
+	"pandora-pay/network/api/api_common"
+	"pandora-pay/network/api/api_common/api_types"
+	"pandora-pay/network/websocks"
+	"pandora-pay/network/websocks/connection"
+)
+
+//verifyStateRoot downloads the block committing the proven state and checks that it belongs to the followed chain and that it commits the proven roots
+func verifyStateRoot(conn *connection.AdvancedConnection, chain *blockchain.Blockchain, asset []byte, reply *api_common.APIAccountsByKeysReply) error {
+
+	//the followed chain might have advanced by one block meanwhile
+	chainData := chain.GetChainData()
+	if !bytes.Equal(reply.ChainHash, chainData.Hash) && !bytes.Equal(reply.ChainHash, chainData.PrevHash) {
+		return errors.New("Proven state is not committed by the followed chain")
+	}
+
+	blkReply, err := connection.SendJSONAwaitAnswer[api_common.APIBlockReply](conn, []byte("block"), &api_common.APIBlockRequest{0, reply.ChainHash, api_types.RETURN_SERIALIZED}, nil, 0)
+	if err != nil {
+		return err
+	}
+
+	blk := block.CreateEmptyBlock()
+	if err = blk.Deserialize(advanced_buffers.NewBufferReader(blkReply.BlockSerialized)); err != nil {
+		return err
+	}
+
+	if !bytes.Equal(blk.Bloom.Hash, reply.ChainHash) || blk.Height != reply.ChainHeight {
+		return errors.New("Node returned a different block")
+	}
+	if blk.Version < block.BLOCK_VERSION_STATE_ROOT {
+		return errors.New("Block doesn't commit a state root")
+	}
+
+	if reply.AssetProof == nil {
+		return errors.New("Node didn't return the asset proof")
+	}
+	return reply.AssetProof.Verify(blk.StateRoot, asset, reply.StateRoot, reply.RegsStateRoot)
+}
+
+//GetVerifiedAccountsByKeys returns the serialized accounts and registrations only after their proofs were verified against the state root committed by a block of the followed chain
+func GetVerifiedAccountsByKeys(websockets *websocks.Websockets, chain *blockchain.Blockchain, request *api_common.APIAccountsByKeysRequest) (*api_common.APIAccountsByKeysReply, error) {
+
+	if request.IncludeMempool {
+		return nil, errors.New("Mempool balances can not be proven")
+	}
+
+	publicKeys := make([][]byte, len(request.Keys))
+	for i, key := range request.Keys {
+		var err error
+		if publicKeys[i], err = key.GetPublicKey(true); err != nil {
+			return nil, err
+		}
+	}
+
+	conn := websockets.GetFirstSocket()
+	if conn == nil {
+		return nil, errors.New("No node is connected")
+	}
+
+	final := &api_common.APIAccountsByKeysRequest{request.Keys, request.Asset, false, api_types.RETURN_SERIALIZED, true}
+
+	reply, err := connection.SendJSONAwaitAnswer[api_common.APIAccountsByKeysReply](conn, []byte("accounts/by-keys"), final, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(reply.AccSerialized) != len(publicKeys) || len(reply.Proofs) != len(publicKeys) || len(reply.RegSerialized) != len(publicKeys) || len(reply.RegProofs) != len(publicKeys) {
+		return nil, errors.New("Node returned an invalid number of accounts or proofs")
+	}
+
+	for i, publicKey := range publicKeys {
+		if reply.Proofs[i] == nil || reply.RegProofs[i] == nil {
+			return nil, errors.New("Node didn't return the proof")
+		}
+		if err = reply.Proofs[i].Verify(reply.StateRoot, publicKey, reply.AccSerialized[i]); err != nil {
+			return nil, fmt.Errorf("Account %s: %s", hex.EncodeToString(publicKey), err.Error())
+		}
+		if err = reply.RegProofs[i].Verify(reply.RegsStateRoot, publicKey, reply.RegSerialized[i]); err != nil {
+			return nil, fmt.Errorf("Registration %s: %s", hex.EncodeToString(publicKey), err.Error())
+		}
+	}
+
+	if err = verifyStateRoot(conn, chain, request.Asset, reply); err != nil {
+		return nil, err
+	}
+
+	return reply, nil
+}
+
+//GetVerifiedAccount returns the serialized accounts and registration of a public key only after their proofs were verified. Plain accounts are not committed in the state root, so they are refused
+func GetVerifiedAccount(websockets *websocks.Websockets, chain *blockchain.Blockchain, request *api_common.APIAccountRequest) (*api_common.APIAccountReply, error) {
+
+	conn := websockets.GetFirstSocket()
+	if conn == nil {
+		return nil, errors.New("No node is connected")
+	}
+
+	final := &api_common.APIAccountRequest{request.APIAccountBaseRequest, api_types.RETURN_SERIALIZED}
+
+	reply, err := connection.SendJSONAwaitAnswer[api_common.APIAccountReply](conn, []byte("account"), final, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	if reply.PlainAccSerialized != nil {
+		return nil, errors.New("Plain accounts can not be proven")
+	}
+	if len(reply.AccsSerialized) != len(reply.AccsExtra) {
+		return nil, errors.New("Node returned an invalid number of accounts")
+	}
+
+	keys := []*api_types.APIAccountBaseRequest{&request.APIAccountBaseRequest}
+
+	//the registration is proven together with the native accounts when there is no other asset
+	assets := make([][]byte, len(reply.AccsExtra))
+	for i, extra := range reply.AccsExtra {
+		assets[i] = extra.Asset
+	}
+	if len(assets) == 0 {
+		assets = append(assets, config_coins.NATIVE_ASSET_FULL)
+	}
+
+	verified := &api_common.APIAccountReply{}
+	for i, asset := range assets {
+
+		var accs *api_common.APIAccountsByKeysReply
+		if accs, err = GetVerifiedAccountsByKeys(websockets, chain, &api_common.APIAccountsByKeysRequest{keys, asset, false, api_types.RETURN_SERIALIZED, true}); err != nil {
+			return nil, err
+		}
+
+		if i == 0 && accs.RegSerialized[0] != nil {
+			verified.RegSerialized = accs.RegSerialized[0]
+			verified.RegExtra = reply.RegExtra
+		}
+
+		//the proven state is the one before the last block, so a new account might not be proven yet
+		if i < len(reply.AccsExtra) && accs.AccSerialized[0] != nil {
+			verified.AccsSerialized = append(verified.AccsSerialized, accs.AccSerialized[0])
+			verified.AccsExtra = append(verified.AccsExtra, reply.AccsExtra[i])
+		}
+	}
+
+	if verified.RegSerialized != nil && verified.RegExtra == nil {
+		return nil, errors.New("Node didn't return the registration")
+	}
+
+	return verified, nil
+}
+
+//GetVerifiedAccounts returns the verified registrations and accounts of an asset, nil when missing
+func GetVerifiedAccounts(websockets *websocks.Websockets, chain *blockchain.Blockchain, publicKeys [][]byte, asset []byte) ([]*registration.Registration, []*account.Account, error) {
+
+	keys := make([]*api_types.APIAccountBaseRequest, len(publicKeys))
+	for i, publicKey := range publicKeys {
+		keys[i] = &api_types.APIAccountBaseRequest{"", publicKey}
+	}
+
+	reply, err := GetVerifiedAccountsByKeys(websockets, chain, &api_common.APIAccountsByKeysRequest{keys, asset, false, api_types.RETURN_SERIALIZED, true})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	regs := make([]*registration.Registration, len(publicKeys))
+	accs := make([]*account.Account, len(publicKeys))
+	for i, publicKey := range publicKeys {
+		if reply.RegSerialized[i] != nil {
+			regs[i] = registration.NewRegistration(publicKey, 0)
+			if err = regs[i].Deserialize(advanced_buffers.NewBufferReader(reply.RegSerialized[i])); err != nil {
+				return nil, nil, err
+			}
+		}
+		if reply.AccSerialized[i] != nil {
+			accs[i] = account.NewAccountClear(publicKey, 0, asset)
+			if err = accs[i].Deserialize(advanced_buffers.NewBufferReader(reply.AccSerialized[i])); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	return regs, accs, nil
+}
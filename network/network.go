@@ -2,6 +2,7 @@ package network
 
 import (
 	"pandora-pay/blockchain"
+	"pandora-pay/blockchain/forging"
 	"pandora-pay/config"
 	"pandora-pay/mempool"
 	"pandora-pay/network/banned_nodes"
@@ -27,7 +28,7 @@ type Network struct {
 	KnownNodesSync *known_nodes_sync.KnownNodesSync
 }
 
-func NewNetwork(settings *settings.Settings, chain *blockchain.Blockchain, mempool *mempool.Mempool, wallet *wallet.Wallet, txsValidator *txs_validator.TxsValidator, txsBuilder *txs_builder.TxsBuilder) (*Network, error) {
+func NewNetwork(settings *settings.Settings, chain *blockchain.Blockchain, mempool *mempool.Mempool, forging *forging.Forging, wallet *wallet.Wallet, txsValidator *txs_validator.TxsValidator, txsBuilder *txs_builder.TxsBuilder) (*Network, error) {
 
 	connectedNodes := connected_nodes.NewConnectedNodes()
 	bannedNodes := banned_nodes.NewBannedNodes()
@@ -37,7 +38,7 @@ func NewNetwork(settings *settings.Settings, chain *blockchain.Blockchain, mempo
 		knownNodes.AddKnownNode(seed.Url, true)
 	}
 
-	tcpServer, err := node_tcp.NewTcpServer(connectedNodes, bannedNodes, knownNodes, settings, chain, mempool, wallet, txsValidator, txsBuilder)
+	tcpServer, err := node_tcp.NewTcpServer(connectedNodes, bannedNodes, knownNodes, settings, chain, mempool, forging, wallet, txsValidator, txsBuilder)
 	if err != nil {
 		return nil, err
 	}
@@ -52,6 +53,8 @@ func NewNetwork(settings *settings.Settings, chain *blockchain.Blockchain, mempo
 		KnownNodesSync: known_nodes_sync.NewNodesKnownSync(tcpServer.HttpServer.Websockets, knownNodes),
 	}
 
+	network.continuouslyResolveDNSSeeds()
+
 	network.continuouslyConnectingNewPeers()
 
 	network.continuouslyDownloadChain()
@@ -59,6 +62,7 @@ func NewNetwork(settings *settings.Settings, chain *blockchain.Blockchain, mempo
 	if config.CONSENSUS == config.CONSENSUS_TYPE_FULL {
 		network.continuouslyDownloadMempool()
 		network.continuouslyDownloadNetworkNodes()
+		network.continuouslyPushNetworkNodes()
 	}
 
 	network.syncBlockchainNewConnections()
@@ -118,6 +118,44 @@ func (network *Network) continuouslyDownloadNetworkNodes() {
 
 }
 
+//continuouslyPushNetworkNodes advertises the node to the peers, so the nodes that are not listed by the seeds are discovered
+func (network *Network) continuouslyPushNetworkNodes() {
+
+	recovery.SafeGo(func() {
+
+		for {
+			time.Sleep(config.NETWORK_PEER_EXCHANGE_INTERVAL)
+			network.KnownNodesSync.PushNetworkNodes()
+		}
+
+	})
+
+}
+
+//continuouslyResolveDNSSeeds resolves the DNS seeds at start and every hour while the node knows only a few nodes
+func (network *Network) continuouslyResolveDNSSeeds() {
+
+	if len(config.NETWORK_SELECTED_DNS_SEEDS) == 0 {
+		return
+	}
+
+	recovery.SafeGo(func() {
+
+		for {
+
+			if len(network.KnownNodes.GetList()) < config.NETWORK_KNOWN_NODES_LIST_RETURN {
+				if added := network.KnownNodesSync.ResolveDNSSeeds(); added > 0 {
+					gui.GUI.Log("DNS seeds added", added, "nodes")
+				}
+			}
+
+			time.Sleep(config.NETWORK_DNS_SEEDS_INTERVAL)
+		}
+
+	})
+
+}
+
 func (network *Network) syncBlockchainNewConnections() {
 	recovery.SafeGo(func() {
 
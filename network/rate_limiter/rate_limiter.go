@@ -0,0 +1,208 @@
+package rate_limiter
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"pandora-pay/config"
+	"pandora-pay/config/config_auth"
+	"pandora-pay/helpers/generics"
+	"pandora-pay/recovery"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type RateLimitClass uint8
+
+var ErrRateLimited = errors.New("Rate limit exceeded")
+
+const (
+	RATE_LIMIT_CHEAP RateLimitClass = iota
+	RATE_LIMIT_EXPENSIVE
+	RATE_LIMIT_END
+)
+
+func (class RateLimitClass) String() string {
+	switch class {
+	case RATE_LIMIT_CHEAP:
+		return "cheap"
+	case RATE_LIMIT_EXPENSIVE:
+		return "expensive"
+	default:
+		return "unknown"
+	}
+}
+
+var expensiveRoutes = map[string]bool{
+	"tx-preview":          true,
+	"accounts/by-keys":    true,
+	"accounts/state-root": true,
+	"webhook/subscribe":   true,
+	"graphql":             true,
+}
+
+//GetClass returns the budget used by a route. Wallet routes decrypt balances and are always expensive
+func GetClass(route string) RateLimitClass {
+	route = strings.TrimPrefix(route, "/")
+	if expensiveRoutes[route] || strings.HasPrefix(route, "wallet/") {
+		return RATE_LIMIT_EXPENSIVE
+	}
+	return RATE_LIMIT_CHEAP
+}
+
+//GetClientKey identifies the client by the authenticated user or otherwise by the IP
+func GetClientKey(remoteAddr, user string) string {
+	if user != "" {
+		return "user:" + user
+	}
+	if host, _, err := net.SplitHostPort(remoteAddr); err == nil {
+		return "ip:" + host
+	}
+	return "ip:" + remoteAddr
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+	sync.Mutex
+}
+
+//take refills the bucket based on the elapsed time and consumes one token
+func (b *bucket) take(rate float64, burst float64, now time.Time) bool {
+	b.Lock()
+	defer b.Unlock()
+
+	b.tokens += now.Sub(b.last).Seconds() * rate
+	if b.tokens > burst {
+		b.tokens = burst
+	}
+	b.last = now
+
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens -= 1
+	return true
+}
+
+type RateLimiterMetricsClass struct {
+	Allowed uint64 `json:"allowed" msgpack:"allowed"`
+	Limited uint64 `json:"limited" msgpack:"limited"`
+	Rate    uint64 `json:"rate" msgpack:"rate"`
+}
+
+type RateLimiterMetrics struct {
+	Clients   uint64                   `json:"clients" msgpack:"clients"`
+	Cheap     *RateLimiterMetricsClass `json:"cheap" msgpack:"cheap"`
+	Expensive *RateLimiterMetricsClass `json:"expensive" msgpack:"expensive"`
+}
+
+type RateLimiter struct {
+	buckets [RATE_LIMIT_END]*generics.Map[string, *bucket]
+	allowed [RATE_LIMIT_END]uint64 //use atomic
+	limited [RATE_LIMIT_END]uint64 //use atomic
+}
+
+func getRate(class RateLimitClass) uint64 {
+	if class == RATE_LIMIT_EXPENSIVE {
+		return config.API_RATE_LIMIT_EXPENSIVE
+	}
+	return config.API_RATE_LIMIT_CHEAP
+}
+
+//Allow consumes one request from the budget of the client. Over quota requests are counted and rejected
+func (limiter *RateLimiter) Allow(clientKey string, class RateLimitClass) error {
+
+	rate := getRate(class)
+	if rate == 0 {
+		atomic.AddUint64(&limiter.allowed[class], 1)
+		return nil
+	}
+
+	now := time.Now()
+	burst := float64(rate * config.API_RATE_LIMIT_BURST)
+
+	b, _ := limiter.buckets[class].LoadOrStore(clientKey, &bucket{tokens: burst, last: now})
+	if !b.take(float64(rate), burst, now) {
+		atomic.AddUint64(&limiter.limited[class], 1)
+		return fmt.Errorf("%w for %s requests. Maximum %d requests per second", ErrRateLimited, class.String(), rate)
+	}
+
+	atomic.AddUint64(&limiter.allowed[class], 1)
+	return nil
+}
+
+func (limiter *RateLimiter) AllowRoute(clientKey, route string) error {
+	return limiter.Allow(clientKey, GetClass(route))
+}
+
+//AllowLogin consumes the expensive budget of the IP before a password is verified with argon2. It must be called before authenticating the user and pass. The passwords already verified are not limited
+func (limiter *RateLimiter) AllowLogin(remoteAddr, username, password string) error {
+	if !config_auth.IsVerificationRequired(username, password) {
+		return nil
+	}
+	return limiter.Allow(GetClientKey(remoteAddr, ""), RATE_LIMIT_EXPENSIVE)
+}
+
+func (limiter *RateLimiter) GetMetrics() *RateLimiterMetrics {
+
+	clients := make(map[string]bool)
+	out := &RateLimiterMetrics{}
+
+	for class := RateLimitClass(0); class < RATE_LIMIT_END; class++ {
+		limiter.buckets[class].Range(func(key string, value *bucket) bool {
+			clients[key] = true
+			return true
+		})
+
+		metrics := &RateLimiterMetricsClass{
+			atomic.LoadUint64(&limiter.allowed[class]),
+			atomic.LoadUint64(&limiter.limited[class]),
+			getRate(class),
+		}
+
+		if class == RATE_LIMIT_EXPENSIVE {
+			out.Expensive = metrics
+		} else {
+			out.Cheap = metrics
+		}
+	}
+
+	out.Clients = uint64(len(clients))
+	return out
+}
+
+//removeIdleBuckets deletes the buckets that were refilled completely. They will be recreated full
+func (limiter *RateLimiter) removeIdleBuckets() {
+	for {
+		time.Sleep(time.Minute)
+
+		now := time.Now()
+		for class := RateLimitClass(0); class < RATE_LIMIT_END; class++ {
+			rate := getRate(class)
+			limiter.buckets[class].Range(func(key string, b *bucket) bool {
+				b.Lock()
+				idle := rate == 0 || b.tokens+now.Sub(b.last).Seconds()*float64(rate) >= float64(rate*config.API_RATE_LIMIT_BURST)
+				b.Unlock()
+				if idle {
+					limiter.buckets[class].Delete(key)
+				}
+				return true
+			})
+		}
+	}
+}
+
+func NewRateLimiter() *RateLimiter {
+
+	limiter := &RateLimiter{}
+	for class := range limiter.buckets {
+		limiter.buckets[class] = &generics.Map[string, *bucket]{}
+	}
+
+	recovery.SafeGo(limiter.removeIdleBuckets)
+
+	return limiter
+}
@@ -0,0 +1,71 @@
+package rate_limiter
+
+import (
+	"github.com/stretchr/testify/assert"
+	"pandora-pay/config"
+	"pandora-pay/config/config_auth"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter(t *testing.T) {
+
+	assert.Equal(t, RATE_LIMIT_CHEAP, GetClass("/ping"))
+	assert.Equal(t, RATE_LIMIT_EXPENSIVE, GetClass("tx-preview"))
+	assert.Equal(t, RATE_LIMIT_EXPENSIVE, GetClass("/wallet/get-balances"))
+
+	assert.Equal(t, "ip:127.0.0.1", GetClientKey("127.0.0.1:5230", ""))
+	assert.Equal(t, "user:admin", GetClientKey("127.0.0.1:5230", "admin"))
+
+	oldExpensive, oldBurst := config.API_RATE_LIMIT_EXPENSIVE, config.API_RATE_LIMIT_BURST
+	defer func() { config.API_RATE_LIMIT_EXPENSIVE, config.API_RATE_LIMIT_BURST = oldExpensive, oldBurst }()
+
+	config.API_RATE_LIMIT_EXPENSIVE = 2
+	config.API_RATE_LIMIT_BURST = 1
+
+	limiter := NewRateLimiter()
+
+	assert.NoError(t, limiter.AllowRoute("ip:1", "tx-preview"))
+	assert.NoError(t, limiter.AllowRoute("ip:1", "tx-preview"))
+	assert.Error(t, limiter.AllowRoute("ip:1", "tx-preview"))
+	assert.NoError(t, limiter.AllowRoute("ip:2", "tx-preview"), "every client has its own budget")
+
+	time.Sleep(600 * time.Millisecond)
+	assert.NoError(t, limiter.AllowRoute("ip:1", "tx-preview"))
+
+	metrics := limiter.GetMetrics()
+	assert.Equal(t, uint64(2), metrics.Clients)
+	assert.Equal(t, uint64(4), metrics.Expensive.Allowed)
+	assert.Equal(t, uint64(1), metrics.Expensive.Limited)
+
+	config.API_RATE_LIMIT_EXPENSIVE = 0
+	for i := 0; i < 10; i++ {
+		assert.NoError(t, limiter.AllowRoute("ip:1", "tx-preview"))
+	}
+}
+
+func TestAllowLogin(t *testing.T) {
+
+	hash, err := config_auth.HashPassword("secret")
+	assert.NoError(t, err)
+
+	oldUsers := config_auth.CONFIG_AUTH_USERS_MAP
+	oldExpensive, oldBurst := config.API_RATE_LIMIT_EXPENSIVE, config.API_RATE_LIMIT_BURST
+	defer func() {
+		config_auth.CONFIG_AUTH_USERS_MAP = oldUsers
+		config.API_RATE_LIMIT_EXPENSIVE, config.API_RATE_LIMIT_BURST = oldExpensive, oldBurst
+	}()
+
+	config_auth.CONFIG_AUTH_USERS_MAP = map[string]*config_auth.ConfigAuth{"user": {Username: "user", PasswordHash: hash}}
+	config.API_RATE_LIMIT_EXPENSIVE = 1
+	config.API_RATE_LIMIT_BURST = 1
+
+	limiter := NewRateLimiter()
+
+	assert.NoError(t, limiter.AllowLogin("10.0.0.1:5000", "user", "wrong"))
+	assert.ErrorIs(t, limiter.AllowLogin("10.0.0.1:5001", "user", "wrong"), ErrRateLimited, "the failed logins are limited by IP")
+	assert.NoError(t, limiter.AllowLogin("10.0.0.1:5000", "missing", "wrong"), "unknown users are not verified")
+
+	assert.NotNil(t, config_auth.Authenticate("user", "secret"))
+	assert.NoError(t, limiter.AllowLogin("10.0.0.1:5000", "user", "secret"), "the passwords already verified are not limited")
+}
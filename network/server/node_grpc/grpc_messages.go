@@ -0,0 +1,69 @@
+package node_grpc
+
+import (
+	"pandora-pay/network/api/api_common"
+	"pandora-pay/network/api/api_common/api_types"
+)
+
+//the messages are generated from pandora.proto. These functions convert the replies of the API routes to them
+
+func newChain(chain *api_common.APIBlockchain) *Chain {
+	return &Chain{
+		Height:            chain.Height,
+		Hash:              chain.Hash,
+		PrevHash:          chain.PrevHash,
+		KernelHash:        chain.KernelHash,
+		PrevKernelHash:    chain.PrevKernelHash,
+		Timestamp:         chain.Timestamp,
+		TransactionsCount: chain.TransactionsCount,
+		AccountsCount:     chain.AccountsCount,
+		AssetsCount:       chain.AssetsCount,
+		Target:            chain.Target,
+		Supply:            chain.Supply,
+		TotalDifficulty:   chain.TotalDifficulty,
+	}
+}
+
+func newBlockReply(reply *api_common.APIBlockReply) *BlockReply {
+	return &BlockReply{Block: reply.BlockSerialized, TxHashes: reply.Txs}
+}
+
+func newTxReply(reply *api_common.APITxReply) *TxReply {
+	out := &TxReply{Tx: reply.TxSerialized, Mempool: reply.Mempool, Confirmations: reply.Confirmations}
+	if reply.Info != nil {
+		out.Info = &TxInfo{Height: reply.Info.Height, BlockHeight: reply.Info.BlkHeight, Timestamp: reply.Info.Timestmap}
+	}
+	return out
+}
+
+//newAccountReply skips the assets without an account
+func newAccountReply(reply *api_common.APIAccountReply) *AccountReply {
+
+	out := &AccountReply{Accounts: make([]*AccountAsset, 0, len(reply.AccsExtra))}
+	for i, extra := range reply.AccsExtra {
+		if extra != nil {
+			out.Accounts = append(out.Accounts, &AccountAsset{Asset: extra.Asset, Index: extra.Index, Account: reply.AccsSerialized[i]})
+		}
+	}
+
+	if reply.PlainAccExtra != nil {
+		out.PlainAccount, out.PlainAccountIndex = reply.PlainAccSerialized, reply.PlainAccExtra.Index
+	}
+	if reply.RegExtra != nil {
+		out.Registration, out.RegistrationIndex = reply.RegSerialized, reply.RegExtra.Index
+	}
+	return out
+}
+
+func newMempoolReply(reply *api_common.APIMempoolReply) *MempoolReply {
+	return &MempoolReply{ChainHash: reply.ChainHash, Count: int32(reply.Count), Hashes: reply.Hashes}
+}
+
+func newAccountNotification(notification *api_types.APISubscriptionNotification) *AccountNotification {
+	return &AccountNotification{
+		Type:  uint64(notification.SubscriptionType),
+		Key:   notification.Key,
+		Data:  notification.Data,
+		Extra: notification.Extra,
+	}
+}
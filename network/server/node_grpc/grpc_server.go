@@ -0,0 +1,434 @@
+package node_grpc
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"net"
+	"pandora-pay/blockchain"
+	"pandora-pay/config"
+	"pandora-pay/config/config_auth"
+	"pandora-pay/gui"
+	"pandora-pay/network/api/api_common"
+	"pandora-pay/network/api/api_common/api_types"
+	"pandora-pay/network/rate_limiter"
+	"pandora-pay/network/server/node_http_rpc"
+	"pandora-pay/network/websocks/connection"
+	"pandora-pay/recovery"
+	"sync"
+)
+
+const (
+	GRPC_MAX_MESSAGE                = int(config.WEBSOCKETS_MAX_READ)
+	GRPC_MAX_STREAMS                = 1000
+	GRPC_MAX_CLIENT_STREAMS         = 10  //streams of every IP or authenticated user
+	GRPC_MAX_CONNECTION_CONCURRENCY = 100 //concurrent calls of every connection
+	GRPC_STREAM_BUFFER              = 256 //a stream that falls behind is closed
+)
+
+//grpcInvoker runs the API methods. It is implemented by the JSON-RPC server, so both of them share the handlers
+type grpcInvoker interface {
+	Invoke(name string, decode func(args any) error, clientKey string, principal *config_auth.Principal) (any, *node_http_rpc.JSONRPCError)
+}
+
+//grpcSubscriptions are the websockets subscriptions
+type grpcSubscriptions interface {
+	Subscribe(subscription *connection.SubscriptionNotification) error
+	Unsubscribe(subscription *connection.SubscriptionNotification)
+}
+
+//grpcStream buffers the messages of a server stream
+type grpcStream struct {
+	clientKey string
+	cn        chan any
+	closed    bool
+	lock      *sync.Mutex
+}
+
+type GRPCServer struct {
+	UnimplementedNodeServer
+	invoker       grpcInvoker
+	subscriptions grpcSubscriptions
+	rateLimiter   *rate_limiter.RateLimiter
+	lastBlock     *Chain
+	blockStreams  map[*grpcStream]bool
+	streamsCount  int
+	clientStreams map[string]int
+	lock          *sync.Mutex
+	server        *grpc.Server
+}
+
+//send never blocks. The stream is closed when it falls behind
+func (stream *grpcStream) send(message any) {
+	stream.lock.Lock()
+	defer stream.lock.Unlock()
+
+	if stream.closed {
+		return
+	}
+	select {
+	case stream.cn <- message:
+	default:
+		stream.closed = true
+		close(stream.cn)
+	}
+}
+
+func (stream *grpcStream) close() {
+	stream.lock.Lock()
+	defer stream.lock.Unlock()
+
+	if !stream.closed {
+		stream.closed = true
+		close(stream.cn)
+	}
+}
+
+//Notify receives the websockets subscriptions notifications
+func (stream *grpcStream) Notify(notification *api_types.APISubscriptionNotification) {
+	stream.send(newAccountNotification(notification))
+}
+
+//toGRPCError maps the JSON-RPC errors to the gRPC status codes
+func toGRPCError(rpcErr *node_http_rpc.JSONRPCError) error {
+
+	message := rpcErr.Message
+	if data, ok := rpcErr.Data.(string); ok && data != "" {
+		message += ": " + data
+	}
+
+	switch rpcErr.Code {
+	case node_http_rpc.JSON_RPC_INVALID_PARAMS, node_http_rpc.JSON_RPC_INVALID_REQUEST:
+		return status.Error(codes.InvalidArgument, message)
+	case node_http_rpc.JSON_RPC_METHOD_NOT_FOUND:
+		return status.Error(codes.Unimplemented, message)
+	case node_http_rpc.JSON_RPC_INTERNAL_ERROR:
+		return status.Error(codes.Internal, message)
+	case node_http_rpc.JSON_RPC_UNAUTHORIZED:
+		return status.Error(codes.Unauthenticated, message)
+	case node_http_rpc.JSON_RPC_PERMISSION_DENIED:
+		return status.Error(codes.PermissionDenied, message)
+	case node_http_rpc.JSON_RPC_RATE_LIMIT_EXCEEDED:
+		return status.Error(codes.ResourceExhausted, message)
+	default:
+		return status.Error(codes.Unknown, message)
+	}
+}
+
+//authenticate reads the credentials of the "authorization" metadata and returns the key of the client for the rate limiter
+func (server *GRPCServer) authenticate(ctx context.Context) (string, *config_auth.Principal, error) {
+
+	remoteAddr := ""
+	if p, ok := peer.FromContext(ctx); ok {
+		remoteAddr = p.Addr.String()
+	}
+
+	authorization := ""
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get("authorization"); len(values) > 0 {
+			authorization = values[0]
+		}
+	}
+
+	principal, err := node_http_rpc.Authenticate(authorization, remoteAddr, server.rateLimiter)
+	if err != nil {
+		return "", nil, status.Error(codes.ResourceExhausted, err.Error())
+	}
+
+	user := ""
+	if principal != nil {
+		user = principal.Username
+	}
+	return rate_limiter.GetClientKey(remoteAddr, user), principal, nil
+}
+
+//invoke runs the route with the arguments of a typed method
+func invoke[T any, B any](server *GRPCServer, ctx context.Context, name string, args *T) (*B, error) {
+
+	clientKey, principal, err := server.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result, rpcErr := server.invoker.Invoke(name, func(out any) error {
+		*out.(*T) = *args
+		return nil
+	}, clientKey, principal)
+	if rpcErr != nil {
+		return nil, toGRPCError(rpcErr)
+	}
+	return result.(*B), nil
+}
+
+func (server *GRPCServer) GetChain(ctx context.Context, request *ChainRequest) (*Chain, error) {
+	reply, err := invoke[struct{}, api_common.APIBlockchain](server, ctx, "chain", &struct{}{})
+	if err != nil {
+		return nil, err
+	}
+	return newChain(reply), nil
+}
+
+func (server *GRPCServer) GetBlock(ctx context.Context, request *BlockRequest) (*BlockReply, error) {
+	reply, err := invoke[api_common.APIBlockRequest, api_common.APIBlockReply](server, ctx, "block", &api_common.APIBlockRequest{request.Height, request.Hash, api_types.RETURN_SERIALIZED})
+	if err != nil {
+		return nil, err
+	}
+	return newBlockReply(reply), nil
+}
+
+func (server *GRPCServer) GetTx(ctx context.Context, request *TxRequest) (*TxReply, error) {
+	reply, err := invoke[api_common.APITxRequest, api_common.APITxReply](server, ctx, "tx", &api_common.APITxRequest{request.Height, request.Hash, api_types.RETURN_SERIALIZED})
+	if err != nil {
+		return nil, err
+	}
+	return newTxReply(reply), nil
+}
+
+func (server *GRPCServer) GetAccount(ctx context.Context, request *AccountRequest) (*AccountReply, error) {
+	reply, err := invoke[api_common.APIAccountRequest, api_common.APIAccountReply](server, ctx, "account", &api_common.APIAccountRequest{api_types.APIAccountBaseRequest{request.Address, request.PublicKey}, api_types.RETURN_SERIALIZED})
+	if err != nil {
+		return nil, err
+	}
+	return newAccountReply(reply), nil
+}
+
+func (server *GRPCServer) GetAsset(ctx context.Context, request *AssetRequest) (*AssetReply, error) {
+	reply, err := invoke[api_common.APIAssetRequest, api_common.APIAssetReply](server, ctx, "asset", &api_common.APIAssetRequest{request.Height, request.Hash, api_types.RETURN_SERIALIZED})
+	if err != nil {
+		return nil, err
+	}
+	return &AssetReply{Asset: reply.Serialized}, nil
+}
+
+func (server *GRPCServer) GetMempool(ctx context.Context, request *MempoolRequest) (*MempoolReply, error) {
+	reply, err := invoke[api_common.APIMempoolRequest, api_common.APIMempoolReply](server, ctx, "mempool", &api_common.APIMempoolRequest{request.ChainHash, int(request.Page), int(request.Count)})
+	if err != nil {
+		return nil, err
+	}
+	return newMempoolReply(reply), nil
+}
+
+func (server *GRPCServer) SubmitTx(ctx context.Context, request *SubmitTxRequest) (*SubmitTxReply, error) {
+	reply, err := invoke[api_common.APIMempoolNewTxRequest, api_common.APIMempoolNewTxReply](server, ctx, "mempool/new-tx", &api_common.APIMempoolNewTxRequest{request.Tx})
+	if err != nil {
+		return nil, err
+	}
+	return &SubmitTxReply{Result: reply.Result}, nil
+}
+
+func (server *GRPCServer) Call(ctx context.Context, request *CallRequest) (*CallReply, error) {
+
+	clientKey, principal, err := server.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result, rpcErr := server.invoker.Invoke(request.Method, node_http_rpc.JSONParams(request.Params), clientKey, principal)
+	if rpcErr != nil {
+		return nil, toGRPCError(rpcErr)
+	}
+
+	out, err := json.Marshal(result)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &CallReply{Result: out}, nil
+}
+
+//openStream limits the streams of the node and of every client
+func (server *GRPCServer) openStream(ctx context.Context, method string) (*grpcStream, error) {
+
+	clientKey, _, err := server.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = server.rateLimiter.AllowRoute(clientKey, method); err != nil {
+		return nil, status.Error(codes.ResourceExhausted, err.Error())
+	}
+
+	server.lock.Lock()
+	defer server.lock.Unlock()
+
+	if server.streamsCount >= GRPC_MAX_STREAMS {
+		return nil, status.Error(codes.Unavailable, "Too many streams")
+	}
+	if server.clientStreams[clientKey] >= GRPC_MAX_CLIENT_STREAMS {
+		return nil, status.Error(codes.ResourceExhausted, "Too many streams of the client")
+	}
+	server.streamsCount += 1
+	server.clientStreams[clientKey] += 1
+
+	return &grpcStream{clientKey, make(chan any, GRPC_STREAM_BUFFER), false, &sync.Mutex{}}, nil
+}
+
+func (server *GRPCServer) closeStream(stream *grpcStream) {
+	server.lock.Lock()
+	defer server.lock.Unlock()
+
+	server.streamsCount -= 1
+	if server.clientStreams[stream.clientKey] -= 1; server.clientStreams[stream.clientKey] == 0 {
+		delete(server.clientStreams, stream.clientKey)
+	}
+	delete(server.blockStreams, stream)
+	stream.close()
+}
+
+//writeStream sends the messages of the stream until the client leaves
+func writeStream(ctx context.Context, stream *grpcStream, send func(message any) error) error {
+	for {
+		select {
+		case message, ok := <-stream.cn:
+			if !ok {
+				return status.Error(codes.ResourceExhausted, "The stream fell behind")
+			}
+			if err := send(message); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+func (server *GRPCServer) SubscribeBlocks(request *SubscribeBlocksRequest, out Node_SubscribeBlocksServer) error {
+
+	stream, err := server.openStream(out.Context(), "SubscribeBlocks")
+	if err != nil {
+		return err
+	}
+	defer server.closeStream(stream)
+
+	//the stream starts with the current chain
+	server.lock.Lock()
+	server.blockStreams[stream] = true
+	lastBlock := server.lastBlock
+	server.lock.Unlock()
+
+	if lastBlock != nil {
+		if err = out.Send(lastBlock); err != nil {
+			return err
+		}
+	}
+
+	return writeStream(out.Context(), stream, func(message any) error {
+		return out.Send(message.(*Chain))
+	})
+}
+
+func (server *GRPCServer) SubscribeAccount(request *SubscribeAccountRequest, out Node_SubscribeAccountServer) error {
+
+	subscriptionType, returnType := api_types.SubscriptionType(request.Type), api_types.APIReturnType(request.ReturnType)
+	if subscriptionType != api_types.SUBSCRIPTION_ACCOUNT && subscriptionType != api_types.SUBSCRIPTION_ACCOUNT_TRANSACTIONS {
+		return status.Error(codes.InvalidArgument, "Only Account and AccountTransactions subscriptions are supported")
+	}
+	if returnType != api_types.RETURN_SERIALIZED && returnType != api_types.RETURN_JSON {
+		return status.Error(codes.InvalidArgument, "Invalid return type")
+	}
+	if err := connection.CheckSubscriptionLength(request.PublicKey, subscriptionType); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	stream, err := server.openStream(out.Context(), "SubscribeAccount")
+	if err != nil {
+		return err
+	}
+	defer server.closeStream(stream)
+
+	subscription := &connection.SubscriptionNotification{&connection.Subscription{subscriptionType, request.PublicKey, returnType}, nil, stream, connection.NewUUID()}
+	if err = server.subscriptions.Subscribe(subscription); err != nil {
+		return status.Error(codes.Unavailable, err.Error())
+	}
+	defer server.subscriptions.Unsubscribe(subscription)
+
+	return writeStream(out.Context(), stream, func(message any) error {
+		return out.Send(message.(*AccountNotification))
+	})
+}
+
+//publishBlock sends the chain to the blocks streams
+func (server *GRPCServer) publishBlock(chain *api_common.APIBlockchain) {
+
+	message := newChain(chain)
+
+	server.lock.Lock()
+	defer server.lock.Unlock()
+
+	server.lastBlock = message
+	for stream := range server.blockStreams {
+		stream.send(message)
+	}
+}
+
+func (server *GRPCServer) processBlocks(chain *blockchain.Blockchain) {
+
+	updateNewChainDataUpdateCn := chain.UpdateNewChainDataUpdate.AddListener()
+	defer chain.UpdateNewChainDataUpdate.RemoveChannel(updateNewChainDataUpdateCn)
+
+	for {
+		chainDataUpdate, ok := <-updateNewChainDataUpdateCn
+		if !ok {
+			return
+		}
+		server.publishBlock(api_common.NewAPIBlockchain(chainDataUpdate.Update))
+	}
+}
+
+//newGRPCServer registers the service. Compressed messages are rejected as no compressor is registered
+func newGRPCServer(invoker grpcInvoker, subscriptions grpcSubscriptions, rateLimiter *rate_limiter.RateLimiter, options ...grpc.ServerOption) *GRPCServer {
+
+	server := &GRPCServer{
+		invoker:       invoker,
+		subscriptions: subscriptions,
+		rateLimiter:   rateLimiter,
+		blockStreams:  map[*grpcStream]bool{},
+		clientStreams: map[string]int{},
+		lock:          &sync.Mutex{},
+	}
+
+	server.server = grpc.NewServer(append(options,
+		grpc.MaxRecvMsgSize(GRPC_MAX_MESSAGE),
+		grpc.MaxConcurrentStreams(GRPC_MAX_CONNECTION_CONCURRENCY),
+	)...)
+	RegisterNodeServer(server.server, server)
+
+	return server
+}
+
+//NewGRPCServer listens on the port. It uses TLS when a certificate is given, otherwise cleartext HTTP/2
+func NewGRPCServer(port string, tlsConfig *tls.Config, rpc *node_http_rpc.HTTPServerRPC, chain *blockchain.Blockchain, subscriptions grpcSubscriptions, rateLimiter *rate_limiter.RateLimiter) (*GRPCServer, error) {
+
+	var options []grpc.ServerOption
+	if tlsConfig != nil {
+		options = append(options, grpc.Creds(credentials.NewTLS(tlsConfig.Clone())))
+	}
+
+	server := newGRPCServer(rpc, subscriptions, rateLimiter, options...)
+	server.publishBlock(api_common.NewAPIBlockchain(chain.GetChainData()))
+
+	listener, err := net.Listen("tcp", ":"+port)
+	if err != nil {
+		return nil, errors.New("Error creating gRPC server " + err.Error())
+	}
+
+	recovery.SafeGo(func() {
+		server.processBlocks(chain)
+	})
+
+	recovery.SafeGo(func() {
+		if err := server.server.Serve(listener); err != nil {
+			gui.GUI.Error("Error opening gRPC server", err)
+		}
+	})
+
+	gui.GUI.InfoUpdate("gRPC", port)
+
+	return server, nil
+}
@@ -0,0 +1,209 @@
+package node_grpc
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"net"
+	"pandora-pay/config/config_auth"
+	"pandora-pay/cryptography"
+	"pandora-pay/helpers"
+	"pandora-pay/network/api/api_common"
+	"pandora-pay/network/api/api_common/api_types"
+	"pandora-pay/network/rate_limiter"
+	"pandora-pay/network/server/node_http_rpc"
+	"pandora-pay/network/websocks/connection"
+	"testing"
+	"time"
+)
+
+//testInvoker runs the routes like node_http_rpc, decoding the arguments with the given function
+type testInvoker struct {
+	principal *config_auth.Principal
+}
+
+func (invoker *testInvoker) Invoke(name string, decode func(args any) error, clientKey string, principal *config_auth.Principal) (any, *node_http_rpc.JSONRPCError) {
+	invoker.principal = principal
+	switch name {
+	case "echo":
+		args := map[string]any{}
+		if err := decode(&args); err != nil {
+			return nil, &node_http_rpc.JSONRPCError{node_http_rpc.JSON_RPC_INVALID_PARAMS, "Invalid params", err.Error()}
+		}
+		return args, nil
+	case "block":
+		args := &api_common.APIBlockRequest{}
+		if err := decode(args); err != nil {
+			return nil, &node_http_rpc.JSONRPCError{node_http_rpc.JSON_RPC_INVALID_PARAMS, "Invalid params", err.Error()}
+		}
+		if args.ReturnType != api_types.RETURN_SERIALIZED {
+			return nil, &node_http_rpc.JSONRPCError{node_http_rpc.JSON_RPC_INVALID_PARAMS, "Invalid params", "serialized"}
+		}
+		return &api_common.APIBlockReply{BlockSerialized: args.Hash, Txs: [][]byte{{byte(args.Height)}}}, nil
+	case "wallet/get-addresses":
+		return nil, &node_http_rpc.JSONRPCError{node_http_rpc.JSON_RPC_UNAUTHORIZED, "Unauthorized", nil}
+	default:
+		return nil, &node_http_rpc.JSONRPCError{node_http_rpc.JSON_RPC_METHOD_NOT_FOUND, "Method not found", nil}
+	}
+}
+
+type testSubscriptions struct {
+	subscribed   chan *connection.SubscriptionNotification
+	unsubscribed chan *connection.SubscriptionNotification
+}
+
+func (subscriptions *testSubscriptions) Subscribe(subscription *connection.SubscriptionNotification) error {
+	subscriptions.subscribed <- subscription
+	return nil
+}
+
+func (subscriptions *testSubscriptions) Unsubscribe(subscription *connection.SubscriptionNotification) {
+	subscriptions.unsubscribed <- subscription
+}
+
+func createTestServer(t *testing.T) (*GRPCServer, *testInvoker, *testSubscriptions, NodeClient) {
+
+	invoker := &testInvoker{}
+	subscriptions := &testSubscriptions{make(chan *connection.SubscriptionNotification, 1), make(chan *connection.SubscriptionNotification, 1)}
+	server := newGRPCServer(invoker, subscriptions, rate_limiter.NewRateLimiter())
+
+	listener := bufconn.Listen(1 << 20)
+	go server.server.Serve(listener)
+	t.Cleanup(server.server.Stop)
+
+	conn, err := grpc.Dial("bufconn", grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithContextDialer(func(ctx context.Context, addr string) (net.Conn, error) {
+		return listener.DialContext(ctx)
+	}))
+	assert.Nil(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return server, invoker, subscriptions, NewNodeClient(conn)
+}
+
+func TestGRPCCall(t *testing.T) {
+
+	_, _, _, client := createTestServer(t)
+	ctx := context.Background()
+
+	reply, err := client.Call(ctx, &CallRequest{Method: "echo", Params: []byte(`{"value":"a"}`)})
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"value":"a"}`, string(reply.Result))
+
+	_, err = client.Call(ctx, &CallRequest{Method: "echo", Params: []byte(`[`)})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = client.Call(ctx, &CallRequest{Method: "wallet/get-addresses"})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err), "the wallet methods require auth")
+	assert.Equal(t, "Unauthorized", status.Convert(err).Message())
+
+	_, err = client.Call(ctx, &CallRequest{Method: "missing"})
+	assert.Equal(t, codes.Unimplemented, status.Code(err))
+}
+
+func TestGRPCTyped(t *testing.T) {
+
+	_, invoker, _, client := createTestServer(t)
+
+	hash := helpers.RandomBytes(cryptography.HashSize)
+	reply, err := client.GetBlock(context.Background(), &BlockRequest{Height: 5, Hash: hash})
+	assert.Nil(t, err)
+	assert.Equal(t, hash, reply.Block)
+	assert.Equal(t, [][]byte{{5}}, reply.TxHashes)
+	assert.Nil(t, invoker.principal)
+
+	//the credentials are read from the metadata
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer invalid")
+	_, err = client.GetBlock(ctx, &BlockRequest{Height: 5, Hash: hash})
+	assert.Nil(t, err)
+
+	_, err = client.GetChain(context.Background(), &ChainRequest{})
+	assert.Equal(t, codes.Unimplemented, status.Code(err))
+}
+
+func TestGRPCSubscribeBlocks(t *testing.T) {
+
+	server, _, _, client := createTestServer(t)
+	server.publishBlock(&api_common.APIBlockchain{Height: 1, Hash: "a"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream, err := client.SubscribeBlocks(ctx, &SubscribeBlocksRequest{})
+	assert.Nil(t, err)
+
+	chain, err := stream.Recv()
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(1), chain.Height, "the stream starts with the current chain")
+
+	server.publishBlock(&api_common.APIBlockchain{Height: 2, Hash: "b"})
+	chain, err = stream.Recv()
+	assert.Nil(t, err)
+	assert.Equal(t, "b", chain.Hash)
+
+	cancel()
+	assert.Eventually(t, func() bool {
+		server.lock.Lock()
+		defer server.lock.Unlock()
+		return server.streamsCount == 0 && len(server.blockStreams) == 0 && len(server.clientStreams) == 0
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestGRPCStreamsLimit(t *testing.T) {
+
+	server, _, _, client := createTestServer(t)
+	server.publishBlock(&api_common.APIBlockchain{Height: 1, Hash: "a"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	for i := 0; i < GRPC_MAX_CLIENT_STREAMS; i++ {
+		stream, err := client.SubscribeBlocks(ctx, &SubscribeBlocksRequest{})
+		assert.Nil(t, err)
+		_, err = stream.Recv()
+		assert.Nil(t, err)
+	}
+
+	stream, err := client.SubscribeBlocks(ctx, &SubscribeBlocksRequest{})
+	assert.Nil(t, err)
+	_, err = stream.Recv()
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err), "every client has its own limit")
+
+	server.lock.Lock()
+	assert.Equal(t, GRPC_MAX_CLIENT_STREAMS, server.streamsCount)
+	server.lock.Unlock()
+}
+
+func TestGRPCSubscribeAccount(t *testing.T) {
+
+	_, _, subscriptions, client := createTestServer(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	publicKey := helpers.RandomBytes(cryptography.PublicKeySize)
+	stream, err := client.SubscribeAccount(ctx, &SubscribeAccountRequest{PublicKey: publicKey, Type: uint64(api_types.SUBSCRIPTION_ACCOUNT), ReturnType: uint64(api_types.RETURN_JSON)})
+	assert.Nil(t, err)
+
+	subscription := <-subscriptions.subscribed
+	assert.Equal(t, publicKey, subscription.Subscription.Key)
+
+	subscription.Webhook.Notify(&api_types.APISubscriptionNotification{api_types.SUBSCRIPTION_ACCOUNT, publicKey, []byte(`{}`), nil})
+
+	notification, err := stream.Recv()
+	assert.Nil(t, err)
+	assert.Equal(t, publicKey, notification.Key)
+	assert.Equal(t, []byte(`{}`), notification.Data)
+
+	cancel()
+	assert.Equal(t, subscription, <-subscriptions.unsubscribed)
+
+	stream, err = client.SubscribeAccount(context.Background(), &SubscribeAccountRequest{PublicKey: publicKey, Type: uint64(api_types.SUBSCRIPTION_ASSET), ReturnType: uint64(api_types.RETURN_JSON)})
+	assert.Nil(t, err)
+	_, err = stream.Recv()
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
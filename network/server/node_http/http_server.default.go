@@ -10,6 +10,9 @@ import (
 	"net/http"
 	"net/url"
 	"pandora-pay/config"
+	"pandora-pay/config/config_auth"
+	"pandora-pay/network/api/api_common/api_types"
+	"pandora-pay/network/rate_limiter"
 )
 
 func (server *HttpServer) get(w http.ResponseWriter, req *http.Request) {
@@ -31,7 +34,33 @@ func (server *HttpServer) get(w http.ResponseWriter, req *http.Request) {
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
-		output, err = callback(args)
+
+		if token := api_types.GetBearerToken(req.Header.Get("Authorization")); token != "" {
+			args.Set("token", token)
+		}
+
+		//the credentials are verified once, after the login is rate limited
+		var principal *config_auth.Principal
+		if token := args.Get("token"); token != "" {
+			principal = config_auth.AuthenticateToken(token)
+		} else if args.Get("user") != "" {
+			if err = server.RateLimiter.AllowLogin(req.RemoteAddr, args.Get("user"), args.Get("pass")); err != nil {
+				http.Error(w, err.Error(), http.StatusTooManyRequests)
+				return
+			}
+			principal = config_auth.Authenticate(args.Get("user"), args.Get("pass"))
+		}
+
+		user := ""
+		if principal != nil {
+			user = principal.Username
+		}
+		if err = server.RateLimiter.AllowRoute(rate_limiter.GetClientKey(req.RemoteAddr, user), req.URL.Path); err != nil {
+			http.Error(w, err.Error(), http.StatusTooManyRequests)
+			return
+		}
+
+		output, err = callback(args, principal)
 	} else {
 		err = errors.New("Unknown request")
 	}
@@ -71,11 +100,19 @@ func (server *HttpServer) post(w http.ResponseWriter, req *http.Request) {
 
 	callback := server.PostMap[req.URL.Path]
 	if callback != nil {
-		output, err = callback(req.Body)
+		if err = server.RateLimiter.AllowRoute(rate_limiter.GetClientKey(req.RemoteAddr, ""), req.URL.Path); err != nil {
+			http.Error(w, err.Error(), http.StatusTooManyRequests)
+			return
+		}
+		output, err = callback(req)
 	} else {
 		err = errors.New("Unknown request")
 	}
 
+	if errors.Is(err, rate_limiter.ErrRateLimited) {
+		http.Error(w, err.Error(), http.StatusTooManyRequests)
+		return
+	}
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
@@ -103,6 +140,8 @@ func (server *HttpServer) GetHttpHandler() *http.Handler {
 	mux := http.NewServeMux()
 
 	mux.HandleFunc("/ws", server.websocketServer.HandleUpgradeConnection)
+	mux.Handle("/rpc/api/v1", server.RPC)
+	mux.Handle("/events", server.events)
 
 	if config.FAUCET_TESTNET_ENABLED {
 		fs := http.FileServer(http.Dir("../../../static/challenge"))
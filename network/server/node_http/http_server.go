@@ -1,9 +1,11 @@
 package node_http
 
 import (
-	"io"
+	"net/http"
 	"net/url"
 	"pandora-pay/blockchain"
+	"pandora-pay/blockchain/forging"
+	"pandora-pay/config/config_auth"
 	"pandora-pay/mempool"
 	"pandora-pay/network/api/api_common"
 	"pandora-pay/network/api/api_http"
@@ -11,6 +13,8 @@ import (
 	"pandora-pay/network/banned_nodes"
 	"pandora-pay/network/connected_nodes"
 	"pandora-pay/network/known_nodes"
+	"pandora-pay/network/rate_limiter"
+	"pandora-pay/network/server/node_http_events"
 	"pandora-pay/network/server/node_http_rpc"
 	"pandora-pay/network/websocks"
 	"pandora-pay/settings"
@@ -25,35 +29,37 @@ type HttpServer struct {
 	Api             *api_http.API
 	ApiWebsockets   *api_websockets.APIWebsockets
 	ApiStore        *api_common.APIStore
-	GetMap          map[string]func(values url.Values) (any, error)
-	PostMap         map[string]func(values io.ReadCloser) (any, error)
+	RateLimiter     *rate_limiter.RateLimiter
+	RPC             *node_http_rpc.HTTPServerRPC
+	events          *node_http_events.HTTPServerEvents
+	GetMap          map[string]func(values url.Values, principal *config_auth.Principal) (any, error)
+	PostMap         map[string]func(req *http.Request) (any, error)
 }
 
-func NewHttpServer(chain *blockchain.Blockchain, settings *settings.Settings, connectedNodes *connected_nodes.ConnectedNodes, bannedNodes *banned_nodes.BannedNodes, knownNodes *known_nodes.KnownNodes, mempool *mempool.Mempool, wallet *wallet.Wallet, txsValidator *txs_validator.TxsValidator, txsBuilder *txs_builder.TxsBuilder) (*HttpServer, error) {
+func NewHttpServer(chain *blockchain.Blockchain, settings *settings.Settings, connectedNodes *connected_nodes.ConnectedNodes, bannedNodes *banned_nodes.BannedNodes, knownNodes *known_nodes.KnownNodes, mempool *mempool.Mempool, forging *forging.Forging, wallet *wallet.Wallet, txsValidator *txs_validator.TxsValidator, txsBuilder *txs_builder.TxsBuilder) (*HttpServer, error) {
 
 	apiStore := api_common.NewAPIStore(chain)
-	apiCommon, err := api_common.NewAPICommon(knownNodes, mempool, chain, wallet, txsValidator, txsBuilder, apiStore)
+	apiCommon, err := api_common.NewAPICommon(knownNodes, mempool, chain, forging, wallet, txsValidator, txsBuilder, apiStore)
 	if err != nil {
 		return nil, err
 	}
 
-	apiWebsockets := api_websockets.NewWebsocketsAPI(apiStore, apiCommon, chain, settings, mempool, txsValidator)
 	api := api_http.NewAPI(apiStore, apiCommon, chain)
+	apiWebsockets := api_websockets.NewWebsocketsAPI(apiStore, apiCommon, chain, settings, mempool, txsValidator, api.Routes)
 
-	websockets := websocks.NewWebsockets(chain, mempool, settings, connectedNodes, knownNodes, bannedNodes, api, apiWebsockets)
+	websockets := websocks.NewWebsockets(chain, mempool, wallet, settings, connectedNodes, knownNodes, bannedNodes, api, apiWebsockets, apiCommon.DelegatorNode, apiCommon.Webhooks)
 
 	server := &HttpServer{
 		websocketServer: websocks.NewWebsocketServer(websockets, connectedNodes, knownNodes),
 		Websockets:      websockets,
-		GetMap:          make(map[string]func(values url.Values) (any, error)),
-		PostMap:         make(map[string]func(values io.ReadCloser) (any, error)),
+		GetMap:          make(map[string]func(values url.Values, principal *config_auth.Principal) (any, error)),
+		PostMap:         make(map[string]func(req *http.Request) (any, error)),
 		Api:             api,
 		ApiWebsockets:   apiWebsockets,
 		ApiStore:        apiStore,
-	}
-
-	if err = node_http_rpc.InitializeRPC(apiCommon); err != nil {
-		return nil, err
+		RateLimiter:     apiCommon.RateLimiter,
+		RPC:             node_http_rpc.NewHTTPServerRPC(api, apiCommon.RateLimiter),
+		events:          node_http_events.NewHTTPServerEvents(chain, mempool, apiCommon.RateLimiter),
 	}
 
 	return server, nil
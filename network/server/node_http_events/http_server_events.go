@@ -0,0 +1,328 @@
+package node_http_events
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"pandora-pay/blockchain"
+	"pandora-pay/blockchain/blockchain_sync"
+	"pandora-pay/blockchain/blockchain_types"
+	"pandora-pay/helpers"
+	"pandora-pay/mempool"
+	"pandora-pay/network/api/api_common"
+	"pandora-pay/network/rate_limiter"
+	"pandora-pay/recovery"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	EVENTS_HISTORY            = 1000 //events kept to resume the streams using Last-Event-ID
+	EVENTS_CLIENT_BUFFER      = 256  //a client that falls behind is disconnected and it must resume
+	EVENTS_MAX_CLIENTS        = 1000
+	EVENTS_MAX_CLIENT_STREAMS = 10 //streams of every IP
+	EVENTS_KEEP_ALIVE         = 15 * time.Second
+	EVENTS_RETRY              = 3000 //milliseconds the browser waits before reconnecting
+)
+
+type EventType string
+
+const (
+	EVENT_BLOCK   EventType = "block"
+	EVENT_SYNC    EventType = "sync"
+	EVENT_MEMPOOL EventType = "mempool"
+	EVENT_RESET   EventType = "reset" //the stream can't be resumed as the events were lost
+)
+
+type Event struct {
+	Id   uint64
+	Type EventType
+	Data []byte
+	keys map[string]bool //accounts of a mempool tx
+}
+
+type EventMempoolTx struct {
+	Action               string         `json:"action"` //add or remove
+	Hash                 helpers.Base64 `json:"hash"`
+	IncludedInBlockchain bool           `json:"includedInBlockchain,omitempty"`
+}
+
+type eventsClient struct {
+	clientKey string
+	filter    *eventsFilter
+	cn        chan *Event
+}
+
+type HTTPServerEvents struct {
+	chain       *blockchain.Blockchain
+	rateLimiter *rate_limiter.RateLimiter
+	history     []*Event
+	nextId      uint64
+	clients     map[*eventsClient]bool
+	clientsKeys map[string]int //streams of every client key
+	lock        *sync.Mutex
+}
+
+//publish stores the event in the history and sends it to the clients. Slow clients are disconnected instead of blocking
+func (server *HTTPServerEvents) publish(eventType EventType, data any, keys map[string]bool) {
+
+	bytes, err := json.Marshal(data)
+	if err != nil {
+		return
+	}
+
+	server.lock.Lock()
+	defer server.lock.Unlock()
+
+	event := &Event{server.nextId, eventType, bytes, keys}
+	server.nextId += 1
+
+	server.history = append(server.history, event)
+	if len(server.history) > EVENTS_HISTORY {
+		server.history = server.history[len(server.history)-EVENTS_HISTORY:]
+	}
+
+	for client := range server.clients {
+		if !client.filter.matches(event) {
+			continue
+		}
+		select {
+		case client.cn <- event:
+		default:
+			server.removeClient(client)
+		}
+	}
+}
+
+//subscribe registers the client. When resuming, it returns the events after lastEventId or reset if they are no longer available
+func (server *HTTPServerEvents) subscribe(clientKey string, filter *eventsFilter, resume bool, lastEventId uint64) (client *eventsClient, replay []*Event, reset bool, err error) {
+
+	server.lock.Lock()
+	defer server.lock.Unlock()
+
+	if len(server.clients) >= EVENTS_MAX_CLIENTS {
+		return nil, nil, false, fmt.Errorf("Too many clients")
+	}
+	if server.clientsKeys[clientKey] >= EVENTS_MAX_CLIENT_STREAMS {
+		return nil, nil, false, fmt.Errorf("Too many streams of the client")
+	}
+
+	if resume {
+		oldest := server.nextId
+		if len(server.history) > 0 {
+			oldest = server.history[0].Id
+		}
+		if lastEventId >= server.nextId || lastEventId+1 < oldest {
+			reset = true
+		} else {
+			for _, event := range server.history {
+				if event.Id > lastEventId && filter.matches(event) {
+					replay = append(replay, event)
+				}
+			}
+		}
+	}
+
+	client = &eventsClient{clientKey, filter, make(chan *Event, EVENTS_CLIENT_BUFFER)}
+	server.clients[client] = true
+	server.clientsKeys[clientKey] += 1
+	return
+}
+
+//must be locked before
+func (server *HTTPServerEvents) removeClient(client *eventsClient) {
+	if !server.clients[client] {
+		return
+	}
+	delete(server.clients, client)
+	if server.clientsKeys[client.clientKey] -= 1; server.clientsKeys[client.clientKey] == 0 {
+		delete(server.clientsKeys, client.clientKey)
+	}
+	close(client.cn)
+}
+
+func (server *HTTPServerEvents) unsubscribe(client *eventsClient) {
+	server.lock.Lock()
+	defer server.lock.Unlock()
+
+	server.removeClient(client)
+}
+
+func writeEvent(w http.ResponseWriter, event *Event) error {
+	var err error
+	if event.Id != 0 {
+		_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.Id, event.Type, event.Data)
+	} else {
+		_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, event.Data)
+	}
+	return err
+}
+
+func newSnapshotEvent(eventType EventType, data any) *Event {
+	bytes, _ := json.Marshal(data)
+	return &Event{0, eventType, bytes, nil}
+}
+
+func (server *HTTPServerEvents) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+
+	if req.Method != http.MethodGet {
+		http.Error(w, "SSE requires GET", http.StatusMethodNotAllowed)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	clientKey := rate_limiter.GetClientKey(req.RemoteAddr, "")
+	if err := server.rateLimiter.AllowRoute(clientKey, req.URL.Path); err != nil {
+		http.Error(w, err.Error(), http.StatusTooManyRequests)
+		return
+	}
+
+	query := req.URL.Query()
+
+	filter, err := parseEventsFilter(query)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	//browsers send the header when reconnecting. The query argument is used to resume manually
+	lastEventIdStr := req.Header.Get("Last-Event-ID")
+	if lastEventIdStr == "" {
+		lastEventIdStr = query.Get("lastEventId")
+	}
+
+	var lastEventId uint64
+	resume := lastEventIdStr != ""
+	if resume {
+		if lastEventId, err = strconv.ParseUint(lastEventIdStr, 10, 64); err != nil {
+			http.Error(w, "Invalid Last-Event-ID", http.StatusBadRequest)
+			return
+		}
+	}
+
+	client, replay, reset, err := server.subscribe(clientKey, filter, resume, lastEventId)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	defer server.unsubscribe(client)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	if _, err = fmt.Fprintf(w, "retry: %d\n\n", EVENTS_RETRY); err != nil {
+		return
+	}
+
+	if reset {
+		replay = []*Event{newSnapshotEvent(EVENT_RESET, struct{}{})}
+	}
+
+	//new streams and reset streams start with the current state
+	if !resume || reset {
+		if filter.types == nil || filter.types[EVENT_BLOCK] {
+			replay = append(replay, newSnapshotEvent(EVENT_BLOCK, api_common.NewAPIBlockchain(server.chain.GetChainData())))
+		}
+		if filter.types == nil || filter.types[EVENT_SYNC] {
+			replay = append(replay, newSnapshotEvent(EVENT_SYNC, server.chain.Sync.GetSyncData()))
+		}
+	}
+
+	for _, event := range replay {
+		if err = writeEvent(w, event); err != nil {
+			return
+		}
+	}
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(EVENTS_KEEP_ALIVE)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case event, ok := <-client.cn:
+			if !ok {
+				return
+			}
+			if err = writeEvent(w, event); err != nil {
+				return
+			}
+		case <-keepAlive.C:
+			if _, err = fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+		case <-req.Context().Done():
+			return
+		}
+		flusher.Flush()
+	}
+}
+
+func (server *HTTPServerEvents) processUpdates(mempool *mempool.Mempool) {
+
+	updateNewChainDataUpdateCn := server.chain.UpdateNewChainDataUpdate.AddListener()
+	defer server.chain.UpdateNewChainDataUpdate.RemoveChannel(updateNewChainDataUpdateCn)
+
+	updateSyncCn := server.chain.Sync.UpdateSyncMulticast.AddListener()
+	defer server.chain.Sync.UpdateSyncMulticast.RemoveChannel(updateSyncCn)
+
+	updateMempoolTransactionsCn := mempool.Txs.UpdateMempoolTransactions.AddListener()
+	defer mempool.Txs.UpdateMempoolTransactions.RemoveChannel(updateMempoolTransactionsCn)
+
+	var chainDataUpdate *blockchain.BlockchainDataUpdate
+	var syncData *blockchain_sync.BlockchainSyncData
+	var txUpdate *blockchain_types.MempoolTransactionUpdate
+	var ok bool
+
+	for {
+		select {
+		case chainDataUpdate, ok = <-updateNewChainDataUpdateCn:
+			if !ok {
+				return
+			}
+			server.publish(EVENT_BLOCK, api_common.NewAPIBlockchain(chainDataUpdate.Update), nil)
+		case syncData, ok = <-updateSyncCn:
+			if !ok {
+				return
+			}
+			server.publish(EVENT_SYNC, syncData, nil)
+		case txUpdate, ok = <-updateMempoolTransactionsCn:
+			if !ok {
+				return
+			}
+			action := "remove"
+			if txUpdate.Inserted {
+				action = "add"
+			}
+			server.publish(EVENT_MEMPOOL, &EventMempoolTx{action, txUpdate.Tx.Bloom.Hash, txUpdate.IncludedInBlockchainNotification}, txUpdate.Keys)
+		}
+	}
+}
+
+func NewHTTPServerEvents(chain *blockchain.Blockchain, mempool *mempool.Mempool, rateLimiter *rate_limiter.RateLimiter) *HTTPServerEvents {
+
+	server := &HTTPServerEvents{
+		chain,
+		rateLimiter,
+		nil,
+		uint64(time.Now().UnixNano()), //ids keep increasing after restarts, so old ids are detected as lost
+		map[*eventsClient]bool{},
+		map[string]int{},
+		&sync.Mutex{},
+	}
+
+	recovery.SafeGo(func() {
+		server.processUpdates(mempool)
+	})
+
+	return server
+}
@@ -0,0 +1,75 @@
+package node_http_events
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+//eventsFilter selects the events of a client. A nil map accepts everything
+type eventsFilter struct {
+	types    map[EventType]bool
+	accounts map[string]bool //mempool txs are sent only if they involve one of the accounts
+}
+
+func (filter *eventsFilter) matches(event *Event) bool {
+
+	if event.Type == EVENT_RESET {
+		return true
+	}
+	if filter.types != nil && !filter.types[event.Type] {
+		return false
+	}
+	if filter.accounts != nil && event.Type == EVENT_MEMPOOL {
+		for key := range filter.accounts {
+			if event.keys[key] {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func splitList(str string) (list []string) {
+	for _, it := range strings.Split(str, ",") {
+		if it = strings.TrimSpace(it); it != "" {
+			list = append(list, it)
+		}
+	}
+	return
+}
+
+//parseEventsFilter reads ?events=block,sync,mempool&accounts=base64,base64
+func parseEventsFilter(query url.Values) (*eventsFilter, error) {
+
+	filter := &eventsFilter{}
+
+	if str := query.Get("events"); str != "" {
+		filter.types = map[EventType]bool{}
+		for _, it := range splitList(str) {
+			eventType := EventType(it)
+			switch eventType {
+			case EVENT_BLOCK, EVENT_SYNC, EVENT_MEMPOOL:
+				filter.types[eventType] = true
+			default:
+				return nil, fmt.Errorf("Invalid event %s", it)
+			}
+		}
+	}
+
+	if str := query.Get("accounts"); str != "" {
+		filter.accounts = map[string]bool{}
+		for _, it := range splitList(str) {
+			publicKey, err := base64.StdEncoding.DecodeString(it)
+			if err != nil {
+				return nil, errors.New("Invalid account. Public keys must be base64")
+			}
+			filter.accounts[string(publicKey)] = true
+		}
+	}
+
+	return filter, nil
+}
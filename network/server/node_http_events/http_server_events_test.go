@@ -0,0 +1,127 @@
+package node_http_events
+
+import (
+	"encoding/base64"
+	"github.com/stretchr/testify/assert"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+)
+
+func createTestServer() *HTTPServerEvents {
+	return &HTTPServerEvents{nil, nil, nil, 100, map[*eventsClient]bool{}, map[string]int{}, &sync.Mutex{}}
+}
+
+func TestEventsFilter(t *testing.T) {
+
+	filter, err := parseEventsFilter(url.Values{"events": {"block, mempool"}, "accounts": {base64.StdEncoding.EncodeToString([]byte("key"))}})
+	assert.Nil(t, err)
+
+	assert.True(t, filter.matches(&Event{Type: EVENT_BLOCK}))
+	assert.False(t, filter.matches(&Event{Type: EVENT_SYNC}))
+	assert.True(t, filter.matches(&Event{Type: EVENT_MEMPOOL, keys: map[string]bool{"key": true}}))
+	assert.False(t, filter.matches(&Event{Type: EVENT_MEMPOOL, keys: map[string]bool{"other": true}}))
+	assert.True(t, filter.matches(&Event{Type: EVENT_RESET}))
+
+	_, err = parseEventsFilter(url.Values{"events": {"blocks"}})
+	assert.NotNil(t, err)
+
+	filter, err = parseEventsFilter(url.Values{})
+	assert.Nil(t, err)
+	assert.True(t, filter.matches(&Event{Type: EVENT_MEMPOOL}))
+}
+
+func TestEventsResume(t *testing.T) {
+
+	server := createTestServer()
+	all := &eventsFilter{}
+
+	for i := 0; i < 3; i++ {
+		server.publish(EVENT_BLOCK, i, nil)
+	}
+	server.publish(EVENT_SYNC, true, nil)
+
+	client, replay, reset, err := server.subscribe("client", &eventsFilter{types: map[EventType]bool{EVENT_BLOCK: true}}, true, 100)
+	assert.Nil(t, err)
+	assert.False(t, reset)
+	assert.Equal(t, 2, len(replay))
+	assert.Equal(t, uint64(101), replay[0].Id)
+	assert.Equal(t, "2", string(replay[1].Data))
+	server.unsubscribe(client)
+
+	_, replay, reset, _ = server.subscribe("client", all, true, 103)
+	assert.False(t, reset)
+	assert.Empty(t, replay)
+
+	//ids of a previous run or from the future can't be resumed
+	_, _, reset, _ = server.subscribe("client", all, true, 50)
+	assert.True(t, reset)
+	_, _, reset, _ = server.subscribe("client", all, true, 104)
+	assert.True(t, reset)
+
+	for i := 0; i < EVENTS_HISTORY; i++ {
+		server.publish(EVENT_BLOCK, i, nil)
+	}
+	_, _, reset, _ = server.subscribe("client", all, true, 101)
+	assert.True(t, reset)
+}
+
+func TestEventsSlowClient(t *testing.T) {
+
+	server := createTestServer()
+
+	client, _, _, err := server.subscribe("client", &eventsFilter{}, false, 0)
+	assert.Nil(t, err)
+
+	for i := 0; i <= EVENTS_CLIENT_BUFFER; i++ {
+		server.publish(EVENT_BLOCK, i, nil)
+	}
+
+	assert.False(t, server.clients[client])
+
+	count := 0
+	for range client.cn {
+		count++
+	}
+	assert.Equal(t, EVENTS_CLIENT_BUFFER, count)
+
+	server.unsubscribe(client)
+}
+
+func TestEventsClientLimit(t *testing.T) {
+
+	server := createTestServer()
+
+	clients := make([]*eventsClient, EVENTS_MAX_CLIENT_STREAMS)
+	for i := range clients {
+		var err error
+		clients[i], _, _, err = server.subscribe("client", &eventsFilter{}, false, 0)
+		assert.Nil(t, err)
+	}
+
+	_, _, _, err := server.subscribe("client", &eventsFilter{}, false, 0)
+	assert.NotNil(t, err, "every client has its own limit")
+
+	other, _, _, err := server.subscribe("other", &eventsFilter{}, false, 0)
+	assert.Nil(t, err)
+	server.unsubscribe(other)
+
+	//the slow clients are disconnected and free their streams
+	for i := 0; i <= EVENTS_CLIENT_BUFFER; i++ {
+		server.publish(EVENT_BLOCK, i, nil)
+	}
+	assert.Empty(t, server.clientsKeys)
+
+	for _, client := range clients {
+		server.unsubscribe(client)
+	}
+	_, _, _, err = server.subscribe("client", &eventsFilter{}, false, 0)
+	assert.Nil(t, err)
+}
+
+func TestWriteEvent(t *testing.T) {
+	w := httptest.NewRecorder()
+	assert.Nil(t, writeEvent(w, &Event{Id: 7, Type: EVENT_SYNC, Data: []byte(`{"sync":true}`)}))
+	assert.Equal(t, "id: 7\nevent: sync\ndata: {\"sync\":true}\n\n", w.Body.String())
+}
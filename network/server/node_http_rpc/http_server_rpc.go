@@ -1,25 +1,224 @@
 package node_http_rpc
 
 import (
-	"github.com/gorilla/rpc"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
 	"net/http"
-	"pandora-pay/network/api/api_common"
+	"pandora-pay/config"
+	"pandora-pay/config/config_auth"
+	"pandora-pay/network/api/api_common/api_types"
+	"pandora-pay/network/api/api_http"
+	"pandora-pay/network/rate_limiter"
 )
 
-type HTTPServerRPCRoutes struct {
-	apiCommon *api_common.APICommon
+type rpcMethod func(decode func(args any) error, principal *config_auth.Principal) (any, error)
+
+//HTTPServerRPC is a JSON-RPC 2.0 endpoint. The methods have the same names as the HTTP routes
+type HTTPServerRPC struct {
+	methods     map[string]rpcMethod
+	rateLimiter *rate_limiter.RateLimiter
+}
+
+//newMethod decodes the params of a route. Malformed params are reported as JSON-RPC invalid params
+func newMethod(call func(decode func(args any) error, principal *config_auth.Principal) (interface{}, error)) rpcMethod {
+	return func(decode func(args any) error, principal *config_auth.Principal) (any, error) {
+		var decodeErr error
+		result, err := call(func(args any) error {
+			decodeErr = decode(args)
+			return decodeErr
+		}, principal)
+		if decodeErr != nil {
+			return nil, &JSONRPCError{JSON_RPC_INVALID_PARAMS, "Invalid params", decodeErr.Error()}
+		}
+		return result, err
+	}
+}
+
+//Authenticate accepts a Bearer token or Basic user and pass. The password is verified only after the login is allowed by the rate limiter
+func Authenticate(authorization, remoteAddr string, rateLimiter *rate_limiter.RateLimiter) (*config_auth.Principal, error) {
+	if token := api_types.GetBearerToken(authorization); token != "" {
+		return config_auth.AuthenticateToken(token), nil
+	}
+	if user, pass, ok := (&http.Request{Header: http.Header{"Authorization": {authorization}}}).BasicAuth(); ok {
+		if err := rateLimiter.AllowLogin(remoteAddr, user, pass); err != nil {
+			return nil, err
+		}
+		return config_auth.Authenticate(user, pass), nil
+	}
+	return nil, nil
+}
+
+//GetPrincipal authenticates the "Authorization" header
+func GetPrincipal(req *http.Request, rateLimiter *rate_limiter.RateLimiter) (*config_auth.Principal, error) {
+	return Authenticate(req.Header.Get("Authorization"), req.RemoteAddr, rateLimiter)
+}
+
+//JSONParams decodes the params of a JSON-RPC request
+func JSONParams(params json.RawMessage) func(args any) error {
+	return func(args any) error {
+		return decodeParams(params, args)
+	}
+}
+
+//Invoke runs a method with the rate limits and the errors of JSON-RPC. decode fills the arguments of the method, so the gRPC server shares the same handlers with its typed messages
+func (server *HTTPServerRPC) Invoke(name string, decode func(args any) error, clientKey string, principal *config_auth.Principal) (result any, rpcErr *JSONRPCError) {
+
+	defer func() {
+		if err := recover(); err != nil {
+			rpcErr = &JSONRPCError{JSON_RPC_INTERNAL_ERROR, "Internal error", fmt.Sprint(err)}
+		}
+	}()
+
+	method := server.methods[name]
+	if method == nil {
+		return nil, newJSONRPCError(JSON_RPC_METHOD_NOT_FOUND, "Method not found")
+	}
+
+	if err := server.rateLimiter.AllowRoute(clientKey, name); err != nil {
+		return nil, toJSONRPCError(err)
+	}
+
+	result, err := method(decode, principal)
+	if err != nil {
+		return nil, toJSONRPCError(err)
+	}
+	return result, nil
+}
+
+func (server *HTTPServerRPC) call(request *JSONRPCRequest, clientKey string, principal *config_auth.Principal) (any, *JSONRPCError) {
+	if request.JSONRPC != JSON_RPC_VERSION || request.Method == "" || !isValidId(request.Id) {
+		return nil, newJSONRPCError(JSON_RPC_INVALID_REQUEST, "Invalid Request")
+	}
+	return server.Invoke(request.Method, JSONParams(request.Params), clientKey, principal)
+}
+
+//process returns nil for notifications as they must not be answered
+func (server *HTTPServerRPC) process(data json.RawMessage, clientKey string, principal *config_auth.Principal) *JSONRPCResponse {
+
+	request := &JSONRPCRequest{}
+	if err := json.Unmarshal(data, request); err != nil {
+		return &JSONRPCResponse{JSON_RPC_VERSION, nil, newJSONRPCError(JSON_RPC_INVALID_REQUEST, "Invalid Request"), json.RawMessage("null")}
+	}
+
+	result, rpcErr := server.call(request, clientKey, principal)
+
+	if request.Id == nil && (rpcErr == nil || rpcErr.Code != JSON_RPC_INVALID_REQUEST) {
+		return nil
+	}
+
+	response := &JSONRPCResponse{JSONRPC: JSON_RPC_VERSION, Id: request.Id}
+	if response.Id == nil || !isValidId(response.Id) {
+		response.Id = json.RawMessage("null")
+	}
+
+	if rpcErr != nil {
+		response.Error = rpcErr
+		return response
+	}
+
+	out, err := json.Marshal(result)
+	if err != nil {
+		response.Error = &JSONRPCError{JSON_RPC_INTERNAL_ERROR, "Internal error", err.Error()}
+		return response
+	}
+	raw := json.RawMessage(out)
+	response.Result = &raw
+
+	return response
 }
 
-func InitializeRPC(apiCommon *api_common.APICommon) (err error) {
+func writeJSON(w http.ResponseWriter, data any) {
+	out, err := json.Marshal(data)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(out)
+}
+
+func (server *HTTPServerRPC) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+
+	if req.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "JSON-RPC requires POST", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, req.Body, int64(config.WEBSOCKETS_MAX_READ)))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+		return
+	}
 
-	s := rpc.NewServer()
+	principal, err := GetPrincipal(req, server.rateLimiter)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusTooManyRequests)
+		return
+	}
+
+	user := ""
+	if principal != nil {
+		user = principal.Username
+	}
+	clientKey := rate_limiter.GetClientKey(req.RemoteAddr, user)
 
-	s.RegisterCodec(NewUpCodec(), "application/json")
-	if err = s.RegisterService(apiCommon, "api"); err != nil {
+	body = bytes.TrimSpace(body)
+	if !json.Valid(body) {
+		writeJSON(w, &JSONRPCResponse{JSON_RPC_VERSION, nil, newJSONRPCError(JSON_RPC_PARSE_ERROR, "Parse error"), json.RawMessage("null")})
 		return
 	}
 
-	http.Handle("/rpc/api/v1", s)
+	if body[0] != '[' {
+		if response := server.process(body, clientKey, principal); response != nil {
+			writeJSON(w, response)
+		} else {
+			w.WriteHeader(http.StatusNoContent)
+		}
+		return
+	}
+
+	var batch []json.RawMessage
+	if err = json.Unmarshal(body, &batch); err != nil || len(batch) == 0 || len(batch) > JSON_RPC_MAX_BATCH {
+		writeJSON(w, &JSONRPCResponse{JSON_RPC_VERSION, nil, newJSONRPCError(JSON_RPC_INVALID_REQUEST, "Invalid Request"), json.RawMessage("null")})
+		return
+	}
+
+	responses := make([]*JSONRPCResponse, 0, len(batch))
+	for _, data := range batch {
+		if response := server.process(data, clientKey, principal); response != nil {
+			responses = append(responses, response)
+		}
+	}
+
+	if len(responses) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	writeJSON(w, responses)
+}
+
+//NewHTTPServerRPC serves the routes of the HTTP API. The info route is named "info" and every api_common.APICommon route is also served with the "api.GetX" name of the former gorilla/rpc endpoint
+func NewHTTPServerRPC(api *api_http.API, rateLimiter *rate_limiter.RateLimiter) *HTTPServerRPC {
+
+	server := &HTTPServerRPC{
+		methods:     map[string]rpcMethod{},
+		rateLimiter: rateLimiter,
+	}
+
+	for name, route := range api.Routes {
+		method := newMethod(route.Call)
+		if name == "" {
+			name = "info"
+		}
+		server.methods[name] = method
+		if route.Handler != "" {
+			server.methods["api."+route.Handler] = method
+		}
+	}
 
-	return
+	return server
 }
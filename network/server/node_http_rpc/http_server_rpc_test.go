@@ -0,0 +1,123 @@
+package node_http_rpc
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"pandora-pay/config/config_auth"
+	"pandora-pay/network/api/api_common"
+	"pandora-pay/network/api/api_http"
+	"pandora-pay/network/rate_limiter"
+	"strings"
+	"testing"
+)
+
+type testEchoRequest struct {
+	Value string `json:"value"`
+}
+
+type testEchoReply struct {
+	Value string `json:"value"`
+}
+
+func createTestServer() *HTTPServerRPC {
+	return &HTTPServerRPC{
+		map[string]rpcMethod{
+			"echo": newMethod(func(decode func(args any) error, principal *config_auth.Principal) (interface{}, error) {
+				args := &testEchoRequest{}
+				if err := decode(args); err != nil {
+					return nil, err
+				}
+				return &testEchoReply{args.Value}, nil
+			}),
+			"wallet/get-addresses": newMethod(func(decode func(args any) error, principal *config_auth.Principal) (interface{}, error) {
+				return nil, principal.Authorize(config_auth.ROLE_READ_ONLY)
+			}),
+		},
+		rate_limiter.NewRateLimiter(),
+	}
+}
+
+func postTestRPC(t *testing.T, server *HTTPServerRPC, body string) (int, string) {
+	req := httptest.NewRequest(http.MethodPost, "/rpc/api/v1", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+	return w.Code, w.Body.String()
+}
+
+func TestJSONRPC(t *testing.T) {
+
+	server := createTestServer()
+
+	_, out := postTestRPC(t, server, `{"jsonrpc":"2.0","method":"echo","params":{"value":"a"},"id":1}`)
+	assert.JSONEq(t, `{"jsonrpc":"2.0","result":{"value":"a"},"id":1}`, out)
+
+	_, out = postTestRPC(t, server, `{"jsonrpc":"2.0","method":"echo","params":[{"value":"b"}],"id":"x"}`)
+	assert.JSONEq(t, `{"jsonrpc":"2.0","result":{"value":"b"},"id":"x"}`, out)
+
+	code, out := postTestRPC(t, server, `{"jsonrpc":"2.0","method":"echo","params":{"value":"a"}}`)
+	assert.Equal(t, http.StatusNoContent, code, "notifications are not answered")
+	assert.Empty(t, out)
+
+	_, out = postTestRPC(t, server, `{"jsonrpc":"2.0","method":"echo","params":{"value":"a"},"id":null}`)
+	assert.JSONEq(t, `{"jsonrpc":"2.0","result":{"value":"a"},"id":null}`, out)
+
+	_, out = postTestRPC(t, server, `{"jsonrpc":"2.0","method":"echo",`)
+	assert.JSONEq(t, `{"jsonrpc":"2.0","error":{"code":-32700,"message":"Parse error"},"id":null}`, out)
+
+	_, out = postTestRPC(t, server, `{"jsonrpc":"1.0","method":"echo","id":1}`)
+	assert.JSONEq(t, `{"jsonrpc":"2.0","error":{"code":-32600,"message":"Invalid Request"},"id":1}`, out)
+
+	_, out = postTestRPC(t, server, `{"jsonrpc":"2.0","method":"missing","id":2}`)
+	assert.JSONEq(t, `{"jsonrpc":"2.0","error":{"code":-32601,"message":"Method not found"},"id":2}`, out)
+
+	_, out = postTestRPC(t, server, `{"jsonrpc":"2.0","method":"echo","params":[1,2],"id":3}`)
+	response := &JSONRPCResponse{}
+	assert.NoError(t, json.Unmarshal([]byte(out), response))
+	assert.Equal(t, JSON_RPC_INVALID_PARAMS, response.Error.Code)
+
+	_, out = postTestRPC(t, server, `{"jsonrpc":"2.0","method":"wallet/get-addresses","id":4}`)
+	assert.NoError(t, json.Unmarshal([]byte(out), response))
+	assert.Equal(t, JSON_RPC_UNAUTHORIZED, response.Error.Code)
+
+	_, out = postTestRPC(t, server, `[]`)
+	assert.JSONEq(t, `{"jsonrpc":"2.0","error":{"code":-32600,"message":"Invalid Request"},"id":null}`, out)
+
+	_, out = postTestRPC(t, server, `[
+		{"jsonrpc":"2.0","method":"echo","params":{"value":"1"},"id":1},
+		{"jsonrpc":"2.0","method":"echo","params":{"value":"2"}},
+		1,
+		{"jsonrpc":"2.0","method":"missing","id":"5"}
+	]`)
+	assert.JSONEq(t, `[
+		{"jsonrpc":"2.0","result":{"value":"1"},"id":1},
+		{"jsonrpc":"2.0","error":{"code":-32600,"message":"Invalid Request"},"id":null},
+		{"jsonrpc":"2.0","error":{"code":-32601,"message":"Method not found"},"id":"5"}
+	]`, out)
+
+	code, _ = postTestRPC(t, server, `[{"jsonrpc":"2.0","method":"echo"},{"jsonrpc":"2.0","method":"echo"}]`)
+	assert.Equal(t, http.StatusNoContent, code)
+}
+
+func TestJSONRPCRoutes(t *testing.T) {
+
+	api := api_http.NewAPI(nil, &api_common.APICommon{}, nil)
+	server := NewHTTPServerRPC(api, rate_limiter.NewRateLimiter())
+
+	for name := range api.Routes {
+		if name != "" {
+			assert.NotNil(t, server.methods[name], "route %s is served", name)
+		}
+	}
+	assert.NotNil(t, server.methods["info"])
+	assert.Nil(t, server.methods[""])
+
+	_, out := postTestRPC(t, server, `{"jsonrpc":"2.0","method":"ping","id":1}`)
+	assert.JSONEq(t, `{"jsonrpc":"2.0","result":{"ping":"pong"},"id":1}`, out)
+
+	_, out = postTestRPC(t, server, `{"jsonrpc":"2.0","method":"api.GetPing","params":[{}],"id":2}`)
+	assert.JSONEq(t, `{"jsonrpc":"2.0","result":{"ping":"pong"},"id":2}`, out, "the gorilla/rpc names are kept")
+
+	assert.NotNil(t, server.methods["api.GetWalletAddresses"])
+}
@@ -0,0 +1,116 @@
+package node_http_rpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"pandora-pay/config/config_auth"
+	"pandora-pay/network/rate_limiter"
+)
+
+const (
+	JSON_RPC_VERSION   = "2.0"
+	JSON_RPC_MAX_BATCH = 100
+)
+
+//standard JSON-RPC 2.0 codes. -32000 to -32099 are reserved for the implementation
+const (
+	JSON_RPC_PARSE_ERROR         = -32700
+	JSON_RPC_INVALID_REQUEST     = -32600
+	JSON_RPC_METHOD_NOT_FOUND    = -32601
+	JSON_RPC_INVALID_PARAMS      = -32602
+	JSON_RPC_INTERNAL_ERROR      = -32603
+	JSON_RPC_SERVER_ERROR        = -32000
+	JSON_RPC_UNAUTHORIZED        = -32001
+	JSON_RPC_PERMISSION_DENIED   = -32003
+	JSON_RPC_RATE_LIMIT_EXCEEDED = -32005
+)
+
+type JSONRPCRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Id      json.RawMessage `json:"id,omitempty"` //missing for notifications
+}
+
+type JSONRPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    any    `json:"data,omitempty"`
+}
+
+func (err *JSONRPCError) Error() string {
+	return err.Message
+}
+
+//JSONRPCResponse has either the result or the error. Result is a pointer to keep a null result in the response
+type JSONRPCResponse struct {
+	JSONRPC string           `json:"jsonrpc"`
+	Result  *json.RawMessage `json:"result,omitempty"`
+	Error   *JSONRPCError    `json:"error,omitempty"`
+	Id      json.RawMessage  `json:"id"`
+}
+
+func newJSONRPCError(code int, message string) *JSONRPCError {
+	return &JSONRPCError{code, message, nil}
+}
+
+//toJSONRPCError maps the errors returned by the API to JSON-RPC error objects
+func toJSONRPCError(err error) *JSONRPCError {
+
+	var rpcErr *JSONRPCError
+	switch {
+	case errors.As(err, &rpcErr):
+		return rpcErr
+	case errors.Is(err, config_auth.ErrInvalidCredentials):
+		return newJSONRPCError(JSON_RPC_UNAUTHORIZED, err.Error())
+	case errors.Is(err, config_auth.ErrPermissionDenied):
+		return newJSONRPCError(JSON_RPC_PERMISSION_DENIED, err.Error())
+	case errors.Is(err, rate_limiter.ErrRateLimited):
+		return newJSONRPCError(JSON_RPC_RATE_LIMIT_EXCEEDED, err.Error())
+	default:
+		return newJSONRPCError(JSON_RPC_SERVER_ERROR, err.Error())
+	}
+}
+
+//decodeParams accepts the params by name or an array with a single object
+func decodeParams(params json.RawMessage, out any) error {
+
+	params = bytes.TrimSpace(params)
+	if len(params) == 0 || bytes.Equal(params, []byte("null")) {
+		return nil
+	}
+
+	if params[0] == '[' {
+		var list []json.RawMessage
+		if err := json.Unmarshal(params, &list); err != nil {
+			return err
+		}
+		if len(list) == 0 {
+			return nil
+		}
+		if len(list) > 1 {
+			return errors.New("params by position must be an array with a single object")
+		}
+		params = list[0]
+	}
+
+	if params[0] != '{' {
+		return errors.New("params must be an object")
+	}
+
+	return json.Unmarshal(params, out)
+}
+
+//isValidId checks the id is a string, a number or null
+func isValidId(id json.RawMessage) bool {
+	if len(id) == 0 {
+		return true
+	}
+	switch id[0] {
+	case '"', 'n', '-', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+		return true
+	default:
+		return false
+	}
+}
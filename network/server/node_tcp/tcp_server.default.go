@@ -12,6 +12,7 @@ import (
 	"net/url"
 	"os"
 	"pandora-pay/blockchain"
+	"pandora-pay/blockchain/forging"
 	"pandora-pay/config"
 	"pandora-pay/config/globals"
 	"pandora-pay/gui"
@@ -19,6 +20,7 @@ import (
 	"pandora-pay/network/banned_nodes"
 	"pandora-pay/network/connected_nodes"
 	"pandora-pay/network/known_nodes"
+	"pandora-pay/network/server/node_grpc"
 	"pandora-pay/network/server/node_http"
 	"pandora-pay/recovery"
 	"pandora-pay/settings"
@@ -36,9 +38,10 @@ type TcpServer struct {
 	URL         *url.URL
 	tcpListener net.Listener
 	HttpServer  *node_http.HttpServer
+	GRPCServer  *node_grpc.GRPCServer
 }
 
-func NewTcpServer(connectedNodes *connected_nodes.ConnectedNodes, bannedNodes *banned_nodes.BannedNodes, knownNodes *known_nodes.KnownNodes, settings *settings.Settings, chain *blockchain.Blockchain, mempool *mempool.Mempool, wallet *wallet.Wallet, txsValidator *txs_validator.TxsValidator, txsBuilder *txs_builder.TxsBuilder) (*TcpServer, error) {
+func NewTcpServer(connectedNodes *connected_nodes.ConnectedNodes, bannedNodes *banned_nodes.BannedNodes, knownNodes *known_nodes.KnownNodes, settings *settings.Settings, chain *blockchain.Blockchain, mempool *mempool.Mempool, forging *forging.Forging, wallet *wallet.Wallet, txsValidator *txs_validator.TxsValidator, txsBuilder *txs_builder.TxsBuilder) (*TcpServer, error) {
 
 	server := &TcpServer{}
 
@@ -162,10 +165,23 @@ func NewTcpServer(connectedNodes *connected_nodes.ConnectedNodes, bannedNodes *b
 
 	gui.GUI.InfoUpdate("TCP", address+":"+port)
 
-	if server.HttpServer, err = node_http.NewHttpServer(chain, settings, connectedNodes, bannedNodes, knownNodes, mempool, wallet, txsValidator, txsBuilder); err != nil {
+	if server.HttpServer, err = node_http.NewHttpServer(chain, settings, connectedNodes, bannedNodes, knownNodes, mempool, forging, wallet, txsValidator, txsBuilder); err != nil {
 		return nil, err
 	}
 
+	//the gRPC server uses the handlers of the JSON-RPC server and the websockets subscriptions
+	if globals.Arguments["--grpc-server-port"] != nil {
+
+		grpcPortNumber, err := strconv.Atoi(globals.Arguments["--grpc-server-port"].(string))
+		if err != nil {
+			return nil, errors.New("gRPC port is not a valid port number")
+		}
+
+		if server.GRPCServer, err = node_grpc.NewGRPCServer(strconv.Itoa(grpcPortNumber+config.INSTANCE_ID), tlsConfig, server.HttpServer.RPC, chain, server.HttpServer.Websockets, server.HttpServer.RateLimiter); err != nil {
+			return nil, err
+		}
+	}
+
 	recovery.SafeGo(func() {
 		if err := http.Serve(server.tcpListener, *server.HttpServer.GetHttpHandler()); err != nil {
 			gui.GUI.Error("Error opening HTTP server", err)
@@ -5,6 +5,7 @@ package node_tcp
 
 import (
 	"pandora-pay/blockchain"
+	"pandora-pay/blockchain/forging"
 	"pandora-pay/mempool"
 	"pandora-pay/network/banned_nodes"
 	"pandora-pay/network/connected_nodes"
@@ -20,11 +21,11 @@ type TcpServer struct {
 	HttpServer *node_http.HttpServer
 }
 
-func NewTcpServer(connectedNodes *connected_nodes.ConnectedNodes, bannedNodes *banned_nodes.BannedNodes, knownNodes *known_nodes.KnownNodes, settings *settings.Settings, chain *blockchain.Blockchain, mempool *mempool.Mempool, wallet *wallet.Wallet, txsValidator *txs_validator.TxsValidator, txsBuilder *txs_builder.TxsBuilder) (*TcpServer, error) {
+func NewTcpServer(connectedNodes *connected_nodes.ConnectedNodes, bannedNodes *banned_nodes.BannedNodes, knownNodes *known_nodes.KnownNodes, settings *settings.Settings, chain *blockchain.Blockchain, mempool *mempool.Mempool, forging *forging.Forging, wallet *wallet.Wallet, txsValidator *txs_validator.TxsValidator, txsBuilder *txs_builder.TxsBuilder) (*TcpServer, error) {
 
 	server := &TcpServer{}
 	var err error
-	if server.HttpServer, err = node_http.NewHttpServer(chain, settings, connectedNodes, bannedNodes, knownNodes, mempool, wallet, txsValidator, txsBuilder); err != nil {
+	if server.HttpServer, err = node_http.NewHttpServer(chain, settings, connectedNodes, bannedNodes, knownNodes, mempool, forging, wallet, txsValidator, txsBuilder); err != nil {
 		return nil, err
 	}
 
@@ -7,6 +7,7 @@ import (
 	"github.com/tevino/abool"
 	"github.com/vmihailenco/msgpack/v5"
 	"pandora-pay/config"
+	"pandora-pay/config/config_auth"
 	"pandora-pay/helpers"
 	"pandora-pay/helpers/generics"
 	"pandora-pay/network/known_nodes/known_node"
@@ -29,7 +30,7 @@ const (
 var uuidGenerator uint32 //use atomic
 
 type AdvancedConnection struct {
-	Authenticated            *abool.AtomicBool
+	Principal                *generics.Value[*config_auth.Principal] //nil when it is not authenticated
 	UUID                     advanced_connection_types.UUID
 	Conn                     *websock.Conn
 	Handshake                *ConnectionHandshake
@@ -51,6 +52,14 @@ type AdvancedConnection struct {
 	onIncreaseKnownNodeScore func(knownNode *known_node.KnownNodeScored, delta int32, isServer bool) bool
 }
 
+//GetUsername returns the name of the authenticated principal or "" if the connection is not authenticated
+func (c *AdvancedConnection) GetUsername() string {
+	if principal := c.Principal.Load(); principal != nil {
+		return principal.Username
+	}
+	return ""
+}
+
 func (c *AdvancedConnection) GetTimeout() time.Duration {
 	return config.WEBSOCKETS_TIMEOUT
 }
@@ -323,17 +332,20 @@ func (c *AdvancedConnection) IncreaseKnownNodeScore() {
 
 }
 
-func NewAdvancedConnection(conn *websock.Conn, remoteAddr string, knownNode *known_node.KnownNodeScored, getMap map[string]func(conn *AdvancedConnection, values []byte) (interface{}, error), connectionType bool, newSubscriptionCn, removeSubscriptionCn chan<- *SubscriptionNotification, onClosedConnection func(*AdvancedConnection), onIncreaseKnownNodeScore func(*known_node.KnownNodeScored, int32, bool) bool) (*AdvancedConnection, error) {
-
-	//making sure u is not collided with UUID_ALL and UUID_SKIP_ALL
+//NewUUID returns an unique id for a connection or a webhook. It is never UUID_ALL or UUID_SKIP_ALL
+func NewUUID() advanced_connection_types.UUID {
 	uuid := advanced_connection_types.UUID(atomic.AddUint32(&uuidGenerator, 1))
 	for uuid <= advanced_connection_types.UUID_SKIP_ALL {
 		uuid = advanced_connection_types.UUID(atomic.AddUint32(&uuidGenerator, 1))
 	}
+	return uuid
+}
+
+func NewAdvancedConnection(conn *websock.Conn, remoteAddr string, knownNode *known_node.KnownNodeScored, getMap map[string]func(conn *AdvancedConnection, values []byte) (interface{}, error), connectionType bool, newSubscriptionCn, removeSubscriptionCn chan<- *SubscriptionNotification, onClosedConnection func(*AdvancedConnection), onIncreaseKnownNodeScore func(*known_node.KnownNodeScored, int32, bool) bool) (*AdvancedConnection, error) {
 
 	advancedConnection := &AdvancedConnection{
-		abool.New(),
-		uuid,
+		&generics.Value[*config_auth.Principal]{},
+		NewUUID(),
 		conn,
 		nil,
 		nil,
@@ -1,6 +1,9 @@
 package connection
 
-import "pandora-pay/network/api/api_common/api_types"
+import (
+	"pandora-pay/network/api/api_common/api_types"
+	"pandora-pay/network/websocks/connection/advanced_connection_types"
+)
 
 type Subscription struct {
 	Type       api_types.SubscriptionType
@@ -8,7 +11,15 @@ type Subscription struct {
 	ReturnType api_types.APIReturnType
 }
 
+//SubscriptionWebhook receives the notifications of a subscription that has no websocket connection
+type SubscriptionWebhook interface {
+	Notify(notification *api_types.APISubscriptionNotification)
+}
+
+//SubscriptionNotification is a subscription of a connection or of a webhook. UUID identifies the subscriber
 type SubscriptionNotification struct {
 	Subscription *Subscription
 	Conn         *AdvancedConnection
+	Webhook      SubscriptionWebhook
+	UUID         advanced_connection_types.UUID
 }
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"errors"
 	"golang.org/x/exp/slices"
+	"pandora-pay/blockchain/transactions/transaction/transaction_data"
 	"pandora-pay/config"
 	"pandora-pay/config/config_coins"
 	"pandora-pay/cryptography"
@@ -20,15 +21,20 @@ type Subscriptions struct {
 	sync.Mutex
 }
 
-func checkSubscriptionLength(key []byte, subscriptionType api_types.SubscriptionType) error {
+//CheckSubscriptionLength verifies the length of the key of a subscription
+func CheckSubscriptionLength(key []byte, subscriptionType api_types.SubscriptionType) error {
 	var length int
 	switch subscriptionType {
-	case api_types.SUBSCRIPTION_PLAIN_ACCOUNT, api_types.SUBSCRIPTION_ACCOUNT, api_types.SUBSCRIPTION_ACCOUNT_TRANSACTIONS, api_types.SUBSCRIPTION_REGISTRATION:
+	case api_types.SUBSCRIPTION_PLAIN_ACCOUNT, api_types.SUBSCRIPTION_ACCOUNT, api_types.SUBSCRIPTION_ACCOUNT_TRANSACTIONS, api_types.SUBSCRIPTION_REGISTRATION, api_types.SUBSCRIPTION_DELEGATE:
 		length = cryptography.PublicKeySize
-	case api_types.SUBSCRIPTION_ASSET:
+	case api_types.SUBSCRIPTION_ASSET, api_types.SUBSCRIPTION_MEMPOOL_ASSET:
 		length = config_coins.ASSET_LENGTH
 	case api_types.SUBSCRIPTION_TRANSACTION:
 		length = cryptography.HashSize
+	case api_types.SUBSCRIPTION_WALLET_PAYMENT:
+		length = transaction_data.TX_DATA_MESSAGE_PAYMENT_ID_LENGTH
+	case api_types.SUBSCRIPTION_REORG, api_types.SUBSCRIPTION_MEMPOOL:
+		length = 0
 	}
 	if len(key) != length {
 		return errors.New("Key is invalid")
@@ -42,7 +48,7 @@ func (s *Subscriptions) AddSubscription(subscriptionType api_types.SubscriptionT
 		return errors.New("These subscriptions are automatically. They can't be subsribed manually")
 	}
 
-	if err := checkSubscriptionLength(key, subscriptionType); err != nil {
+	if err := CheckSubscriptionLength(key, subscriptionType); err != nil {
 		return err
 	}
 
@@ -64,14 +70,14 @@ func (s *Subscriptions) AddSubscription(subscriptionType api_types.SubscriptionT
 	subscription := &Subscription{subscriptionType, key, returnType}
 	s.list = append(s.list, subscription)
 
-	s.newSubscriptionCn <- &SubscriptionNotification{subscription, s.conn}
+	s.newSubscriptionCn <- &SubscriptionNotification{subscription, s.conn, nil, s.conn.UUID}
 
 	return nil
 }
 
 func (s *Subscriptions) RemoveSubscription(subscriptionType api_types.SubscriptionType, key []byte) error {
 
-	if err := checkSubscriptionLength(key, subscriptionType); err != nil {
+	if err := CheckSubscriptionLength(key, subscriptionType); err != nil {
 		return err
 	}
 
@@ -81,7 +87,7 @@ func (s *Subscriptions) RemoveSubscription(subscriptionType api_types.Subscripti
 	for i, subscription := range s.list {
 		if subscription.Type == subscriptionType && bytes.Equal(subscription.Key, key) {
 			s.list = slices.Delete(s.list, i, i+1)
-			s.removeSubscriptionCn <- &SubscriptionNotification{subscription, s.conn}
+			s.removeSubscriptionCn <- &SubscriptionNotification{subscription, s.conn, nil, s.conn.UUID}
 			return nil
 		}
 	}
@@ -11,6 +11,8 @@ import (
 	"pandora-pay/gui"
 	"pandora-pay/helpers/multicast"
 	"pandora-pay/mempool"
+	"pandora-pay/network/api/api_common/api_delegator_node"
+	"pandora-pay/network/api/api_common/api_webhooks"
 	"pandora-pay/network/api/api_http"
 	"pandora-pay/network/api/api_websockets"
 	"pandora-pay/network/banned_nodes"
@@ -22,6 +24,7 @@ import (
 	"pandora-pay/network/websocks/websock"
 	"pandora-pay/recovery"
 	"pandora-pay/settings"
+	"pandora-pay/wallet"
 	"strconv"
 	"sync/atomic"
 	"time"
@@ -243,7 +246,7 @@ func (websockets *Websockets) InitializeConnection(conn *connection.AdvancedConn
 	return nil
 }
 
-func NewWebsockets(chain *blockchain.Blockchain, mempool *mempool.Mempool, settings *settings.Settings, connectedNodes *connected_nodes.ConnectedNodes, knownNodes *known_nodes.KnownNodes, bannedNodes *banned_nodes.BannedNodes, api *api_http.API, apiWebsockets *api_websockets.APIWebsockets) *Websockets {
+func NewWebsockets(chain *blockchain.Blockchain, mempool *mempool.Mempool, wallet *wallet.Wallet, settings *settings.Settings, connectedNodes *connected_nodes.ConnectedNodes, knownNodes *known_nodes.KnownNodes, bannedNodes *banned_nodes.BannedNodes, api *api_http.API, apiWebsockets *api_websockets.APIWebsockets, delegatorNode *api_delegator_node.DelegatorNode, webhooks *api_webhooks.Webhooks) *Websockets {
 
 	websockets := &Websockets{
 		connectedNodes:               connectedNodes,
@@ -255,7 +258,7 @@ func NewWebsockets(chain *blockchain.Blockchain, mempool *mempool.Mempool, setti
 		bannedNodes:                  bannedNodes,
 	}
 
-	websockets.subscriptions = newWebsocketSubscriptions(websockets, chain, mempool)
+	websockets.subscriptions = newWebsocketSubscriptions(websockets, chain, mempool, wallet, delegatorNode, webhooks)
 
 	recovery.SafeGo(func() {
 		for {
@@ -1,21 +1,28 @@
 package websocks
 
 import (
+	"errors"
 	"github.com/vmihailenco/msgpack/v5"
 	"pandora-pay/blockchain"
+	"pandora-pay/blockchain/info"
 	"pandora-pay/config"
 	"pandora-pay/helpers"
 	"pandora-pay/mempool"
+	"pandora-pay/network/api/api_common/api_delegator_node"
 	"pandora-pay/network/api/api_common/api_types"
+	"pandora-pay/network/api/api_common/api_webhooks"
 	"pandora-pay/network/websocks/connection"
 	"pandora-pay/network/websocks/connection/advanced_connection_types"
 	"pandora-pay/recovery"
+	"pandora-pay/wallet"
 )
 
 type WebsocketSubscriptions struct {
 	websockets                        *Websockets
 	chain                             *blockchain.Blockchain
 	mempool                           *mempool.Mempool
+	wallet                            *wallet.Wallet
+	delegatorNode                     *api_delegator_node.DelegatorNode
 	websocketClosedCn                 chan *connection.AdvancedConnection
 	newSubscriptionCn                 chan *connection.SubscriptionNotification
 	removeSubscriptionCn              chan *connection.SubscriptionNotification
@@ -23,22 +30,35 @@ type WebsocketSubscriptions struct {
 	accountsTransactionsSubscriptions map[string]map[advanced_connection_types.UUID]*connection.SubscriptionNotification
 	assetsSubscriptions               map[string]map[advanced_connection_types.UUID]*connection.SubscriptionNotification
 	transactionsSubscriptions         map[string]map[advanced_connection_types.UUID]*connection.SubscriptionNotification
+	walletPaymentsSubscriptions       map[string]map[advanced_connection_types.UUID]*connection.SubscriptionNotification
+	delegatesSubscriptions            map[string]map[advanced_connection_types.UUID]*connection.SubscriptionNotification
+	reorgsSubscriptions               map[string]map[advanced_connection_types.UUID]*connection.SubscriptionNotification
+	mempoolSubscriptions              map[string]map[advanced_connection_types.UUID]*connection.SubscriptionNotification
+	mempoolAssetsSubscriptions        map[string]map[advanced_connection_types.UUID]*connection.SubscriptionNotification
 }
 
-func newWebsocketSubscriptions(websockets *Websockets, chain *blockchain.Blockchain, mempool *mempool.Mempool) (subs *WebsocketSubscriptions) {
+func newWebsocketSubscriptions(websockets *Websockets, chain *blockchain.Blockchain, mempool *mempool.Mempool, wallet *wallet.Wallet, delegatorNode *api_delegator_node.DelegatorNode, webhooks *api_webhooks.Webhooks) (subs *WebsocketSubscriptions) {
 
 	subs = &WebsocketSubscriptions{
-		websockets, chain, mempool, make(chan *connection.AdvancedConnection),
+		websockets, chain, mempool, wallet, delegatorNode, make(chan *connection.AdvancedConnection),
 		make(chan *connection.SubscriptionNotification),
 		make(chan *connection.SubscriptionNotification),
 		make(map[string]map[advanced_connection_types.UUID]*connection.SubscriptionNotification),
 		make(map[string]map[advanced_connection_types.UUID]*connection.SubscriptionNotification),
 		make(map[string]map[advanced_connection_types.UUID]*connection.SubscriptionNotification),
 		make(map[string]map[advanced_connection_types.UUID]*connection.SubscriptionNotification),
+		make(map[string]map[advanced_connection_types.UUID]*connection.SubscriptionNotification),
+		make(map[string]map[advanced_connection_types.UUID]*connection.SubscriptionNotification),
+		make(map[string]map[advanced_connection_types.UUID]*connection.SubscriptionNotification),
+		make(map[string]map[advanced_connection_types.UUID]*connection.SubscriptionNotification),
+		make(map[string]map[advanced_connection_types.UUID]*connection.SubscriptionNotification),
 	}
 
 	if config.SEED_WALLET_NODES_INFO {
 		recovery.SafeGo(subs.processSubscriptions)
+		if webhooks != nil {
+			webhooks.Start(subs.newSubscriptionCn, subs.removeSubscriptionCn)
+		}
 	}
 
 	return
@@ -59,7 +79,11 @@ func (this *WebsocketSubscriptions) send(subscriptionType api_types.Subscription
 	for _, subNot := range list {
 
 		if element == nil && elementBytes == nil && extra == nil {
-			_ = subNot.Conn.Send(key, nil, 0)
+			if subNot.Webhook != nil {
+				subNot.Webhook.Notify(&api_types.APISubscriptionNotification{subscriptionType, key, nil, nil})
+			} else {
+				_ = subNot.Conn.Send(key, nil, 0)
+			}
 			continue
 		}
 
@@ -74,7 +98,11 @@ func (this *WebsocketSubscriptions) send(subscriptionType api_types.Subscription
 			if serialized == nil {
 				serialized = &api_types.APISubscriptionNotification{subscriptionType, key, bytes, extraMarshalled}
 			}
-			_ = subNot.Conn.SendJSON(apiRoute, serialized, 0)
+			if subNot.Webhook != nil {
+				subNot.Webhook.Notify(serialized)
+			} else {
+				_ = subNot.Conn.SendJSON(apiRoute, serialized, 0)
+			}
 		} else if subNot.Subscription.ReturnType == api_types.RETURN_JSON {
 			if marshalled == nil {
 				var bytes []byte
@@ -87,7 +115,11 @@ func (this *WebsocketSubscriptions) send(subscriptionType api_types.Subscription
 				}
 				marshalled = &api_types.APISubscriptionNotification{subscriptionType, key, bytes, extraMarshalled}
 			}
-			_ = subNot.Conn.SendJSON(apiRoute, marshalled, 0)
+			if subNot.Webhook != nil {
+				subNot.Webhook.Notify(marshalled)
+			} else {
+				_ = subNot.Conn.SendJSON(apiRoute, marshalled, 0)
+			}
 		}
 
 	}
@@ -103,6 +135,16 @@ func (this *WebsocketSubscriptions) getSubsMap(subscriptionType api_types.Subscr
 		subsMap = this.assetsSubscriptions
 	case api_types.SUBSCRIPTION_TRANSACTION:
 		subsMap = this.transactionsSubscriptions
+	case api_types.SUBSCRIPTION_WALLET_PAYMENT:
+		subsMap = this.walletPaymentsSubscriptions
+	case api_types.SUBSCRIPTION_DELEGATE:
+		subsMap = this.delegatesSubscriptions
+	case api_types.SUBSCRIPTION_REORG:
+		subsMap = this.reorgsSubscriptions
+	case api_types.SUBSCRIPTION_MEMPOOL:
+		subsMap = this.mempoolSubscriptions
+	case api_types.SUBSCRIPTION_MEMPOOL_ASSET:
+		subsMap = this.mempoolAssetsSubscriptions
 	}
 	return
 }
@@ -133,9 +175,22 @@ func (this *WebsocketSubscriptions) processSubscriptions() {
 	updateTransactionsCn := this.chain.UpdateSocketsSubscriptionsTransactions.AddListener()
 	defer this.chain.UpdateSocketsSubscriptionsTransactions.RemoveChannel(updateTransactionsCn)
 
+	updateChainCn := this.chain.UpdateNewChainUpdate.AddListener()
+	defer this.chain.UpdateNewChainUpdate.RemoveChannel(updateChainCn)
+
 	updateMempoolTransactionsCn := this.mempool.Txs.UpdateMempoolTransactions.AddListener()
 	defer this.mempool.Txs.UpdateMempoolTransactions.RemoveChannel(updateMempoolTransactionsCn)
 
+	updatePaymentConfirmedCn := this.wallet.UpdatePaymentConfirmed.AddListener()
+	defer this.wallet.UpdatePaymentConfirmed.RemoveChannel(updatePaymentConfirmedCn)
+
+	//the delegator node is optional and a nil channel is never selected
+	var updateDelegateRevokedCn chan *api_delegator_node.DelegateRevoked
+	if this.delegatorNode != nil {
+		updateDelegateRevokedCn = this.delegatorNode.UpdateDelegateRevoked.AddListener()
+		defer this.delegatorNode.UpdateDelegateRevoked.RemoveChannel(updateDelegateRevokedCn)
+	}
+
 	var subsMap map[string]map[advanced_connection_types.UUID]*connection.SubscriptionNotification
 
 	for {
@@ -151,7 +206,7 @@ func (this *WebsocketSubscriptions) processSubscriptions() {
 			if subsMap[keyStr] == nil {
 				subsMap[keyStr] = make(map[advanced_connection_types.UUID]*connection.SubscriptionNotification)
 			}
-			subsMap[keyStr][subscription.Conn.UUID] = subscription
+			subsMap[keyStr][subscription.UUID] = subscription
 
 		case subscription := <-this.removeSubscriptionCn:
 
@@ -161,7 +216,7 @@ func (this *WebsocketSubscriptions) processSubscriptions() {
 
 			keyStr := string(subscription.Subscription.Key)
 			if subsMap[keyStr] != nil {
-				delete(subsMap[keyStr], subscription.Conn.UUID)
+				delete(subsMap[keyStr], subscription.UUID)
 				if len(subsMap[keyStr]) == 0 {
 					delete(subsMap, keyStr)
 				}
@@ -230,6 +285,19 @@ func (this *WebsocketSubscriptions) processSubscriptions() {
 				}
 			}
 
+		case chainUpdate, ok := <-updateChainCn:
+			if !ok {
+				return
+			}
+
+			if chainUpdate.Reorg != nil {
+				if list := this.reorgsSubscriptions[""]; list != nil {
+					this.send(api_types.SUBSCRIPTION_REORG, []byte("sub/notify"), nil, list, nil, nil, &api_types.APISubscriptionNotificationReorgExtra{
+						chainUpdate.Reorg.CommonAncestorHeight, chainUpdate.Reorg.RemovedBlocks, chainUpdate.Reorg.AddedBlocks, chainUpdate.Reorg.OrphanedTxs, chainUpdate.BlockHeight,
+					})
+				}
+			}
+
 		case txsUpdates, ok := <-updateTransactionsCn:
 			if !ok {
 				return
@@ -274,6 +342,49 @@ func (this *WebsocketSubscriptions) processSubscriptions() {
 				})
 			}
 
+			//the preview is created only when there are subscribers
+			if len(this.mempoolSubscriptions) > 0 || len(this.mempoolAssetsSubscriptions) > 0 {
+
+				txPreview, err := info.CreateTxPreviewFromTx(txUpdate.Tx)
+				if err != nil {
+					continue
+				}
+
+				extra := &api_types.APISubscriptionNotificationMempoolExtra{txUpdate.Inserted, txUpdate.IncludedInBlockchainNotification, txPreview}
+
+				if list := this.mempoolSubscriptions[""]; list != nil {
+					this.send(api_types.SUBSCRIPTION_MEMPOOL, []byte("sub/notify"), nil, list, nil, txUpdate.Tx.Bloom.Hash, extra)
+				}
+
+				for asset := range txPreview.GetAssets() {
+					if list := this.mempoolAssetsSubscriptions[asset]; list != nil {
+						this.send(api_types.SUBSCRIPTION_MEMPOOL_ASSET, []byte("sub/notify"), []byte(asset), list, nil, txUpdate.Tx.Bloom.Hash, extra)
+					}
+				}
+			}
+
+		case payment, ok := <-updatePaymentConfirmedCn:
+			if !ok {
+				return
+			}
+
+			if list := this.walletPaymentsSubscriptions[string(payment.PaymentID)]; list != nil {
+				this.send(api_types.SUBSCRIPTION_WALLET_PAYMENT, []byte("sub/notify"), payment.PaymentID, list, nil, nil, &api_types.APISubscriptionNotificationWalletPaymentExtra{
+					payment.TxHash, payment.PayloadIndex, payment.PublicKey, payment.Amount, payment.Asset, payment.BlockHeight, payment.BlockTimestamp,
+				})
+			}
+
+		case revoked, ok := <-updateDelegateRevokedCn:
+			if !ok {
+				return
+			}
+
+			if list := this.delegatesSubscriptions[string(revoked.PublicKey)]; list != nil {
+				this.send(api_types.SUBSCRIPTION_DELEGATE, []byte("sub/notify"), revoked.PublicKey, list, nil, nil, &api_types.APISubscriptionNotificationDelegateRevokedExtra{
+					byte(revoked.Reason), revoked.BlockHeight,
+				})
+			}
+
 		case conn, ok := <-this.websocketClosedCn:
 			if !ok {
 				return
@@ -283,9 +394,32 @@ func (this *WebsocketSubscriptions) processSubscriptions() {
 			this.removeConnection(conn, api_types.SUBSCRIPTION_ACCOUNT_TRANSACTIONS)
 			this.removeConnection(conn, api_types.SUBSCRIPTION_ASSET)
 			this.removeConnection(conn, api_types.SUBSCRIPTION_TRANSACTION)
+			this.removeConnection(conn, api_types.SUBSCRIPTION_WALLET_PAYMENT)
+			this.removeConnection(conn, api_types.SUBSCRIPTION_DELEGATE)
+			this.removeConnection(conn, api_types.SUBSCRIPTION_REORG)
+			this.removeConnection(conn, api_types.SUBSCRIPTION_MEMPOOL)
+			this.removeConnection(conn, api_types.SUBSCRIPTION_MEMPOOL_ASSET)
 
 		}
 
 	}
 
 }
+
+//Subscribe registers a subscription that is notified without a websocket connection, like the gRPC streams
+func (websockets *Websockets) Subscribe(subscription *connection.SubscriptionNotification) error {
+	if !config.SEED_WALLET_NODES_INFO {
+		return errors.New("Subscriptions require --seed-wallet-nodes-info")
+	}
+	if subscription.Webhook == nil {
+		return errors.New("Subscription must have a notifier")
+	}
+	websockets.subscriptions.newSubscriptionCn <- subscription
+	return nil
+}
+
+func (websockets *Websockets) Unsubscribe(subscription *connection.SubscriptionNotification) {
+	if config.SEED_WALLET_NODES_INFO {
+		websockets.subscriptions.removeSubscriptionCn <- subscription
+	}
+}
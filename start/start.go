@@ -2,17 +2,24 @@ package start
 
 import (
 	"context"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"math"
 	"os"
 	"os/signal"
 	"pandora-pay/address_balance_decryptor"
+	"pandora-pay/addresses"
 	"pandora-pay/app"
 	"pandora-pay/blockchain"
+	"pandora-pay/blockchain/data_storage/accounts/account"
+	"pandora-pay/blockchain/data_storage/registrations/registration"
 	"pandora-pay/blockchain/forging"
+	"pandora-pay/blockchain/forging/forging_signer"
 	"pandora-pay/blockchain/genesis"
 	"pandora-pay/config"
 	"pandora-pay/config/arguments"
+	"pandora-pay/config/config_auth"
 	"pandora-pay/config/config_forging"
 	"pandora-pay/config/globals"
 	"pandora-pay/cryptography/crypto/balance_decryptor"
@@ -20,6 +27,7 @@ import (
 	"pandora-pay/helpers/debugging_pprof"
 	"pandora-pay/mempool"
 	"pandora-pay/network"
+	"pandora-pay/network/light_wallet"
 	"pandora-pay/settings"
 	"pandora-pay/store"
 	"pandora-pay/testnet"
@@ -28,6 +36,7 @@ import (
 	"pandora-pay/wallet"
 	"runtime"
 	"strconv"
+	"strings"
 	"syscall"
 )
 
@@ -53,6 +62,10 @@ func StartMainNow() (err error) {
 	}
 	globals.MainEvents.BroadcastEvent("main", "database initialized")
 
+	if err = config_auth.LoadTokens(); err != nil {
+		return
+	}
+
 	if app.TxsValidator, err = txs_validator.NewTxsValidator(); err != nil {
 		return
 	}
@@ -87,6 +100,22 @@ func StartMainNow() (err error) {
 
 	globals.MainEvents.BroadcastEvent("main", "wallet initialized")
 
+	if config_forging.FORGING_SIGNER_SERVER != "" {
+		if app.ForgingSignerServer, err = forging_signer.NewSignerServer(config_forging.FORGING_SIGNER_SERVER, config_forging.FORGING_SIGNER_SECRET, func(publicKey []byte) *addresses.PrivateKey {
+			return app.Wallet.GetForgingPrivateKey(publicKey, true)
+		}); err != nil {
+			return
+		}
+		globals.MainEvents.BroadcastEvent("main", "forging signer server initialized")
+	}
+
+	if dataArguments := globals.Arguments["--build-genesis"]; dataArguments != nil {
+		if err = buildGenesis(strings.Split(dataArguments.(string), ",")); err != nil {
+			return
+		}
+		os.Exit(0)
+	}
+
 	if err = genesis.GenesisInit(app.Wallet.GetFirstAddressForDevnetGenesisAirdrop); err != nil {
 		return
 	}
@@ -111,7 +140,7 @@ func StartMainNow() (err error) {
 		}()
 	}
 
-	app.Wallet.InitializeWallet(app.Chain.UpdateNewChainUpdate)
+	app.Wallet.InitializeWallet(app.Chain.UpdateNewChainUpdate, app.Chain.UpdateSocketsSubscriptionsTransactions)
 	if err = app.Wallet.StartWallet(); err != nil {
 		return
 	}
@@ -142,17 +171,52 @@ func StartMainNow() (err error) {
 		app.Testnet = myTestnet
 	}
 
-	if app.Network, err = network.NewNetwork(app.Settings, app.Chain, app.Mempool, app.Wallet, app.TxsValidator, app.TxsBuilder); err != nil {
+	if app.Network, err = network.NewNetwork(app.Settings, app.Chain, app.Mempool, app.Forging, app.Wallet, app.TxsValidator, app.TxsBuilder); err != nil {
 		return
 	}
 	globals.MainEvents.BroadcastEvent("main", "network initialized")
 
+	if config.CONSENSUS == config.CONSENSUS_TYPE_WALLET {
+		app.Wallet.SetNetworkAccounts(func(publicKeys [][]byte, asset []byte) ([]*registration.Registration, []*account.Account, error) {
+			return light_wallet.GetVerifiedAccounts(app.Network.Websockets, app.Chain, publicKeys, asset)
+		})
+	}
+
 	gui.GUI.Log("Main Loop")
 	globals.MainEvents.BroadcastEvent("main", "initialized")
 
 	return
 }
 
+func buildGenesis(args []string) (err error) {
+
+	if len(args) != 2 {
+		return errors.New("--build-genesis argument must be \"spec.json,genesis.data\"")
+	}
+
+	addr, err := app.Wallet.GetWalletAddress(0, true)
+	if err != nil {
+		return
+	}
+	if addr.PrivateKey == nil {
+		return errors.New("The first wallet address can't sign the genesis")
+	}
+
+	defaultGenesis, err := genesis.GetDefaultGenesis()
+	if err != nil {
+		return
+	}
+
+	hash, err := genesis.BuildGenesisFile(args[0], args[1], defaultGenesis.Target, addr.PrivateKey)
+	if err != nil {
+		return
+	}
+
+	gui.GUI.Info("Genesis built", args[1], "hash", hex.EncodeToString(hash))
+	fmt.Println(hex.EncodeToString(hash))
+	return
+}
+
 func InitMain(ready func()) {
 	var err error
 
@@ -162,6 +226,15 @@ func InitMain(ready func()) {
 	}
 	globals.MainEvents.BroadcastEvent("main", "arguments initialized")
 
+	if password := globals.Arguments["--auth-hash-password"]; password != nil {
+		hash, err := config_auth.HashPassword(password.(string))
+		if err != nil {
+			saveError(err)
+		}
+		fmt.Println(hash)
+		os.Exit(0)
+	}
+
 	if err = config.InitConfig(); err != nil {
 		saveError(err)
 	}
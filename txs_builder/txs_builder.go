@@ -2,6 +2,7 @@ package txs_builder
 
 import (
 	"context"
+	"encoding/binary"
 	"errors"
 	"fmt"
 	"pandora-pay/blockchain/data_storage/assets/asset"
@@ -101,9 +102,11 @@ func (builder *TxsBuilder) CreateSimpleTx(txData *TxBuilderCreateSimpleTx, propa
 	var plainAcc *plain_account.PlainAccount
 	var chainHeight uint64
 
-	if len(sendersWalletAddresses) > 0 {
+	if err = store.StoreBlockchain.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+
+		chainHeight, _ = binary.Uvarint(reader.Get("chainHeight"))
 
-		if err = store.StoreBlockchain.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+		if len(sendersWalletAddresses) > 0 {
 
 			plainAccs := plain_accounts.NewPlainAccounts(reader)
 
@@ -113,18 +116,20 @@ func (builder *TxsBuilder) CreateSimpleTx(txData *TxBuilderCreateSimpleTx, propa
 			if plainAcc == nil {
 				return errors.New("Plain Account doesn't exist")
 			}
-
-			return
-		}); err != nil {
-			return nil, err
 		}
 
+		return
+	}); err != nil {
+		return nil, err
+	}
+
+	if len(sendersWalletAddresses) > 0 {
 		statusCallback("Getting Nonce from Mempool")
 		transfer.Nonce = builder.getNonce(txData.Nonce, sendersWalletAddresses[0].PublicKey, plainAcc.Nonce)
 		transfer.Key = sendersWalletAddresses[0].PrivateKey.Key
 	}
 
-	if tx, err = wizard.CreateSimpleTx(transfer, false, statusCallback); err != nil {
+	if tx, err = wizard.CreateSimpleTx(transfer, chainHeight, false, statusCallback); err != nil {
 		return nil, err
 	}
 	statusCallback("Transaction Created")
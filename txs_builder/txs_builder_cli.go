@@ -85,6 +85,32 @@ func (builder *TxsBuilder) readAddress(text string, leaveEmpty bool) (address *a
 	return
 }
 
+func (builder *TxsBuilder) readRecipient(text string) (recipient string, request *addresses.PaymentRequest, err error) {
+
+	for {
+		recipient = gui.GUI.OutputReadString(text)
+
+		if addresses.IsPaymentRequestURI(recipient) {
+			if request, err = addresses.DecodePaymentRequestURI(recipient); err != nil {
+				gui.GUI.OutputWrite("Invalid Payment Request URI")
+				continue
+			}
+			if request.IsExpired() {
+				return "", nil, errors.New("Payment Request expired")
+			}
+			break
+		}
+
+		if _, err = addresses.DecodeAddr(recipient); err != nil {
+			gui.GUI.OutputWrite("Invalid Address")
+			continue
+		}
+		break
+	}
+
+	return
+}
+
 func (builder *TxsBuilder) readAddressOptional(text string, assetId []byte, allowRandomAddress bool) (address *addresses.Address, addressEncoded string, amount uint64, err error) {
 
 	text2 := text
@@ -197,14 +223,29 @@ func (builder *TxsBuilder) initCLI() {
 			return
 		}
 
-		txData.Payloads[0].Asset = builder.readAsset("Asset. Leave empty for Native Asset", true)
+		var request *addresses.PaymentRequest
+		if txData.Payloads[0].Recipient, request, err = builder.readRecipient("Recipient Address or Payment Request URI"); err != nil {
+			return
+		}
 
-		if _, txData.Payloads[0].Recipient, txData.Payloads[0].Amount, err = builder.readAddressOptional("Recipient Address", txData.Payloads[0].Asset, false); err != nil {
+		if request != nil && len(request.Asset) > 0 {
+			txData.Payloads[0].Asset = request.Asset
+		} else {
+			txData.Payloads[0].Asset = builder.readAsset("Asset. Leave empty for Native Asset", true)
+		}
+
+		if request != nil && request.Amount > 0 {
+			txData.Payloads[0].Amount = request.Amount
+		} else if txData.Payloads[0].Amount, err = builder.readAmount(txData.Payloads[0].Asset, "Recipient Address Amount"); err != nil {
 			return
 		}
 
 		txData.Payloads[0].RingConfiguration = builder.readZetherRingConfiguration()
-		txData.Payloads[0].Data = builder.readData()
+		if request == nil || (len(request.PaymentID) == 0 && request.Memo == "") {
+			txData.Payloads[0].Data = builder.readData()
+		} else if request.Memo == "" {
+			txData.Payloads[0].Memo = gui.GUI.OutputReadString("Encrypted memo. Leave empty for none")
+		}
 		txData.Payloads[0].Fee = builder.readZetherFee(txData.Payloads[0].Asset)
 		propagate := gui.GUI.OutputReadBool("Propagate? y/n. Leave empty for yes", true, true)
 
@@ -276,14 +317,14 @@ func (builder *TxsBuilder) initCLI() {
 		gui.GUI.OutputWrite(fmt.Sprintf("Tx created: %s %s", base64.StdEncoding.EncodeToString(tx.Bloom.Hash), cmd))
 
 		assetId := tx.TransactionBaseInterface.(*transaction_zether.TransactionZether).Payloads[0].Extra.(*transaction_zether_payload_extra.TransactionZetherPayloadExtraAssetCreate).GetAssetId(tx.Bloom.Hash, 0)
-		gui.GUI.OutputWrite(fmt.Sprintf("Asset Id: %s", base64.StdEncoding.EncodeToString(assetId), cmd))
+		gui.GUI.OutputWrite(fmt.Sprintf("Asset Id: %s", base64.StdEncoding.EncodeToString(assetId)))
 
 		if updatePrivKey != nil || supplyPrivKey != nil {
 
 			if filename := gui.GUI.OutputReadFilename("Path to export Asset Private Keys", "keys", true); len(filename) > 0 {
 				if err = files.WriteFile(filename,
 					fmt.Sprintf("Asset ID: %s", base64.StdEncoding.EncodeToString(assetId)),
-					fmt.Sprintf("Asset name: %s", extra.Asset.Name, extra.Asset.Ticker),
+					fmt.Sprintf("Asset name: %s %s", extra.Asset.Name, extra.Asset.Ticker),
 					fmt.Sprintf("Supply Private Key: %s", base64.StdEncoding.EncodeToString(supplyPrivKey.Key)),
 					fmt.Sprintf("Update Private Key: %s", base64.StdEncoding.EncodeToString(updatePrivKey.Key)),
 				); err != nil {
@@ -472,6 +513,52 @@ func (builder *TxsBuilder) initCLI() {
 		return
 	}
 
+	cliPrivateUnstake := func(cmd string, ctx context.Context) (err error) {
+		builder.showWarningIfNotSyncCLI()
+
+		txData := &TxBuilderCreateZetherTxData{
+			Payloads: []*TxBuilderCreateZetherTxPayload{{
+				Extra: &wizard.WizardZetherPayloadExtraUnstaking{},
+				Asset: config_coins.NATIVE_ASSET_FULL,
+			}},
+		}
+
+		if _, txData.Payloads[0].Sender, _, err = builder.wallet.CliSelectAddress("Select Staked Address to Unstake", ctx); err != nil {
+			return
+		}
+
+		if _, txData.Payloads[0].Recipient, _, err = builder.wallet.CliSelectAddress("Select Unstaked Address which will receive the funds after the cooldown", ctx); err != nil {
+			return
+		}
+
+		if txData.Payloads[0].Amount, err = builder.readAmount(config_coins.NATIVE_ASSET_FULL, "Amount to Unstake"); err != nil {
+			return
+		}
+
+		txData.Payloads[0].RingConfiguration = builder.readZetherRingConfiguration()
+		if err = builder.presetZetherRing(txData.Payloads[0].RingConfiguration); err != nil {
+			return err
+		}
+
+		txData.Payloads[0].RingConfiguration.SenderRingType.RequireStakedAccounts = true
+		txData.Payloads[0].RingConfiguration.RecipientRingType.AvoidStakedAccounts = true
+
+		txData.Payloads[0].Data = builder.readData()
+		txData.Payloads[0].Fee = builder.readZetherFee(config_coins.NATIVE_ASSET_FULL)
+
+		propagate := gui.GUI.OutputReadBool("Propagate? y/n. Leave empty for yes", true, true)
+
+		tx, err := builder.CreateZetherTx(txData, nil, propagate, true, true, false, ctx, func(status string) {
+			gui.GUI.OutputWrite(status)
+		})
+		if err != nil {
+			return
+		}
+
+		gui.GUI.OutputWrite(fmt.Sprintf("Tx created: %s %s", base64.StdEncoding.EncodeToString(tx.Bloom.Hash), cmd))
+		return
+	}
+
 	cliUpdateAssetFeeLiquidity := func(cmd string, ctx context.Context) (err error) {
 
 		builder.showWarningIfNotSyncCLI()
@@ -603,6 +690,7 @@ func (builder *TxsBuilder) initCLI() {
 	gui.GUI.CommandDefineCallback("Private Asset Supply Increase", cliPrivateAssetSupplyIncrease, true)
 	gui.GUI.CommandDefineCallback("Private Plain Account Fund", cliPrivatePlainAccountFund, true)
 	gui.GUI.CommandDefineCallback("Private Conditional Payment", cliPrivateConditionalPayment, true)
+	gui.GUI.CommandDefineCallback("Private Unstake", cliPrivateUnstake, true)
 	gui.GUI.CommandDefineCallback("Public Update Asset Fee Liquidity", cliUpdateAssetFeeLiquidity, true)
 	gui.GUI.CommandDefineCallback("Public Resolution Conditional Payment", cliResolutionConditionalPayment, true)
 
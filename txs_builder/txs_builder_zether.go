@@ -18,7 +18,10 @@ import (
 	"pandora-pay/blockchain/data_storage/registrations"
 	"pandora-pay/blockchain/data_storage/registrations/registration"
 	"pandora-pay/blockchain/transactions/transaction"
+	"pandora-pay/blockchain/transactions/transaction/transaction_data"
+	"pandora-pay/blockchain/transactions/transaction/transaction_zether/transaction_zether_payload"
 	"pandora-pay/config/config_coins"
+	"pandora-pay/config/config_forks"
 	"pandora-pay/cryptography/bn256"
 	"pandora-pay/cryptography/crypto"
 	"pandora-pay/gui"
@@ -267,6 +270,90 @@ func (builder *TxsBuilder) createZetherRing(allAlreadyUsed map[string]bool, send
 	return
 }
 
+func (builder *TxsBuilder) applyPaymentRequest(payload *TxBuilderCreateZetherTxPayload) ([]byte, error) {
+
+	request, err := addresses.DecodePaymentRequestURI(payload.Recipient)
+	if err != nil {
+		return nil, err
+	}
+	if request.IsExpired() {
+		return nil, errors.New("Payment Request expired")
+	}
+
+	payload.Recipient = request.Address.EncodeAddr()
+
+	if request.Amount > 0 {
+		if payload.Amount == 0 {
+			payload.Amount = request.Amount
+		} else if payload.Amount != request.Amount {
+			return nil, errors.New("Amount is not matching the Payment Request")
+		}
+	}
+
+	if len(request.Asset) > 0 {
+		if payload.Asset == nil {
+			payload.Asset = request.Asset
+		} else if !bytes.Equal(payload.Asset, request.Asset) {
+			return nil, errors.New("Asset is not matching the Payment Request")
+		}
+	}
+
+	if request.Memo != "" {
+		if payload.Memo == "" {
+			payload.Memo = request.Memo
+		} else if payload.Memo != request.Memo {
+			return nil, errors.New("Memo is not matching the Payment Request")
+		}
+	}
+
+	return request.PaymentID, nil
+}
+
+//getRecipientPaymentID returns the payment id of the payment request or of the integrated address of the recipient
+func (builder *TxsBuilder) getRecipientPaymentID(payload *TxBuilderCreateZetherTxPayload) ([]byte, error) {
+
+	if addresses.IsPaymentRequestURI(payload.Recipient) {
+		return builder.applyPaymentRequest(payload)
+	}
+
+	//the recipient of the staking rewards is set later
+	if payload.Recipient == "" {
+		return nil, nil
+	}
+
+	recipient, err := addresses.DecodeAddr(payload.Recipient)
+	if err != nil {
+		return nil, err
+	}
+
+	return recipient.PaymentID, nil
+}
+
+//applyMemo encrypts the memo and the payment id so only the recipient and the sender can read them
+func (builder *TxsBuilder) applyMemo(payload *TxBuilderCreateZetherTxPayload, paymentID []byte) error {
+
+	if payload.Memo == "" && len(paymentID) == 0 {
+		return nil
+	}
+
+	if payload.Data != nil && len(payload.Data.Data) > 0 {
+		return errors.New("Data can not be used together with a Memo or a PaymentID")
+	}
+
+	message := &transaction_data.TransactionDataMessage{paymentID, []byte(payload.Memo)}
+	if err := message.Validate(); err != nil {
+		return err
+	}
+
+	data := message.Serialize()
+	if len(data) > transaction_zether_payload.PAYLOAD_LIMIT {
+		return errors.New("Memo is too long")
+	}
+
+	payload.Data = &wizard.WizardTransactionData{data, true}
+	return nil
+}
+
 func (builder *TxsBuilder) prebuild(txData *TxBuilderCreateZetherTxData, pendingTxs []*transaction.Transaction, blockHeight uint64, prevKernelHash []byte, ctx context.Context, statusCallback func(string)) ([]*wizard.WizardZetherTransfer, map[string]map[string][]byte, map[string]bool, [][]*bn256.G1, [][]*bn256.G1, map[string]*wizard.WizardZetherPublicKeyIndex, uint64, []byte, error) {
 
 	sendersPrivateKeys := make([]*addresses.PrivateKey, len(txData.Payloads))
@@ -277,6 +364,15 @@ func (builder *TxsBuilder) prebuild(txData *TxBuilderCreateZetherTxData, pending
 
 	for t, payload := range txData.Payloads {
 
+		paymentID, err := builder.getRecipientPaymentID(payload)
+		if err != nil {
+			return nil, nil, nil, nil, nil, nil, 0, nil, err
+		}
+
+		if err = builder.applyMemo(payload, paymentID); err != nil {
+			return nil, nil, nil, nil, nil, nil, 0, nil, err
+		}
+
 		if payload.Asset == nil {
 			payload.Asset = config_coins.NATIVE_ASSET_FULL
 		}
@@ -437,6 +533,35 @@ func (builder *TxsBuilder) prebuild(txData *TxBuilderCreateZetherTxData, pending
 				return
 			}
 
+			//after the upgrade the staked funds can leave only through Unstaking, so other native payloads can't have staked senders, even as decoys, unless all the recipients are staked and nothing is burned
+			if includeHeight := blockHeight; bytes.Equal(payload.Asset, config_coins.NATIVE_ASSET_FULL) && (!payload.RingConfiguration.RecipientRingType.RequireStakedAccounts || payload.Burn > 0) {
+				if includeHeight == 0 {
+					includeHeight, _ = binary.Uvarint(reader.Get("chainHeight"))
+				}
+				if config_forks.GetRules(includeHeight).StakedSendersChecks {
+					switch payload.Extra.(type) {
+					case *wizard.WizardZetherPayloadExtraStaking, *wizard.WizardZetherPayloadExtraStakingReward, *wizard.WizardZetherPayloadExtraUnstaking:
+					default:
+						payload.RingConfiguration.SenderRingType.RequireStakedAccounts = false
+						payload.RingConfiguration.SenderRingType.AvoidStakedAccounts = true
+					}
+				}
+			}
+
+			if payload.RingConfiguration.SenderRingType.AvoidStakedAccounts {
+				var senderAddr *addresses.Address
+				var senderReg *registration.Registration
+				if senderAddr, err = addresses.DecodeAddr(payload.Sender); err != nil {
+					return
+				}
+				if senderReg, err = dataStorage.Regs.Get(string(senderAddr.PublicKey)); err != nil {
+					return
+				}
+				if senderReg != nil && senderReg.Staked {
+					return errors.New("Staked funds can be moved only by Unstaking or to staked recipients")
+				}
+			}
+
 			if err = builder.createZetherRing(allAlreadyUsed, &senderRingMembers[t], &recipientRingMembers[t], &payload.Sender, &payload.Recipient, payload.Asset, payload.RingConfiguration, hasRollovers, dataStorage); err != nil {
 				return
 			}
@@ -732,6 +857,7 @@ func (builder *TxsBuilder) CreateForgingTransactions(blkComplete *block_complete
 				blkComplete.StakingAmount,
 				&ZetherRingConfiguration{64, &ZetherSenderRingType{true, false, nil, 0}, &ZetherRecipientRingType{true, false, nil, 0}},
 				nil,
+				"",
 				&wizard.WizardZetherTransactionFee{&wizard.WizardTransactionFee{0, 0, 0, false}, false, 0, 0},
 				&wizard.WizardZetherPayloadExtraStaking{},
 				nil,
@@ -745,6 +871,7 @@ func (builder *TxsBuilder) CreateForgingTransactions(blkComplete *block_complete
 				0,
 				&ZetherRingConfiguration{64, &ZetherSenderRingType{true, false, nil, 0}, &ZetherRecipientRingType{true, false, nil, 0}},
 				nil,
+				"",
 				&wizard.WizardZetherTransactionFee{&wizard.WizardTransactionFee{0, 0, 0, false}, false, 0, 0},
 				&wizard.WizardZetherPayloadExtraStakingReward{nil, finalForgerReward},
 				nil,
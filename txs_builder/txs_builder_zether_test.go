@@ -0,0 +1,46 @@
+package txs_builder
+
+import (
+	"github.com/stretchr/testify/assert"
+	"pandora-pay/addresses"
+	"pandora-pay/blockchain/transactions/transaction/transaction_data"
+	"pandora-pay/helpers"
+	"testing"
+)
+
+func TestRecipientPaymentID(t *testing.T) {
+
+	builder := &TxsBuilder{}
+
+	paymentID := helpers.RandomBytes(transaction_data.TX_DATA_MESSAGE_PAYMENT_ID_LENGTH)
+	integrated, err := addresses.GenerateNewPrivateKey().GenerateAddress(false, nil, false, paymentID, 0, nil)
+	assert.Nil(t, err)
+
+	payload := &TxBuilderCreateZetherTxPayload{Recipient: integrated.EncodeAddr(), Memo: "memo"}
+	id, err := builder.getRecipientPaymentID(payload)
+	assert.Nil(t, err)
+	assert.Equal(t, paymentID, id, "the payment id is taken from the integrated address")
+
+	assert.Nil(t, builder.applyMemo(payload, id))
+	message := transaction_data.DeserializeTransactionDataMessage(payload.Data.Data)
+	assert.Equal(t, paymentID, message.PaymentID)
+	assert.Equal(t, []byte("memo"), message.Memo)
+
+	plain, err := addresses.GenerateNewPrivateKey().GenerateAddress(false, nil, false, nil, 0, nil)
+	assert.Nil(t, err)
+	id, err = builder.getRecipientPaymentID(&TxBuilderCreateZetherTxPayload{Recipient: plain.EncodeAddr()})
+	assert.Nil(t, err)
+	assert.Nil(t, id)
+
+	payload = &TxBuilderCreateZetherTxPayload{Recipient: "pandora:" + integrated.EncodeAddr()}
+	id, err = builder.getRecipientPaymentID(payload)
+	assert.Nil(t, err)
+	assert.Equal(t, paymentID, id)
+	recipient, err := addresses.DecodeAddr(payload.Recipient)
+	assert.Nil(t, err)
+	assert.Equal(t, integrated.PublicKey, recipient.PublicKey)
+	assert.Nil(t, recipient.PaymentID, "the payment id of the request is sent in the memo")
+
+	_, err = builder.getRecipientPaymentID(&TxBuilderCreateZetherTxPayload{Recipient: "invalid"})
+	assert.NotNil(t, err)
+}
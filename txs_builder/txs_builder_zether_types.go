@@ -33,6 +33,7 @@ type TxBuilderCreateZetherTxPayload struct {
 	Burn              uint64                             `json:"burn" msgpack:"burn"`
 	RingConfiguration *ZetherRingConfiguration           `json:"ringConfiguration" msgpack:"ringConfiguration"`
 	Data              *wizard.WizardTransactionData      `json:"data" msgpack:"data"`
+	Memo              string                             `json:"memo" msgpack:"memo"`
 	Fee               *wizard.WizardZetherTransactionFee `json:"fee" msgpack:"fee"`
 	Extra             wizard.WizardZetherPayloadExtra    `json:"extra" msgpack:"extra"`
 	WitnessIndexes    []int                              `json:"witnessIndexes" msgpack:"witnessIndexes"`
@@ -7,7 +7,7 @@ import (
 	"pandora-pay/helpers"
 )
 
-func setFee(tx *transaction.Transaction, extraBytes int, fee *WizardTransactionFee, includeSerialize bool) uint64 {
+func setFee(tx *transaction.Transaction, blockHeight uint64, extraBytes int, fee *WizardTransactionFee, includeSerialize bool) uint64 {
 
 	if fee.Fixed > 0 {
 		return fee.Fixed
@@ -20,11 +20,11 @@ func setFee(tx *transaction.Transaction, extraBytes int, fee *WizardTransactionF
 	if fee.PerByte == 0 && fee.PerByteAuto {
 		switch tx.Version {
 		case transaction_type.TX_SIMPLE:
-			fee.PerByte = config_fees.FEE_PER_BYTE
+			fee.PerByte = config_fees.GetFeePerByte(blockHeight)
 		case transaction_type.TX_ZETHER:
-			fee.PerByte = config_fees.FEE_PER_BYTE_ZETHER
+			fee.PerByte = config_fees.GetFeePerByteZether(blockHeight)
 		}
-		fee.PerByteExtraSpace = config_fees.FEE_PER_BYTE_EXTRA_SPACE
+		fee.PerByteExtraSpace = config_fees.GetFeePerByteExtraSpace(blockHeight)
 	}
 
 	spaceExtra := tx.SpaceExtra
@@ -12,7 +12,7 @@ import (
 	"pandora-pay/helpers"
 )
 
-func CreateSimpleTx(transfer *WizardTxSimpleTransfer, validateTx bool, statusCallback func(string)) (tx2 *transaction.Transaction, err error) {
+func CreateSimpleTx(transfer *WizardTxSimpleTransfer, chainHeight uint64, validateTx bool, statusCallback func(string)) (tx2 *transaction.Transaction, err error) {
 
 	dataFinal, err := transfer.Data.getData()
 	if err != nil {
@@ -84,7 +84,7 @@ func CreateSimpleTx(transfer *WizardTxSimpleTransfer, validateTx bool, statusCal
 	statusCallback("Transaction Created")
 
 	extraBytes := cryptography.SignatureSize
-	txBase.Fee = setFee(tx, extraBytes, transfer.Fee.Clone(), true)
+	txBase.Fee = setFee(tx, chainHeight, extraBytes, transfer.Fee.Clone(), true)
 	statusCallback("Transaction Fee set")
 
 	statusCallback("Transaction Signing...")
@@ -318,6 +318,9 @@ func signZetherTx(tx *transaction.Transaction, txBase *transaction_zether.Transa
 					payloadExtra.Threshold,
 					payloadExtra.MultisigPublicKeys,
 				}
+			case *WizardZetherPayloadExtraUnstaking:
+				payloads[t].PayloadScript = transaction_zether_payload_script.SCRIPT_UNSTAKING
+				payloads[t].Extra = &transaction_zether_payload_extra.TransactionZetherPayloadExtraUnstaking{}
 			default:
 				return errors.New("Invalid payload")
 			}
@@ -422,7 +425,7 @@ func signZetherTx(tx *transaction.Transaction, txBase *transaction_zether.Transa
 		extraBytes += len(payload.WhisperRecipient)
 		extraBytes += len(payload.WhisperSender)
 
-		fee := setFee(tx, extraBytes, myFees[t].Clone(), t == 0) + otherFee
+		fee := setFee(tx, txBase.ChainHeight, extraBytes, myFees[t].Clone(), t == 0) + otherFee
 		otherFee = 0
 
 		statusCallback("Transaction Set fee")
@@ -564,7 +567,7 @@ func signZetherTx(tx *transaction.Transaction, txBase *transaction_zether.Transa
 
 				} else { //receiver
 					if (bytes.Equal(payload.Asset, config_coins.NATIVE_ASSET_FULL) && hasRollovers[publickeylist[i].String()]) ||
-						payload.PayloadScript == transaction_zether_payload_script.SCRIPT_CONDITIONAL_PAYMENT {
+						payload.PayloadScript == transaction_zether_payload_script.SCRIPT_CONDITIONAL_PAYMENT || payload.PayloadScript == transaction_zether_payload_script.SCRIPT_UNSTAKING {
 						update = false
 					}
 				}
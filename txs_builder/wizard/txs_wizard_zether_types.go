@@ -8,6 +8,10 @@ type WizardZetherPayloadExtraStaking struct {
 	WizardZetherPayloadExtra `json:"-" msgpack:"-"`
 }
 
+type WizardZetherPayloadExtraUnstaking struct {
+	WizardZetherPayloadExtra `json:"-" msgpack:""`
+}
+
 type WizardZetherPayloadExtraStakingReward struct {
 	WizardZetherPayloadExtra `json:"-"  msgpack:""`
 	Reward                   uint64 `json:"reward" msgpack:"reward"`
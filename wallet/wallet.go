@@ -23,12 +23,21 @@ type Wallet struct {
 	Loaded                  bool                            `json:"loaded" msgpack:"loaded"`
 	DelegatesCount          int                             `json:"delegatesCount" msgpack:"delegatesCount"`
 	addressesMap            map[string]*wallet_address.WalletAddress
+	txsIndex                map[string]*WalletTxIndex
+	payments                map[string][]*WalletPayment
+	messages                map[string][]*WalletMessage
+	forgingStats            map[string]*WalletForgingStats
+	txsIndexRecords         *walletRecords
+	forgingStatsRecords     *walletRecords
 	forging                 *forging.Forging
 	mempool                 *mempool.Mempool
 	addressBalanceDecryptor *address_balance_decryptor.AddressBalanceDecryptor
 	updateNewChainUpdate    *multicast.MulticastChannel[*blockchain_types.BlockchainUpdates]
-	nonHardening            bool         `json:"nonHardening" msgpack:"nonHardening"`
-	Lock                    sync.RWMutex `json:"-" msgpack:"-"`
+	updateNewChainTxs       *multicast.MulticastChannel[[]*blockchain_types.BlockchainTransactionUpdate]
+	UpdatePaymentConfirmed  *multicast.MulticastChannel[*WalletPayment]
+	networkAccounts         WalletNetworkAccounts //set only when there is no local chain
+	nonHardening            bool                  `json:"nonHardening" msgpack:"nonHardening"`
+	Lock                    sync.RWMutex          `json:"-" msgpack:"-"`
 }
 
 func createWallet(forging *forging.Forging, mempool *mempool.Mempool, addressBalanceDecryptor *address_balance_decryptor.AddressBalanceDecryptor, updateNewChainUpdate *multicast.MulticastChannel[*blockchain_types.BlockchainUpdates]) (wallet *Wallet) {
@@ -37,6 +46,9 @@ func createWallet(forging *forging.Forging, mempool *mempool.Mempool, addressBal
 		mempool:                 mempool,
 		updateNewChainUpdate:    updateNewChainUpdate,
 		addressBalanceDecryptor: addressBalanceDecryptor,
+		UpdatePaymentConfirmed:  multicast.NewMulticastChannel[*WalletPayment](),
+		txsIndexRecords:         createWalletRecords("wallet-txs-index", config.WALLET_TXS_INDEX_MAX),
+		forgingStatsRecords:     createWalletRecords("wallet-forging-stats", 0),
 	}
 	wallet.clearWallet()
 	return
@@ -52,6 +64,9 @@ func (wallet *Wallet) clearWallet() {
 	wallet.CountImportedIndex = 0
 	wallet.Addresses = make([]*wallet_address.WalletAddress, 0)
 	wallet.addressesMap = make(map[string]*wallet_address.WalletAddress)
+	wallet.clearTxsIndex()
+	wallet.forgingStats = make(map[string]*WalletForgingStats)
+	wallet.forgingStatsRecords.clear()
 	wallet.Encryption = createEncryption(wallet)
 	wallet.nonHardening = false
 	wallet.setLoaded(false)
@@ -82,13 +97,16 @@ func CreateWallet(forging *forging.Forging, mempool *mempool.Mempool, addressBal
 	return wallet, nil
 }
 
-func (wallet *Wallet) InitializeWallet(updateNewChainUpdate *multicast.MulticastChannel[*blockchain_types.BlockchainUpdates]) {
+func (wallet *Wallet) InitializeWallet(updateNewChainUpdate *multicast.MulticastChannel[*blockchain_types.BlockchainUpdates], updateNewChainTxs *multicast.MulticastChannel[[]*blockchain_types.BlockchainTransactionUpdate]) {
 
 	wallet.Lock.Lock()
 	wallet.updateNewChainUpdate = updateNewChainUpdate
+	wallet.updateNewChainTxs = updateNewChainTxs
 	wallet.Lock.Unlock()
 
 	if config.CONSENSUS == config.CONSENSUS_TYPE_FULL {
 		wallet.processRefreshWallets()
+		wallet.processTxsIndex()
+		wallet.processForgingStats()
 	}
 }
@@ -3,6 +3,7 @@ package wallet
 import (
 	"context"
 	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -17,6 +18,7 @@ import (
 	"pandora-pay/blockchain/data_storage/registrations"
 	"pandora-pay/blockchain/data_storage/registrations/registration"
 	"pandora-pay/blockchain/transactions/transaction/transaction_simple/transaction_simple_extra"
+	"pandora-pay/config"
 	"pandora-pay/config/config_coins"
 	"pandora-pay/cryptography"
 	"pandora-pay/cryptography/crypto"
@@ -90,9 +92,32 @@ func (wallet *Wallet) CliListAddresses(cmd string, ctx context.Context) (err err
 	for i, walletAddress := range wallet.Addresses {
 		addresses[i] = &Address{publicKey: helpers.CloneBytes(walletAddress.PublicKey), name: walletAddress.Name, addressString: walletAddress.GetAddress(false), addressRegisteredString: walletAddress.GetAddress(true)}
 	}
+	networkAccounts := wallet.networkAccounts
 	wallet.Lock.RUnlock()
 
-	if err = store.StoreBlockchain.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+	if networkAccounts != nil {
+
+		publicKeys := make([][]byte, len(addresses))
+		for i, address := range addresses {
+			publicKeys[i] = address.publicKey
+		}
+
+		var regs []*registration.Registration
+		var accs []*account.Account
+		if regs, accs, err = networkAccounts(publicKeys, config_coins.NATIVE_ASSET_FULL); err != nil {
+			return
+		}
+
+		//only the native accounts are read from the network
+		ast := &asset.Asset{Name: config_coins.NATIVE_ASSET_NAME}
+		for i := range addresses {
+			addresses[i].registration = regs[i]
+			if accs[i] != nil {
+				addresses[i].assetsList = append(addresses[i].assetsList, &AddressAsset{accs[i].Balance.Amount, config_coins.NATIVE_ASSET_FULL, ast})
+			}
+		}
+
+	} else if err = store.StoreBlockchain.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
 
 		dataStorage := data_storage.NewDataStorage(reader)
 
@@ -413,6 +438,60 @@ func (wallet *Wallet) initWalletCLI() {
 
 	}
 
+	cliShowMessages := func(cmd string, ctx context.Context) (err error) {
+
+		addr, _, _, err := wallet.CliSelectAddress("Select Address to show the messages", ctx)
+		if err != nil {
+			return
+		}
+
+		messages := wallet.GetMessages(addr.PublicKey, true)
+
+		gui.GUI.OutputWrite("Messages")
+		gui.GUI.OutputWrite("---------------------")
+		for i, message := range messages {
+			direction := "Received"
+			if message.Sent {
+				direction = "Sent"
+			}
+			gui.GUI.OutputWrite(fmt.Sprintf("%d) %8s Block %d Tx %s Amount %s %s", i, direction, message.BlockHeight, base64.StdEncoding.EncodeToString(message.TxHash), strconv.FormatFloat(config_coins.ConvertToBase(message.Amount), 'f', config_coins.DECIMAL_SEPARATOR, 64), base64.StdEncoding.EncodeToString(message.Asset)))
+			if len(message.PaymentID) > 0 {
+				gui.GUI.OutputWrite(fmt.Sprintf("%18s: %s", "PaymentID", hex.EncodeToString(message.PaymentID)))
+			}
+			gui.GUI.OutputWrite(fmt.Sprintf("%18s: %s", "Memo", string(message.Memo)))
+		}
+		gui.GUI.OutputWrite(fmt.Sprintf("%18s %d", "Total:", len(messages)))
+
+		return
+	}
+
+	cliForgingStats := func(cmd string, ctx context.Context) (err error) {
+
+		addr, _, _, err := wallet.CliSelectAddress("Select Address to show the forging stats", ctx)
+		if err != nil {
+			return
+		}
+
+		stats := wallet.GetForgingStats(addr.PublicKey, true)
+
+		gui.GUI.OutputWrite("Forging stats")
+		gui.GUI.OutputWrite("---------------------")
+		gui.GUI.OutputWrite(fmt.Sprintf("%22s: %d", "Blocks forged", stats.BlocksForged))
+		gui.GUI.OutputWrite(fmt.Sprintf("%22s: %s", "Rewards", strconv.FormatFloat(config_coins.ConvertToBase(stats.Rewards), 'f', config_coins.DECIMAL_SEPARATOR, 64)))
+		gui.GUI.OutputWrite(fmt.Sprintf("%22s: %d", "Opportunities", stats.Opportunities))
+		gui.GUI.OutputWrite(fmt.Sprintf("%22s: %d", "Missed opportunities", stats.GetMissedOpportunities()))
+		gui.GUI.OutputWrite(fmt.Sprintf("%22s: %.4f", "Expected blocks", stats.ExpectedBlocks))
+		gui.GUI.OutputWrite(fmt.Sprintf("%22s: %.6f", "Expected rate", stats.GetExpectedRate()))
+		gui.GUI.OutputWrite(fmt.Sprintf("%22s: %.6f", "Actual rate", stats.GetActualRate()))
+
+		gui.GUI.OutputWrite("Forged blocks")
+		for i, block := range stats.History {
+			gui.GUI.OutputWrite(fmt.Sprintf("%d) Block %d Staking %s Reward %s", i, block.BlockHeight, strconv.FormatFloat(config_coins.ConvertToBase(block.StakingAmount), 'f', config_coins.DECIMAL_SEPARATOR, 64), strconv.FormatFloat(config_coins.ConvertToBase(block.Reward), 'f', config_coins.DECIMAL_SEPARATOR, 64)))
+		}
+
+		return
+	}
+
 	cliShowMnemonic := func(cmd string, ctx context.Context) (err error) {
 
 		gui.GUI.OutputWrite("Mnemonic")
@@ -470,6 +549,24 @@ func (wallet *Wallet) initWalletCLI() {
 		return
 	}
 
+	cliScanAddresses := func(cmd string, ctx context.Context) (err error) {
+
+		gapLimit := gui.GUI.OutputReadInt("Gap limit. Leave empty for "+strconv.Itoa(config.WALLET_SCAN_GAP_LIMIT), true, config.WALLET_SCAN_GAP_LIMIT, func(value int) bool {
+			return value > 0
+		})
+
+		gui.GUI.OutputWrite("Scanning addresses...")
+
+		var found int
+		if found, err = wallet.ScanAddresses(gapLimit, true); err != nil {
+			return
+		}
+
+		gui.GUI.OutputWrite("Addresses found: " + strconv.Itoa(found))
+
+		return wallet.CliListAddresses(cmd, ctx)
+	}
+
 	cliImportEntropy := func(cmd string, ctx context.Context) (err error) {
 
 		gui.GUI.OutputWrite("WARNING!!! THIS COMMAND WILL DELETE YOUR EXISTING WALLET!\n\n")
@@ -562,6 +659,22 @@ func (wallet *Wallet) initWalletCLI() {
 		return
 	}
 
+	cliChangePassword := func(cmd string, ctx context.Context) (err error) {
+
+		oldPassword := gui.GUI.OutputReadString("Current password of the wallet")
+		newPassword := gui.GUI.OutputReadString("New password for encrypting wallet")
+		difficulty := gui.GUI.OutputReadInt("Difficulty for encryption", false, 0, func(value int) bool {
+			return value >= 1 && value <= 10
+		})
+
+		gui.GUI.OutputWrite("Wallet re-encrypting...")
+
+		if err = wallet.Encryption.ChangePassword(oldPassword, newPassword, difficulty); err == nil {
+			gui.GUI.OutputWrite("Wallet password was changed successfully")
+		}
+		return
+	}
+
 	cliCreatePair := func(cmd string, ctx context.Context) (err error) {
 		key := addresses.GenerateNewPrivateKey()
 		pub := key.GeneratePublicKey()
@@ -664,18 +777,22 @@ func (wallet *Wallet) initWalletCLI() {
 	gui.GUI.CommandDefineCallback("Clear & Create new empty Wallet", cliClearWallet, wallet.Loaded)
 	gui.GUI.CommandDefineCallback("Show Mnemnonic", cliShowMnemonic, wallet.Loaded)
 	gui.GUI.CommandDefineCallback("Import Mnemnonic", cliImportMnemonic, wallet.Loaded)
+	gui.GUI.CommandDefineCallback("Scan Addresses", cliScanAddresses, wallet.Loaded)
 	gui.GUI.CommandDefineCallback("Show Entropy", cliShowEntropy, wallet.Loaded)
 	gui.GUI.CommandDefineCallback("Import Entropy", cliImportEntropy, wallet.Loaded)
 	gui.GUI.CommandDefineCallback("Show Address Secret Key", cliShowAddressSecretKey, wallet.Loaded)
 	gui.GUI.CommandDefineCallback("Import Address Secret Key", cliImportAddressSecretKey, wallet.Loaded)
 	gui.GUI.CommandDefineCallback("Remove Address", cliRemoveAddress, wallet.Loaded)
 	gui.GUI.CommandDefineCallback("Export Staked Staked Address", cliExportSharedStakedAddress, wallet.Loaded)
+	gui.GUI.CommandDefineCallback("Show Messages", cliShowMessages, wallet.Loaded)
+	gui.GUI.CommandDefineCallback("Forging stats", cliForgingStats, wallet.Loaded)
 	gui.GUI.CommandDefineCallback("Export Addresses", cliExportAddresses, wallet.Loaded)
 	gui.GUI.CommandDefineCallback("Export Address JSON", cliExportAddressJSON, wallet.Loaded)
 	gui.GUI.CommandDefineCallback("Import Address JSON", cliImportAddressJSON, wallet.Loaded)
 	gui.GUI.CommandDefineCallback("Export Wallet JSON", cliExportWalletJSON, wallet.Loaded)
 	gui.GUI.CommandDefineCallback("Import Wallet JSON", cliImportWalletJSON, wallet.Loaded)
 	gui.GUI.CommandDefineCallback("Encrypt Wallet", cliEncryptWallet, wallet.Loaded)
+	gui.GUI.CommandDefineCallback("Change Password", cliChangePassword, wallet.Loaded)
 	gui.GUI.CommandDefineCallback("Remove Encryption", cliRemoveEncryption, wallet.Loaded)
 	gui.GUI.CommandDefineCallback("Decrypt Wallet", cliDecryptWallet, !wallet.Loaded)
 
@@ -22,7 +22,10 @@ type DecryptZetherPayloadOutput struct {
 	ReceivedAmount        uint64 `json:"receivedAmount" msgpack:"receivedAmount"`
 	RecipientIndex        int    `json:"recipientIndex" msgpack:"recipientIndex"`
 	Message               []byte `json:"message" msgpack:"message"`
+	PaymentID             []byte `json:"paymentId,omitempty" msgpack:"paymentId,omitempty"`
+	Memo                  []byte `json:"memo,omitempty" msgpack:"memo,omitempty"`
 	Asset                 []byte `json:"asset" msgpack:"asset"`
+	PublicKey             []byte `json:"publicKey" msgpack:"publicKey"`
 }
 
 type DecryptTxZether struct {
@@ -67,6 +70,7 @@ func (w *Wallet) DecryptTx(tx *transaction.Transaction, walletPublicKey []byte)
 					decyptedZetherPayload := &DecryptZetherPayloadOutput{
 						RecipientIndex: -1,
 						Asset:          payload.Asset,
+						PublicKey:      publicKey,
 					}
 					output.ZetherTx.Payloads[t] = decyptedZetherPayload
 
@@ -196,6 +200,14 @@ func (w *Wallet) DecryptTx(tx *transaction.Transaction, walletPublicKey []byte)
 				}
 			}
 		}
+
+		for _, decryptedPayload := range output.ZetherTx.Payloads {
+			if decryptedPayload != nil && len(decryptedPayload.Message) > 0 {
+				message := transaction_data.DeserializeTransactionDataMessage(decryptedPayload.Message)
+				decryptedPayload.PaymentID = message.PaymentID
+				decryptedPayload.Memo = message.Memo
+			}
+		}
 	}
 
 	return output, nil
@@ -128,6 +128,46 @@ func (self *WalletEncryption) RemoveEncryption() (err error) {
 	return
 }
 
+func (self *WalletEncryption) ChangePassword(oldPassword, newPassword string, difficulty int) (err error) {
+	self.wallet.Lock.Lock()
+	defer self.wallet.Lock.Unlock()
+
+	if !self.wallet.Loaded {
+		return errors.New("Wallet was not loaded!")
+	}
+	if self.Encrypted == ENCRYPTED_VERSION_PLAIN_TEXT {
+		return errors.New("Wallet is not encrypted!")
+	}
+	if self.password != oldPassword {
+		return errors.New("Password is not matching")
+	}
+	if difficulty <= 0 || difficulty > 10 {
+		return errors.New("Difficulty must be in the interval [1,10]")
+	}
+
+	oldSalt, oldDifficulty, oldCipher := self.Salt, self.Difficulty, self.encryptionCipher
+
+	self.password = newPassword
+	self.Salt = helpers.RandomBytes(32)
+	self.Difficulty = difficulty
+
+	//the seed, the addresses and the records are re-encrypted within a single StoreWallet update. If it fails nothing was written and only the memory is restored
+	if err = self.createEncryptionCipher(); err == nil {
+		err = self.wallet.saveWalletEntire(false)
+	}
+
+	if err != nil {
+		self.password = oldPassword
+		self.Salt = oldSalt
+		self.Difficulty = oldDifficulty
+		self.encryptionCipher = oldCipher
+		return
+	}
+
+	globals.MainEvents.BroadcastEvent("wallet/changed-password", true)
+	return
+}
+
 func (self *WalletEncryption) Logout() (err error) {
 	self.wallet.Lock.Lock()
 	if !self.wallet.Loaded {
@@ -0,0 +1,64 @@
+package wallet
+
+import (
+	"github.com/stretchr/testify/assert"
+	"pandora-pay/blockchain/forging"
+	"pandora-pay/gui"
+	"pandora-pay/gui/gui_non_interactive"
+	"pandora-pay/helpers"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_memory"
+	"testing"
+)
+
+//createTestWallet creates a new wallet in memory stores
+func createTestWallet(t *testing.T) *Wallet {
+
+	var err error
+	gui.GUI, err = gui_non_interactive.CreateGUINonInteractive()
+	assert.Nil(t, err)
+
+	for _, s := range []**store.Store{&store.StoreBlockchain, &store.StoreWallet} {
+		db, err := store_db_memory.CreateStoreDBMemory("wallet")
+		assert.Nil(t, err)
+		*s = &store.Store{Name: "wallet", Opened: true, DB: db}
+	}
+
+	forging, err := forging.CreateForging(nil, nil)
+	assert.Nil(t, err)
+
+	wallet, err := CreateWallet(forging, nil, nil)
+	assert.Nil(t, err)
+	return wallet
+}
+
+func TestWalletChangePassword(t *testing.T) {
+
+	wallet := createTestWallet(t)
+	assert.Nil(t, wallet.Encryption.Encrypt("old", 1))
+
+	txHash := helpers.RandomBytes(32)
+	wallet.Lock.Lock()
+	txIndex := &WalletTxIndex{TxHash: txHash, BlockHeight: 5}
+	wallet.addTxIndex(txIndex)
+	assert.Nil(t, wallet.saveTxsIndex([]*WalletTxIndex{txIndex}, nil))
+	wallet.Lock.Unlock()
+
+	assert.NotNil(t, wallet.Encryption.ChangePassword("wrong", "new", 1))
+	assert.Nil(t, wallet.Encryption.ChangePassword("old", "new", 1))
+
+	reload := func(password string) (*Wallet, error) {
+		loaded := createWallet(wallet.forging, nil, nil, nil)
+		return loaded, loaded.loadWallet(password, true)
+	}
+
+	_, err := reload("old")
+	assert.NotNil(t, err, "the old password is rejected")
+
+	loaded, err := reload("new")
+	assert.Nil(t, err)
+	assert.Equal(t, wallet.Seed, loaded.Seed)
+	assert.Equal(t, wallet.Count, len(loaded.Addresses))
+	assert.Equal(t, wallet.Addresses[0].PublicKey, loaded.Addresses[0].PublicKey)
+	assert.Equal(t, uint64(5), loaded.txsIndex[string(txHash)].BlockHeight, "the records are re-encrypted with the new password")
+}
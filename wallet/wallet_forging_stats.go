@@ -0,0 +1,272 @@
+package wallet
+
+import (
+	"github.com/vmihailenco/msgpack/v5"
+	"pandora-pay/blockchain/forging"
+	"pandora-pay/config"
+	"pandora-pay/gui"
+	"pandora-pay/helpers"
+	"pandora-pay/recovery"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_interface"
+)
+
+//WalletForgedBlock is a block forged by a wallet address
+type WalletForgedBlock struct {
+	BlockHeight   uint64 `json:"blockHeight" msgpack:"blockHeight"`
+	BlockHash     []byte `json:"blockHash" msgpack:"blockHash"`
+	StakingAmount uint64 `json:"stakingAmount" msgpack:"stakingAmount"`
+	Reward        uint64 `json:"reward" msgpack:"reward"`
+}
+
+//WalletForgingStats is the block production history of a wallet address. An opportunity is a block height in which the address was forging with an active stake
+type WalletForgingStats struct {
+	PublicKey             []byte               `json:"publicKey" msgpack:"publicKey"`
+	BlocksForged          uint64               `json:"blocksForged" msgpack:"blocksForged"`
+	Rewards               uint64               `json:"rewards" msgpack:"rewards"`
+	Opportunities         uint64               `json:"opportunities" msgpack:"opportunities"`
+	ExpectedBlocks        float64              `json:"expectedBlocks" msgpack:"expectedBlocks"`
+	LastOpportunityHeight uint64               `json:"lastOpportunityHeight" msgpack:"lastOpportunityHeight"`
+	History               []*WalletForgedBlock `json:"history" msgpack:"history"`
+}
+
+func (stats *WalletForgingStats) GetMissedOpportunities() uint64 {
+	if stats.Opportunities > stats.BlocksForged {
+		return stats.Opportunities - stats.BlocksForged
+	}
+	return 0
+}
+
+//GetExpectedRate is the number of blocks expected for every opportunity
+func (stats *WalletForgingStats) GetExpectedRate() float64 {
+	if stats.Opportunities == 0 {
+		return 0
+	}
+	return stats.ExpectedBlocks / float64(stats.Opportunities)
+}
+
+//GetActualRate is the number of blocks forged for every opportunity
+func (stats *WalletForgingStats) GetActualRate() float64 {
+	if stats.Opportunities == 0 {
+		return 0
+	}
+	return float64(stats.BlocksForged) / float64(stats.Opportunities)
+}
+
+//must be locked before
+func (wallet *Wallet) getForgingStats(publicKey []byte) *WalletForgingStats {
+	stats := wallet.forgingStats[string(publicKey)]
+	if stats == nil {
+		stats = &WalletForgingStats{PublicKey: publicKey}
+		wallet.forgingStats[string(publicKey)] = stats
+	}
+	return stats
+}
+
+//must be locked before. Every address has its stats stored under its own key
+func (wallet *Wallet) saveForgingStats(put []*WalletForgingStats, remove [][]byte) error {
+	return store.StoreWallet.DB.Update(func(writer store_db_interface.StoreDBTransactionInterface) (err error) {
+		for _, publicKey := range remove {
+			wallet.forgingStatsRecords.remove(writer, string(publicKey))
+		}
+		for _, stats := range put {
+			if _, err = wallet.forgingStatsRecords.put(writer, wallet.Encryption, string(stats.PublicKey), stats); err != nil {
+				return
+			}
+		}
+		return
+	})
+}
+
+//must be locked before
+func (wallet *Wallet) removeForgingStats(publicKey []byte) error {
+	if wallet.forgingStats[string(publicKey)] == nil {
+		return nil
+	}
+	delete(wallet.forgingStats, string(publicKey))
+	return wallet.saveForgingStats(nil, [][]byte{publicKey})
+}
+
+//must be locked before
+func (wallet *Wallet) loadForgingStats(reader store_db_interface.StoreDBTransactionInterface) error {
+	return wallet.forgingStatsRecords.load(reader, wallet.Encryption, func(data []byte) (string, error) {
+		stats := &WalletForgingStats{}
+		if err := msgpack.Unmarshal(data, stats); err != nil {
+			return "", err
+		}
+		wallet.forgingStats[string(stats.PublicKey)] = stats
+		return string(stats.PublicKey), nil
+	})
+}
+
+func (wallet *Wallet) addBlockForged(blockForged *forging.ForgingBlockForged) (err error) {
+
+	wallet.Lock.Lock()
+	defer wallet.Lock.Unlock()
+
+	if !wallet.Loaded || wallet.addressesMap[string(blockForged.PublicKey)] == nil {
+		return
+	}
+
+	stats := wallet.getForgingStats(blockForged.PublicKey)
+
+	stats.BlocksForged += 1
+	if err = helpers.SafeUint64Add(&stats.Rewards, blockForged.Reward); err != nil {
+		return
+	}
+
+	stats.History = append(stats.History, &WalletForgedBlock{blockForged.BlockHeight, blockForged.BlockHash, blockForged.StakingAmount, blockForged.Reward})
+	if len(stats.History) > config.WALLET_FORGING_STATS_HISTORY {
+		stats.History = stats.History[len(stats.History)-config.WALLET_FORGING_STATS_HISTORY:]
+	}
+
+	return wallet.saveForgingStats([]*WalletForgingStats{stats}, nil)
+}
+
+//removeBlocksForged reverts the blocks forged that were orphaned by a reorg. Only the blocks in the history can be reverted
+func (wallet *Wallet) removeBlocksForged(removedBlocks [][]byte) (err error) {
+
+	wallet.Lock.Lock()
+	defer wallet.Lock.Unlock()
+
+	if !wallet.Loaded {
+		return
+	}
+
+	removed := make(map[string]bool)
+	for _, hash := range removedBlocks {
+		removed[string(hash)] = true
+	}
+
+	changed := []*WalletForgingStats{}
+	for _, stats := range wallet.forgingStats {
+
+		history := make([]*WalletForgedBlock, 0, len(stats.History))
+		for _, block := range stats.History {
+			if !removed[string(block.BlockHash)] {
+				history = append(history, block)
+				continue
+			}
+			if stats.BlocksForged > 0 {
+				stats.BlocksForged -= 1
+			}
+			if stats.Rewards >= block.Reward {
+				stats.Rewards -= block.Reward
+			} else {
+				stats.Rewards = 0
+			}
+		}
+
+		if len(history) != len(stats.History) {
+			stats.History = history
+			changed = append(changed, stats)
+		}
+	}
+
+	if len(changed) > 0 {
+		return wallet.saveForgingStats(changed, nil)
+	}
+	return
+}
+
+func (wallet *Wallet) addBlockOpportunities(opportunities *forging.ForgingBlockOpportunities) (err error) {
+
+	wallet.Lock.Lock()
+	defer wallet.Lock.Unlock()
+
+	if !wallet.Loaded {
+		return
+	}
+
+	changed := []*WalletForgingStats{}
+	for _, opportunity := range opportunities.Addresses {
+
+		if wallet.addressesMap[string(opportunity.PublicKey)] == nil {
+			continue
+		}
+
+		//the workers broadcast the same height every time the block template changes
+		stats := wallet.getForgingStats(opportunity.PublicKey)
+		if stats.LastOpportunityHeight >= opportunities.BlockHeight {
+			continue
+		}
+
+		stats.Opportunities += 1
+		stats.ExpectedBlocks += opportunities.GetExpectedBlocks(opportunity.StakingAmount)
+		stats.LastOpportunityHeight = opportunities.BlockHeight
+		changed = append(changed, stats)
+	}
+
+	if len(changed) > 0 {
+		return wallet.saveForgingStats(changed, nil)
+	}
+	return
+}
+
+//processForgingStats records the blocks forged and the forging opportunities of the wallet addresses. The blocks orphaned by a reorg are reverted
+func (wallet *Wallet) processForgingStats() {
+
+	recovery.SafeGo(func() {
+
+		blockForgedCn := wallet.forging.BlockForged.AddListener()
+		defer wallet.forging.BlockForged.RemoveChannel(blockForgedCn)
+
+		blockOpportunitiesCn := wallet.forging.BlockOpportunities.AddListener()
+		defer wallet.forging.BlockOpportunities.RemoveChannel(blockOpportunitiesCn)
+
+		updateNewChainCn := wallet.updateNewChainUpdate.AddListener()
+		defer wallet.updateNewChainUpdate.RemoveChannel(updateNewChainCn)
+
+		for {
+			select {
+			case blockForged, ok := <-blockForgedCn:
+				if !ok {
+					return
+				}
+				if err := wallet.addBlockForged(blockForged); err != nil {
+					gui.GUI.Error("Error storing wallet forged block", err)
+				}
+			case opportunities, ok := <-blockOpportunitiesCn:
+				if !ok {
+					return
+				}
+				if err := wallet.addBlockOpportunities(opportunities); err != nil {
+					gui.GUI.Error("Error storing wallet forging opportunities", err)
+				}
+			case update, ok := <-updateNewChainCn:
+				if !ok {
+					return
+				}
+				if update.Reorg == nil {
+					continue
+				}
+				if err := wallet.removeBlocksForged(update.Reorg.RemovedBlocks); err != nil {
+					gui.GUI.Error("Error reverting wallet forged blocks", err)
+				}
+			}
+		}
+
+	})
+
+}
+
+func (wallet *Wallet) GetForgingStats(publicKey []byte, lock bool) *WalletForgingStats {
+
+	if lock {
+		wallet.Lock.RLock()
+		defer wallet.Lock.RUnlock()
+	}
+
+	stats := wallet.forgingStats[string(publicKey)]
+	if stats == nil {
+		return &WalletForgingStats{PublicKey: publicKey}
+	}
+
+	clone := *stats
+	clone.History = make([]*WalletForgedBlock, len(stats.History))
+	for i, block := range stats.History {
+		blockClone := *block
+		clone.History[i] = &blockClone
+	}
+	return &clone
+}
@@ -0,0 +1,30 @@
+package wallet
+
+import (
+	"github.com/stretchr/testify/assert"
+	"pandora-pay/blockchain/forging"
+	"pandora-pay/helpers"
+	"testing"
+)
+
+func TestWalletRemoveBlocksForged(t *testing.T) {
+
+	wallet := createTestWallet(t)
+	publicKey := wallet.Addresses[0].PublicKey
+
+	orphaned, kept := helpers.RandomBytes(32), helpers.RandomBytes(32)
+	assert.Nil(t, wallet.addBlockForged(&forging.ForgingBlockForged{publicKey, 10, orphaned, 100, 5}))
+	assert.Nil(t, wallet.addBlockForged(&forging.ForgingBlockForged{publicKey, 11, kept, 100, 7}))
+
+	assert.Nil(t, wallet.removeBlocksForged([][]byte{orphaned, helpers.RandomBytes(32)}))
+
+	stats := wallet.GetForgingStats(publicKey, true)
+	assert.Equal(t, uint64(1), stats.BlocksForged)
+	assert.Equal(t, uint64(7), stats.Rewards)
+	assert.Len(t, stats.History, 1)
+	assert.Equal(t, kept, stats.History[0].BlockHash)
+
+	loaded := createWallet(wallet.forging, nil, nil, nil)
+	assert.Nil(t, loaded.loadWallet("", true))
+	assert.Equal(t, uint64(1), loaded.GetForgingStats(publicKey, true).BlocksForged, "the reverted stats are stored")
+}
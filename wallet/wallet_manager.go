@@ -103,6 +103,27 @@ func (wallet *Wallet) GetWalletAddressByPublicKey(publicKey []byte, lock bool) *
 	return wallet.addressesMap[string(publicKey)].Clone()
 }
 
+//GetForgingPrivateKey returns the key used for forging by the address. Delegated stakes only have the shared staked key
+func (wallet *Wallet) GetForgingPrivateKey(publicKey []byte, lock bool) *addresses.PrivateKey {
+
+	if lock {
+		wallet.Lock.RLock()
+		defer wallet.Lock.RUnlock()
+	}
+
+	addr := wallet.addressesMap[string(publicKey)]
+	if addr == nil {
+		return nil
+	}
+	if addr.PrivateKey != nil {
+		return addr.PrivateKey
+	}
+	if addr.SharedStaked != nil {
+		return addr.SharedStaked.PrivateKey
+	}
+	return nil
+}
+
 func (wallet *Wallet) ImportSecretKey(name string, secret []byte, staked, spendRequired bool) (*wallet_address.WalletAddress, error) {
 
 	secretChild, err := bip32.Deserialize(secret)
@@ -407,6 +428,9 @@ func (wallet *Wallet) RemoveAddressByIndex(index int, lock bool) (bool, error) {
 	if err := wallet.saveWallet(index, index+1, wallet.Count, false); err != nil {
 		return false, err
 	}
+	if err := wallet.removeForgingStats(adr.PublicKey); err != nil {
+		return false, err
+	}
 	globals.MainEvents.BroadcastEvent("wallet/removed", adr)
 
 	return true, nil
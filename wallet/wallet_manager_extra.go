@@ -8,6 +8,7 @@ import (
 	"pandora-pay/addresses"
 	"pandora-pay/blockchain/data_storage/accounts/account"
 	"pandora-pay/blockchain/data_storage/registrations/registration"
+	"pandora-pay/config"
 	"pandora-pay/config/config_coins"
 	"pandora-pay/config/config_stake"
 	"pandora-pay/gui"
@@ -98,11 +99,7 @@ func (wallet *Wallet) ImportMnemonic(mnemonic string) (err error) {
 
 	wallet.Seed = seedExtended.Serialize()
 
-	if _, err = wallet.AddNewAddress(false, "", false, false, true); err != nil {
-		return
-	}
-
-	return
+	return wallet.importScanAddresses()
 }
 
 func (wallet *Wallet) ImportEntropy(entropy []byte) (err error) {
@@ -136,10 +133,22 @@ func (wallet *Wallet) ImportEntropy(entropy []byte) (err error) {
 
 	wallet.Seed = seedExtended.Serialize()
 
-	if _, err = wallet.AddNewAddress(false, "", false, false, true); err != nil {
+	return wallet.importScanAddresses()
+}
+
+//must be locked before
+func (wallet *Wallet) importScanAddresses() (err error) {
+
+	if _, err = wallet.scanAddresses(config.WALLET_SCAN_GAP_LIMIT); err != nil {
 		return
 	}
 
+	if wallet.Count == 0 {
+		if _, err = wallet.AddNewAddress(false, "", false, false, true); err != nil {
+			return
+		}
+	}
+
 	return
 }
 
@@ -0,0 +1,66 @@
+package wallet
+
+import (
+	"pandora-pay/blockchain/blockchain_types"
+)
+
+//WalletMessage is an encrypted memo sent or received by a wallet address
+type WalletMessage struct {
+	TxHash         []byte `json:"txHash" msgpack:"txHash"`
+	PayloadIndex   int    `json:"payloadIndex" msgpack:"payloadIndex"`
+	Sent           bool   `json:"sent" msgpack:"sent"`
+	Amount         uint64 `json:"amount" msgpack:"amount"`
+	Asset          []byte `json:"asset" msgpack:"asset"`
+	PaymentID      []byte `json:"paymentId,omitempty" msgpack:"paymentId,omitempty"`
+	Memo           []byte `json:"memo" msgpack:"memo"`
+	BlockHeight    uint64 `json:"blockHeight" msgpack:"blockHeight"`
+	BlockTimestamp uint64 `json:"blockTimestamp" msgpack:"blockTimestamp"`
+	PublicKey      []byte `json:"-" msgpack:"publicKey"`
+}
+
+func getMessagesFromTx(txUpdate *blockchain_types.BlockchainTransactionUpdate, decrypted *DecryptedTx) []*WalletMessage {
+
+	var messages []*WalletMessage
+	for t, payload := range decrypted.ZetherTx.Payloads {
+		if payload == nil || len(payload.Memo) == 0 || !(payload.WhisperSenderValid || payload.WhisperRecipientValid) {
+			continue
+		}
+
+		message := &WalletMessage{
+			TxHash:         txUpdate.TxHash,
+			PayloadIndex:   t,
+			Sent:           payload.WhisperSenderValid,
+			Amount:         payload.ReceivedAmount,
+			Asset:          payload.Asset,
+			PaymentID:      payload.PaymentID,
+			Memo:           payload.Memo,
+			BlockHeight:    txUpdate.BlockHeight,
+			BlockTimestamp: txUpdate.BlockTimestamp,
+			PublicKey:      payload.PublicKey,
+		}
+		if message.Sent {
+			message.Amount = payload.SentAmount
+		}
+
+		messages = append(messages, message)
+	}
+
+	return messages
+}
+
+func (wallet *Wallet) GetMessages(publicKey []byte, lock bool) []*WalletMessage {
+
+	if lock {
+		wallet.Lock.RLock()
+		defer wallet.Lock.RUnlock()
+	}
+
+	list := wallet.messages[string(publicKey)]
+
+	out := make([]*WalletMessage, len(list))
+	for i, message := range list {
+		clone := *message
+		out[i] = &clone
+	}
+	return out
+}
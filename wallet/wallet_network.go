@@ -0,0 +1,16 @@
+package wallet
+
+import (
+	"pandora-pay/blockchain/data_storage/accounts/account"
+	"pandora-pay/blockchain/data_storage/registrations/registration"
+)
+
+//WalletNetworkAccounts returns the verified registrations and accounts of an asset from the network
+type WalletNetworkAccounts func(publicKeys [][]byte, asset []byte) ([]*registration.Registration, []*account.Account, error)
+
+//SetNetworkAccounts is used by the nodes without a local chain, which follow the chain through the wallet consensus
+func (wallet *Wallet) SetNetworkAccounts(networkAccounts WalletNetworkAccounts) {
+	wallet.Lock.Lock()
+	defer wallet.Lock.Unlock()
+	wallet.networkAccounts = networkAccounts
+}
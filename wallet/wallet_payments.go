@@ -0,0 +1,100 @@
+package wallet
+
+import (
+	"pandora-pay/blockchain/blockchain_types"
+	"pandora-pay/config"
+)
+
+//WalletPayment is an incoming Zether payload whose decrypted message contains a PaymentID
+type WalletPayment struct {
+	PaymentID      []byte `json:"paymentId" msgpack:"paymentId"`
+	TxHash         []byte `json:"txHash" msgpack:"txHash"`
+	PayloadIndex   int    `json:"payloadIndex" msgpack:"payloadIndex"`
+	PublicKey      []byte `json:"publicKey" msgpack:"publicKey"`
+	Amount         uint64 `json:"amount" msgpack:"amount"`
+	Asset          []byte `json:"asset" msgpack:"asset"`
+	BlockHeight    uint64 `json:"blockHeight" msgpack:"blockHeight"`
+	BlockTimestamp uint64 `json:"blockTimestamp" msgpack:"blockTimestamp"`
+	Confirmed      bool   `json:"confirmed" msgpack:"confirmed"`
+}
+
+func getPaymentsFromTx(txUpdate *blockchain_types.BlockchainTransactionUpdate, decrypted *DecryptedTx) []*WalletPayment {
+
+	var payments []*WalletPayment
+	for t, payload := range decrypted.ZetherTx.Payloads {
+		if payload == nil || !payload.WhisperRecipientValid || payload.WhisperSenderValid || len(payload.PaymentID) == 0 {
+			continue
+		}
+
+		payments = append(payments, &WalletPayment{
+			PaymentID:      payload.PaymentID,
+			TxHash:         txUpdate.TxHash,
+			PayloadIndex:   t,
+			PublicKey:      payload.PublicKey,
+			Amount:         payload.ReceivedAmount,
+			Asset:          payload.Asset,
+			BlockHeight:    txUpdate.BlockHeight,
+			BlockTimestamp: txUpdate.BlockTimestamp,
+		})
+	}
+
+	return payments
+}
+
+func (wallet *Wallet) confirmPayments(chainHeight uint64) (err error) {
+
+	confirmed := []*WalletPayment{}
+
+	wallet.Lock.Lock()
+
+	if wallet.Loaded {
+		changed := make(map[string]bool)
+		txsIndex := []*WalletTxIndex{}
+		for _, list := range wallet.payments {
+			for _, payment := range list {
+				if !payment.Confirmed && chainHeight >= payment.BlockHeight && chainHeight-payment.BlockHeight >= config.WALLET_PAYMENTS_CONFIRMATIONS {
+					payment.Confirmed = true
+					clone := *payment
+					confirmed = append(confirmed, &clone)
+					if !changed[string(payment.TxHash)] {
+						changed[string(payment.TxHash)] = true
+						txsIndex = append(txsIndex, wallet.txsIndex[string(payment.TxHash)])
+					}
+				}
+			}
+		}
+
+		if len(txsIndex) > 0 {
+			err = wallet.saveTxsIndex(txsIndex, nil)
+		}
+	}
+
+	wallet.Lock.Unlock()
+
+	if err != nil {
+		return
+	}
+
+	for _, payment := range confirmed {
+		wallet.UpdatePaymentConfirmed.Broadcast(payment)
+	}
+
+	return
+}
+
+func (wallet *Wallet) GetPayments(paymentID []byte, lock bool) []*WalletPayment {
+
+	if lock {
+		wallet.Lock.RLock()
+		defer wallet.Lock.RUnlock()
+	}
+
+	list := wallet.payments[string(paymentID)]
+
+	out := make([]*WalletPayment, len(list))
+	for i, payment := range list {
+		clone := *payment
+		out[i] = &clone
+	}
+	return out
+}
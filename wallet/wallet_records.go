@@ -0,0 +1,138 @@
+package wallet
+
+import (
+	"github.com/vmihailenco/msgpack/v5"
+	"pandora-pay/store/store_db/store_db_interface"
+	"strconv"
+)
+
+//walletRecords are wallet entries stored encrypted under their own keys "prefix-N", so the wallet is not rewritten when they change. The oldest records are pruned when there are more than limit records
+type walletRecords struct {
+	prefix  string
+	limit   int
+	start   uint64
+	end     uint64
+	indexes map[string]uint64
+	ids     map[uint64]string
+}
+
+func createWalletRecords(prefix string, limit int) *walletRecords {
+	records := &walletRecords{prefix: prefix, limit: limit}
+	records.clear()
+	return records
+}
+
+func (records *walletRecords) clear() {
+	records.start = 0
+	records.end = 0
+	records.indexes = make(map[string]uint64)
+	records.ids = make(map[uint64]string)
+}
+
+func (records *walletRecords) key(index uint64) string {
+	return records.prefix + "-" + strconv.FormatUint(index, 10)
+}
+
+func (records *walletRecords) saveBounds(writer store_db_interface.StoreDBTransactionInterface) {
+	writer.Put(records.prefix+"-start", []byte(strconv.FormatUint(records.start, 10)))
+	writer.Put(records.prefix+"-end", []byte(strconv.FormatUint(records.end, 10)))
+}
+
+func (records *walletRecords) write(writer store_db_interface.StoreDBTransactionInterface, encryption *WalletEncryption, index uint64, value any) (err error) {
+	var marshal []byte
+	if marshal, err = msgpack.Marshal(value); err != nil {
+		return
+	}
+	if marshal, err = encryption.encryptData(marshal); err != nil {
+		return
+	}
+	writer.Put(records.key(index), marshal)
+	return
+}
+
+//put stores the record and returns the ids of the records that were pruned
+func (records *walletRecords) put(writer store_db_interface.StoreDBTransactionInterface, encryption *WalletEncryption, id string, value any) (pruned []string, err error) {
+
+	index, ok := records.indexes[id]
+	if !ok {
+		index = records.end
+		records.end += 1
+		records.indexes[id] = index
+		records.ids[index] = id
+	}
+
+	if err = records.write(writer, encryption, index, value); err != nil {
+		return
+	}
+
+	for records.limit > 0 && len(records.indexes) > records.limit {
+		if prunedID, ok := records.ids[records.start]; ok {
+			records.remove(writer, prunedID)
+			pruned = append(pruned, prunedID)
+		}
+		records.start += 1
+	}
+
+	records.saveBounds(writer)
+	return
+}
+
+func (records *walletRecords) remove(writer store_db_interface.StoreDBTransactionInterface, id string) {
+	index, ok := records.indexes[id]
+	if !ok {
+		return
+	}
+	delete(records.indexes, id)
+	delete(records.ids, index)
+	writer.Delete(records.key(index))
+}
+
+//saveAll rewrites every record. It is used when the wallet is created or its encryption changes
+func (records *walletRecords) saveAll(writer store_db_interface.StoreDBTransactionInterface, encryption *WalletEncryption, getValue func(id string) any) (err error) {
+	for index := records.start; index < records.end; index++ {
+		if id, ok := records.ids[index]; ok {
+			if err = records.write(writer, encryption, index, getValue(id)); err != nil {
+				return
+			}
+		}
+	}
+	records.saveBounds(writer)
+	return
+}
+
+//load decrypts the stored records. The callback returns the id of the record
+func (records *walletRecords) load(reader store_db_interface.StoreDBTransactionInterface, encryption *WalletEncryption, callback func(data []byte) (string, error)) (err error) {
+
+	records.clear()
+
+	if data := reader.Get(records.prefix + "-start"); data != nil {
+		if records.start, err = strconv.ParseUint(string(data), 10, 64); err != nil {
+			return
+		}
+	}
+	if data := reader.Get(records.prefix + "-end"); data != nil {
+		if records.end, err = strconv.ParseUint(string(data), 10, 64); err != nil {
+			return
+		}
+	}
+
+	for index := records.start; index < records.end; index++ {
+
+		data := reader.Get(records.key(index))
+		if data == nil {
+			continue
+		}
+		if data, err = encryption.decryptData(data); err != nil {
+			return
+		}
+
+		var id string
+		if id, err = callback(data); err != nil {
+			return
+		}
+		records.indexes[id] = index
+		records.ids[index] = id
+	}
+
+	return
+}
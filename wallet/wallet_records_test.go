@@ -0,0 +1,52 @@
+package wallet
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/vmihailenco/msgpack/v5"
+	"pandora-pay/store/store_db/store_db_interface"
+	"pandora-pay/store/store_db/store_db_memory"
+	"testing"
+)
+
+func TestWalletRecords(t *testing.T) {
+
+	db, err := store_db_memory.CreateStoreDBMemory("wallet")
+	assert.Nil(t, err)
+
+	encryption := createEncryption(nil)
+	records := createWalletRecords("records", 3)
+
+	var pruned []string
+	assert.Nil(t, db.Update(func(writer store_db_interface.StoreDBTransactionInterface) (err error) {
+		for _, id := range []string{"a", "b", "c", "d"} {
+			var list []string
+			if list, err = records.put(writer, encryption, id, id+"-value"); err != nil {
+				return
+			}
+			pruned = append(pruned, list...)
+		}
+		records.remove(writer, "c")
+		_, err = records.put(writer, encryption, "b", "b-updated")
+		return
+	}))
+	assert.Equal(t, []string{"a"}, pruned, "the oldest record is pruned above the limit")
+
+	loaded := createWalletRecords("records", 3)
+	values := map[string]string{}
+	assert.Nil(t, db.View(func(reader store_db_interface.StoreDBTransactionInterface) error {
+		return loaded.load(reader, encryption, func(data []byte) (string, error) {
+			var value string
+			if err := msgpack.Unmarshal(data, &value); err != nil {
+				return "", err
+			}
+			id := value[:1]
+			values[id] = value
+			return id, nil
+		})
+	}))
+
+	assert.Equal(t, map[string]string{"b": "b-updated", "d": "d-value"}, values)
+	assert.Equal(t, records.indexes, loaded.indexes)
+	assert.Equal(t, uint64(1), loaded.start)
+	assert.Equal(t, uint64(4), loaded.end)
+}
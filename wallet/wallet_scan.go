@@ -0,0 +1,179 @@
+package wallet
+
+import (
+	"errors"
+	"pandora-pay/addresses"
+	"pandora-pay/blockchain/data_storage"
+	"pandora-pay/blockchain/data_storage/registrations/registration"
+	"pandora-pay/config/config_coins"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_interface"
+)
+
+type walletScannedAddress struct {
+	seedIndex uint32
+	reg       *registration.Registration
+}
+
+//ScanAddresses derives successive keys from the seed and re-adds every used address until gapLimit consecutive unused keys are found
+func (wallet *Wallet) ScanAddresses(gapLimit int, lock bool) (int, error) {
+
+	if lock {
+		wallet.Lock.Lock()
+		defer wallet.Lock.Unlock()
+	}
+
+	if !wallet.Loaded {
+		return 0, errors.New("Wallet was not loaded!")
+	}
+
+	return wallet.scanAddresses(gapLimit)
+}
+
+//walletScanLookup returns the registrations of the public keys and if the keys were used
+type walletScanLookup func(publicKeys [][]byte) ([]*registration.Registration, []bool, error)
+
+//the keys are looked up in batches. The nodes return at most 1024 accounts by request
+const walletScanBatchMax = 512
+
+//scanLookupLocal reads the keys from the local chain. A key is used if it is registered or it has any asset
+func scanLookupLocal(publicKeys [][]byte) (regs []*registration.Registration, used []bool, err error) {
+
+	regs = make([]*registration.Registration, len(publicKeys))
+	used = make([]bool, len(publicKeys))
+
+	err = store.StoreBlockchain.DB.View(func(reader store_db_interface.StoreDBTransactionInterface) (err error) {
+
+		dataStorage := data_storage.NewDataStorage(reader)
+
+		for i, publicKey := range publicKeys {
+
+			if regs[i], err = dataStorage.Regs.Get(string(publicKey)); err != nil {
+				return
+			}
+
+			var assetsCount uint64
+			if assetsCount, err = dataStorage.AccsCollection.GetAccountAssetsCount(publicKey); err != nil {
+				return
+			}
+
+			used[i] = regs[i] != nil || assetsCount > 0
+		}
+		return
+	})
+
+	return
+}
+
+//scanLookupNetwork reads the keys from the network when there is no local chain. Only the native accounts are read, so an unregistered key holding only other assets is not found
+func scanLookupNetwork(networkAccounts WalletNetworkAccounts) walletScanLookup {
+	return func(publicKeys [][]byte) ([]*registration.Registration, []bool, error) {
+
+		regs, accs, err := networkAccounts(publicKeys, config_coins.NATIVE_ASSET_FULL)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(regs) != len(publicKeys) || len(accs) != len(publicKeys) {
+			return nil, nil, errors.New("Network returned an invalid number of accounts")
+		}
+
+		used := make([]bool, len(publicKeys))
+		for i := range publicKeys {
+			used[i] = regs[i] != nil || accs[i] != nil
+		}
+		return regs, used, nil
+	}
+}
+
+//must be locked before
+func (wallet *Wallet) scanAddresses(gapLimit int) (int, error) {
+
+	if gapLimit <= 0 {
+		return 0, errors.New("Gap limit must be greater than zero")
+	}
+
+	lookup := scanLookupLocal
+	if wallet.networkAccounts != nil {
+		lookup = scanLookupNetwork(wallet.networkAccounts)
+	}
+
+	batchSize := gapLimit
+	if batchSize > walletScanBatchMax {
+		batchSize = walletScanBatchMax
+	}
+
+	used := make([]*walletScannedAddress, 0)
+
+	unused := 0
+	for seedIndex := uint32(0); unused < gapLimit; {
+
+		//the keys of the wallet addresses are not looked up
+		seedIndexes := make([]uint32, 0, batchSize)
+		publicKeys := make([][]byte, 0, batchSize)
+		for ; len(publicKeys) < batchSize; seedIndex++ {
+
+			_, privateKey, _, err := wallet.GenerateKeys(seedIndex, false)
+			if err != nil {
+				return 0, err
+			}
+
+			privKey, err := addresses.NewPrivateKey(privateKey)
+			if err != nil {
+				return 0, err
+			}
+			publicKey := privKey.GeneratePublicKey()
+
+			if wallet.addressesMap[string(publicKey)] != nil {
+				if len(publicKeys) == 0 {
+					unused = 0
+					continue
+				}
+				break
+			}
+
+			seedIndexes = append(seedIndexes, seedIndex)
+			publicKeys = append(publicKeys, publicKey)
+		}
+
+		regs, keysUsed, err := lookup(publicKeys)
+		if err != nil {
+			return 0, err
+		}
+
+		for i := range publicKeys {
+			if !keysUsed[i] {
+				if unused += 1; unused == gapLimit {
+					break
+				}
+				continue
+			}
+			unused = 0
+			used = append(used, &walletScannedAddress{seedIndexes[i], regs[i]})
+		}
+	}
+
+	seedIndex := wallet.SeedIndex
+
+	for _, scanned := range used {
+
+		staked, spendRequired := false, false
+		if scanned.reg != nil {
+			staked = scanned.reg.Staked
+			spendRequired = len(scanned.reg.SpendPublicKey) > 0
+		}
+
+		wallet.SeedIndex = scanned.seedIndex
+		if _, err := wallet.AddNewAddress(false, "", staked, spendRequired, true); err != nil {
+			return 0, err
+		}
+	}
+
+	if wallet.SeedIndex < seedIndex {
+		wallet.SeedIndex = seedIndex
+		if err := wallet.saveWallet(0, 0, -1, false); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(used), nil
+}
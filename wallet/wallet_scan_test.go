@@ -0,0 +1,57 @@
+package wallet
+
+import (
+	"github.com/stretchr/testify/assert"
+	"pandora-pay/addresses"
+	"pandora-pay/blockchain/data_storage/accounts/account"
+	"pandora-pay/blockchain/data_storage/registrations/registration"
+	"testing"
+)
+
+func TestWalletScanGapLimit(t *testing.T) {
+
+	wallet := createTestWallet(t)
+
+	getPublicKey := func(seedIndex uint32) []byte {
+		_, privateKey, _, err := wallet.GenerateKeys(seedIndex, false)
+		assert.Nil(t, err)
+		privKey, err := addresses.NewPrivateKey(privateKey)
+		assert.Nil(t, err)
+		return privKey.GeneratePublicKey()
+	}
+
+	//without a local chain, the keys are read from the network
+	used := map[string]bool{string(getPublicKey(2)): true, string(getPublicKey(5)): true}
+	requests := 0
+	wallet.SetNetworkAccounts(func(publicKeys [][]byte, asset []byte) ([]*registration.Registration, []*account.Account, error) {
+		requests += 1
+		regs := make([]*registration.Registration, len(publicKeys))
+		for i, publicKey := range publicKeys {
+			if used[string(publicKey)] {
+				regs[i] = registration.NewRegistration(publicKey, 0)
+			}
+		}
+		return regs, make([]*account.Account, len(publicKeys)), nil
+	})
+
+	_, err := wallet.ScanAddresses(0, true)
+	assert.NotNil(t, err)
+
+	found, err := wallet.ScanAddresses(2, true)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, found, "the key 5 is after a gap of 2 unused keys")
+	assert.NotNil(t, wallet.GetWalletAddressByPublicKey(getPublicKey(2), true))
+	assert.Nil(t, wallet.GetWalletAddressByPublicKey(getPublicKey(5), true))
+
+	requests = 0
+	found, err = wallet.ScanAddresses(3, true)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, found)
+	assert.NotNil(t, wallet.GetWalletAddressByPublicKey(getPublicKey(5), true))
+	assert.Equal(t, 3, requests, "the keys are looked up in batches of the gap limit")
+	assert.Equal(t, uint32(6), wallet.SeedIndex, "the new addresses are generated after the scanned ones")
+
+	found, err = wallet.ScanAddresses(3, true)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, found)
+}
@@ -42,7 +42,21 @@ func (wallet *Wallet) saveWalletEntire(lock bool) error {
 		wallet.Lock.RLock()
 		defer wallet.Lock.RUnlock()
 	}
-	return wallet.saveWallet(0, wallet.Count, -1, false)
+
+	if !wallet.Loaded {
+		return errors.New("Can't save your wallet because your stored wallet on the drive was not successfully loaded")
+	}
+
+	//the records are re-encrypted within the same update, so the stored wallet never mixes two encryptions
+	return store.StoreWallet.DB.Update(func(writer store_db_interface.StoreDBTransactionInterface) (err error) {
+		if err = wallet.writeWallet(writer, 0, wallet.Count, -1); err != nil {
+			return
+		}
+		if err = wallet.txsIndexRecords.saveAll(writer, wallet.Encryption, func(id string) any { return wallet.txsIndex[id] }); err != nil {
+			return
+		}
+		return wallet.forgingStatsRecords.saveAll(writer, wallet.Encryption, func(id string) any { return wallet.forgingStats[id] })
+	})
 }
 
 func (wallet *Wallet) saveWallet(start, end, deleteIndex int, lock bool) error {
@@ -52,49 +66,57 @@ func (wallet *Wallet) saveWallet(start, end, deleteIndex int, lock bool) error {
 		defer wallet.Lock.RUnlock()
 	}
 
-	start = generics.Max(0, start)
-	end = generics.Min(end, len(wallet.Addresses))
-
 	if !wallet.Loaded {
 		return errors.New("Can't save your wallet because your stored wallet on the drive was not successfully loaded")
 	}
 
-	return store.StoreWallet.DB.Update(func(writer store_db_interface.StoreDBTransactionInterface) (err error) {
+	return store.StoreWallet.DB.Update(func(writer store_db_interface.StoreDBTransactionInterface) error {
+		return wallet.writeWallet(writer, start, end, deleteIndex)
+	})
+}
+
+func (wallet *Wallet) writeWallet(writer store_db_interface.StoreDBTransactionInterface, start, end, deleteIndex int) (err error) {
+
+	start = generics.Max(0, start)
+	end = generics.Min(end, len(wallet.Addresses))
 
-		var marshal []byte
+	var marshal []byte
 
-		writer.Put("saved", []byte{0})
+	writer.Put("saved", []byte{0})
 
-		if marshal, err = helpers.GetMarshalledDataExcept(wallet.Encryption); err != nil {
-			return
-		}
-		writer.Put("encryption", marshal)
+	if marshal, err = helpers.GetMarshalledDataExcept(wallet.Encryption); err != nil {
+		return
+	}
+	writer.Put("encryption", marshal)
 
-		if marshal, err = helpers.GetMarshalledDataExcept(wallet, "addresses", "encryption"); err != nil {
+	if marshal, err = helpers.GetMarshalledDataExcept(wallet, "addresses", "encryption"); err != nil {
+		return
+	}
+	if marshal, err = wallet.Encryption.encryptData(marshal); err != nil {
+		return
+	}
+
+	writer.Put("wallet", marshal)
+
+	//a new wallet starts without the records of the previous one
+	wallet.txsIndexRecords.saveBounds(writer)
+	wallet.forgingStatsRecords.saveBounds(writer)
+
+	for i := start; i < end; i++ {
+		if marshal, err = msgpack.Marshal(wallet.Addresses[i]); err != nil {
 			return
 		}
 		if marshal, err = wallet.Encryption.encryptData(marshal); err != nil {
 			return
 		}
+		writer.Put("wallet-address-"+strconv.Itoa(i), marshal)
+	}
+	if deleteIndex != -1 {
+		writer.Delete("wallet-address-" + strconv.Itoa(deleteIndex))
+	}
 
-		writer.Put("wallet", marshal)
-
-		for i := start; i < end; i++ {
-			if marshal, err = msgpack.Marshal(wallet.Addresses[i]); err != nil {
-				return
-			}
-			if marshal, err = wallet.Encryption.encryptData(marshal); err != nil {
-				return
-			}
-			writer.Put("wallet-address-"+strconv.Itoa(i), marshal)
-		}
-		if deleteIndex != -1 {
-			writer.Delete("wallet-address-" + strconv.Itoa(deleteIndex))
-		}
-
-		writer.Put("saved", []byte{1})
-		return
-	})
+	writer.Put("saved", []byte{1})
+	return
 }
 
 func (wallet *Wallet) loadWallet(password string, firstTime bool) error {
@@ -170,6 +192,13 @@ func (wallet *Wallet) loadWallet(password string, firstTime bool) error {
 
 			}
 
+			if err = wallet.loadTxsIndex(reader); err != nil {
+				return
+			}
+			if err = wallet.loadForgingStats(reader); err != nil {
+				return
+			}
+
 			wallet.setLoaded(true)
 			if !firstTime {
 				if err = wallet.walletLoaded(firstTime); err != nil {
@@ -0,0 +1,188 @@
+package wallet
+
+import (
+	"bytes"
+	"github.com/vmihailenco/msgpack/v5"
+	"pandora-pay/blockchain/blockchain_types"
+	"pandora-pay/blockchain/transactions/transaction/transaction_type"
+	"pandora-pay/gui"
+	"pandora-pay/recovery"
+	"pandora-pay/store"
+	"pandora-pay/store/store_db/store_db_interface"
+)
+
+//WalletTxIndex is the payments and the messages of the wallet addresses found in a transaction. Every transaction is stored under its own key
+type WalletTxIndex struct {
+	TxHash      []byte           `json:"txHash" msgpack:"txHash"`
+	BlockHeight uint64           `json:"blockHeight" msgpack:"blockHeight"`
+	Payments    []*WalletPayment `json:"payments" msgpack:"payments"`
+	Messages    []*WalletMessage `json:"messages" msgpack:"messages"`
+}
+
+//processTxsIndex decrypts the new Zether transactions and indexes the payments and the memos of the wallet addresses
+func (wallet *Wallet) processTxsIndex() {
+
+	recovery.SafeGo(func() {
+
+		updateNewChainCn := wallet.updateNewChainUpdate.AddListener()
+		defer wallet.updateNewChainUpdate.RemoveChannel(updateNewChainCn)
+
+		updateNewChainTxsCn := wallet.updateNewChainTxs.AddListener()
+		defer wallet.updateNewChainTxs.RemoveChannel(updateNewChainTxsCn)
+
+		var chainHeight uint64
+
+		for {
+			select {
+			case update, ok := <-updateNewChainCn:
+				if !ok {
+					return
+				}
+				chainHeight = update.BlockHeight
+			case txsUpdates, ok := <-updateNewChainTxsCn:
+				if !ok {
+					return
+				}
+				if err := wallet.indexTxs(txsUpdates); err != nil {
+					gui.GUI.Error("Error indexing wallet txs", err)
+				}
+			}
+
+			if err := wallet.confirmPayments(chainHeight); err != nil {
+				gui.GUI.Error("Error confirming wallet payments", err)
+			}
+		}
+
+	})
+
+}
+
+//must be locked before
+func (wallet *Wallet) clearTxsIndex() {
+	wallet.txsIndex = make(map[string]*WalletTxIndex)
+	wallet.payments = make(map[string][]*WalletPayment)
+	wallet.messages = make(map[string][]*WalletMessage)
+	wallet.txsIndexRecords.clear()
+}
+
+//must be locked before
+func (wallet *Wallet) addTxIndex(txIndex *WalletTxIndex) {
+	wallet.txsIndex[string(txIndex.TxHash)] = txIndex
+	for _, payment := range txIndex.Payments {
+		wallet.payments[string(payment.PaymentID)] = append(wallet.payments[string(payment.PaymentID)], payment)
+	}
+	for _, message := range txIndex.Messages {
+		wallet.messages[string(message.PublicKey)] = append(wallet.messages[string(message.PublicKey)], message)
+	}
+}
+
+//must be locked before
+func (wallet *Wallet) removeTxIndex(txHash []byte) bool {
+
+	txIndex := wallet.txsIndex[string(txHash)]
+	if txIndex == nil {
+		return false
+	}
+	delete(wallet.txsIndex, string(txHash))
+
+	for _, payment := range txIndex.Payments {
+		key := string(payment.PaymentID)
+		if wallet.payments[key] = removeTxHash(wallet.payments[key], txHash, func(payment *WalletPayment) []byte { return payment.TxHash }); len(wallet.payments[key]) == 0 {
+			delete(wallet.payments, key)
+		}
+	}
+	for _, message := range txIndex.Messages {
+		key := string(message.PublicKey)
+		if wallet.messages[key] = removeTxHash(wallet.messages[key], txHash, func(message *WalletMessage) []byte { return message.TxHash }); len(wallet.messages[key]) == 0 {
+			delete(wallet.messages, key)
+		}
+	}
+	return true
+}
+
+func removeTxHash[T any](list []T, txHash []byte, getTxHash func(T) []byte) []T {
+	newList := make([]T, 0, len(list))
+	for _, element := range list {
+		if !bytes.Equal(getTxHash(element), txHash) {
+			newList = append(newList, element)
+		}
+	}
+	return newList
+}
+
+//must be locked before. saveTxsIndex stores only the changed transactions and prunes the oldest ones
+func (wallet *Wallet) saveTxsIndex(put []*WalletTxIndex, remove [][]byte) error {
+	return store.StoreWallet.DB.Update(func(writer store_db_interface.StoreDBTransactionInterface) (err error) {
+
+		for _, txHash := range remove {
+			wallet.txsIndexRecords.remove(writer, string(txHash))
+		}
+
+		for _, txIndex := range put {
+			var pruned []string
+			if pruned, err = wallet.txsIndexRecords.put(writer, wallet.Encryption, string(txIndex.TxHash), txIndex); err != nil {
+				return
+			}
+			for _, txHash := range pruned {
+				wallet.removeTxIndex([]byte(txHash))
+			}
+		}
+
+		return
+	})
+}
+
+//must be locked before
+func (wallet *Wallet) loadTxsIndex(reader store_db_interface.StoreDBTransactionInterface) error {
+	return wallet.txsIndexRecords.load(reader, wallet.Encryption, func(data []byte) (string, error) {
+		txIndex := &WalletTxIndex{}
+		if err := msgpack.Unmarshal(data, txIndex); err != nil {
+			return "", err
+		}
+		wallet.addTxIndex(txIndex)
+		return string(txIndex.TxHash), nil
+	})
+}
+
+func (wallet *Wallet) indexTxs(txsUpdates []*blockchain_types.BlockchainTransactionUpdate) (err error) {
+
+	//decrypting is done without holding the lock
+	inserted := make(map[string]*WalletTxIndex)
+	for _, txUpdate := range txsUpdates {
+		if txUpdate.Inserted && txUpdate.Tx != nil && txUpdate.Tx.Version == transaction_type.TX_ZETHER {
+			var decrypted *DecryptedTx
+			if decrypted, err = wallet.DecryptTx(txUpdate.Tx, nil); err != nil {
+				return
+			}
+			txIndex := &WalletTxIndex{txUpdate.TxHash, txUpdate.BlockHeight, getPaymentsFromTx(txUpdate, decrypted), getMessagesFromTx(txUpdate, decrypted)}
+			if len(txIndex.Payments) > 0 || len(txIndex.Messages) > 0 {
+				inserted[string(txUpdate.TxHash)] = txIndex
+			}
+		}
+	}
+
+	wallet.Lock.Lock()
+	defer wallet.Lock.Unlock()
+
+	if !wallet.Loaded {
+		return
+	}
+
+	put := []*WalletTxIndex{}
+	remove := [][]byte{}
+	for _, txUpdate := range txsUpdates {
+		if wallet.removeTxIndex(txUpdate.TxHash) {
+			remove = append(remove, txUpdate.TxHash)
+		}
+		if txUpdate.Inserted && inserted[string(txUpdate.TxHash)] != nil {
+			txIndex := inserted[string(txUpdate.TxHash)]
+			wallet.addTxIndex(txIndex)
+			put = append(put, txIndex)
+		}
+	}
+
+	if len(put) > 0 || len(remove) > 0 {
+		return wallet.saveTxsIndex(put, remove)
+	}
+	return
+}